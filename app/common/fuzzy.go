@@ -0,0 +1,59 @@
+package common
+
+// DefaultFuzziness is the edit distance used by fuzzy searches when the config does not
+// set one.
+const DefaultFuzziness = 2
+
+// MaxEditDistance returns the edit distance allowed by fuzzy searches for a word of n
+// letters. Short words are allowed fewer edits, since almost any two short words are within
+// a distance of two.
+func MaxEditDistance(fuzziness int, n int) int {
+	if fuzziness <= 0 {
+		fuzziness = DefaultFuzziness
+	}
+	return min(fuzziness, n/3)
+}
+
+// HalfEditDistance returns the edit distance between a and b in half edits, or limit+1 if
+// it exceeds limit. Substituting a letter by a similar letter is half an edit, and other
+// insertions, deletions and substitutions are whole edits. If similar is symmetric, this is
+// a metric.
+func HalfEditDistance(a, b []rune, similar func(x, y rune) bool, limit int) int {
+	if 2*absInt(len(a)-len(b)) > limit {
+		return limit + 1
+	}
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = 2 * j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = 2 * i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 2
+			if a[i-1] == b[j-1] {
+				cost = 0
+			} else if similar(a[i-1], b[j-1]) {
+				cost = 1
+			}
+			curr[j] = min(prev[j]+2, curr[j-1]+2, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	if prev[len(b)] > limit {
+		return limit + 1
+	}
+	return prev[len(b)]
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -1,12 +1,12 @@
 package common
 
 type GrammaticalTagStyle struct {
-	ReadableName    string
-	SanskritName    string
-	BackgroundColor string
-	Color           string
-	UnderlineStyle  string // "dotted", "dashed", "none"
-	BorderStyle     string // "primary", "secondary", etc. for border color
+	ReadableName    string `json:"readable_name"`
+	SanskritName    string `json:"sanskrit_name"`
+	BackgroundColor string `json:"background_color"`
+	Color           string `json:"color"`
+	UnderlineStyle  string `json:"underline_style"` // "dotted", "dashed", "none"
+	BorderStyle     string `json:"border_style"`    // "primary", "secondary", etc. for border color
 }
 
 var GrammaticalTags = map[string]GrammaticalTagStyle{
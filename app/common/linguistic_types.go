@@ -11,6 +11,7 @@ type Language string
 const (
 	Sanskrit Language = "sanskrit"
 	English  Language = "english"
+	German   Language = "german"
 )
 
 // Accent marks will be ignored for search
@@ -32,6 +33,17 @@ const (
 	SearchFuzzy        SearchMode = "fuzzy"
 	SearchASCII        SearchMode = "ascii"
 	SearchTranslations SearchMode = "translations"
+	// SearchQuery uses the advanced query language with boolean operators and field prefixes.
+	SearchQuery SearchMode = "query"
+	// SearchMorph matches glossing tokens by lemma and grammatical features.
+	SearchMorph SearchMode = "morph"
+	// SearchCQL matches sequences of glossing tokens with a CQL (CQP) style pattern.
+	SearchCQL SearchMode = "cql"
+	// SearchAccent matches words by their accented surface, distinguishing differently
+	// accented forms.
+	SearchAccent SearchMode = "accent"
+	// SearchEnglish finds dictionary words by the English words of their meanings.
+	SearchEnglish SearchMode = "english"
 )
 
 func PathToSortString(path []int) string {
@@ -0,0 +1,41 @@
+package common
+
+// SearchPageSize is the number of results on one page of scripture or dictionary search.
+const SearchPageSize = 100
+
+// MaxPage is the last page which can be requested, which keeps offsets small.
+const MaxPage = 1000
+
+// Pagination describes a page of search results.
+type Pagination struct {
+	// Page is 1-based.
+	Page     int `json:"page"`
+	PageSize int `json:"page_size"`
+	// Total is the number of results across all pages.
+	Total int `json:"total"`
+}
+
+// NewPagination returns the pagination for a 1-based page number, clamped between 1 and MaxPage.
+func NewPagination(page int) Pagination {
+	return Pagination{Page: min(max(page, 1), MaxPage), PageSize: SearchPageSize}
+}
+
+// Offset returns the number of results before this page.
+func (p Pagination) Offset() int {
+	return (p.Page - 1) * p.PageSize
+}
+
+func (p Pagination) TotalPages() int {
+	if p.PageSize == 0 {
+		return 0
+	}
+	return (p.Total + p.PageSize - 1) / p.PageSize
+}
+
+func (p Pagination) HasPrevious() bool {
+	return p.Page > 1
+}
+
+func (p Pagination) HasNext() bool {
+	return p.Page < p.TotalPages()
+}
@@ -14,6 +14,26 @@ func FoldAccents(s string) string {
 	return replacer.Replace(s)
 }
 
+// accentDecomposer writes accented vowels as the vowel followed by a combining acute
+// (udatta) or grave (svarita), and folds the other variant spellings like FoldAccents.
+var accentDecomposer = strings.NewReplacer(
+	"\u00e1", "a\u0301", "\u00ed", "i\u0301", "\u00fa", "u\u0301", "\u00e9", "e\u0301",
+	"\u00f3", "o\u0301", "\u0155", "r\u0301", "\u00e0", "a\u0300", "\u00ec", "i\u0300",
+	"\u00f9", "u\u0300", "\u00e8", "e\u0300", "\u00f2", "o\u0300", "\u1e41", "\u1e43",
+)
+
+// accentMarkOrder puts the ring below before the accent, and writes syllabic r as one letter.
+var accentMarkOrder = strings.NewReplacer(
+	"r\u0301\u0325", "\u1e5b\u0301", "r\u0300\u0325", "\u1e5b\u0300", "r\u0325", "\u1e5b",
+)
+
+// NormalizeAccents is like FoldAccents, but keeps the accents as combining marks, so that
+// differently encoded accented forms compare equal, eg: the precomposed IAST vowels and
+// the forms converted from SLP1.
+func NormalizeAccents(s string) string {
+	return accentMarkOrder.Replace(accentDecomposer.Replace(strings.ToLower(s)))
+}
+
 func NormalizeLemma(lemma string) string {
 	lemma = strings.TrimSuffix(lemma, "-")
 	lemma = strings.TrimRight(lemma, "ⁱ")
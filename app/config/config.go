@@ -24,6 +24,44 @@ type ScriptureDefn struct {
 	DataFile             string                `json:"data_file"`
 	NotesFile            string                `json:"notes_file,omitempty"`
 	NotesBy              string                `json:"notes_by,omitempty"`
+	// Named sets of verses which can be used to scope searches and statistics.
+	VerseSets []VerseSetDefn `json:"verse_sets,omitempty"`
+	// Abbreviations by which dictionaries cite this scripture, eg: "RV." in Monier-Williams.
+	CitationAbbreviations []string `json:"citation_abbreviations,omitempty"`
+	// Dictionaries to look the words up in, in order of preference, eg: a Vedic lexicon before
+	// Monier-Williams. Defaults to the default dictionary.
+	Dictionaries     []string         `json:"dictionaries,omitempty"`
+	DictionaryLookup DictionaryLookup `json:"dictionary_lookup,omitempty"`
+}
+
+// DictionaryLookup is the policy of looking a word up in the dictionaries of a scripture.
+type DictionaryLookup string
+
+const (
+	// LookupMerge shows the meanings from every dictionary having the word. It is the default.
+	LookupMerge DictionaryLookup = "merge"
+	// LookupFallback shows the meanings from the first dictionary having the word.
+	LookupFallback DictionaryLookup = "fallback"
+)
+
+// VerseSetDefn is a named group of hierarchy subtrees or ranges, eg: the family
+// books of the Rig Veda are "2-7".
+type VerseSetDefn struct {
+	// A name slug used in URLs. Eg: family-books
+	Name         string `json:"name"`
+	ReadableName string `json:"readable_name"`
+	// Paths or ranges of paths, in the syntax accepted by search scopes. Eg: "1.1-1.50".
+	Paths []string `json:"paths"`
+}
+
+// GetVerseSet returns the verse set with given name or nil.
+func (s *ScriptureDefn) GetVerseSet(name string) *VerseSetDefn {
+	for i := range s.VerseSets {
+		if s.VerseSets[i].Name == name {
+			return &s.VerseSets[i]
+		}
+	}
+	return nil
 }
 
 type DictDefn struct {
@@ -82,6 +120,23 @@ func (c *DheeConfig) GetDictByName(name string) *DictDefn {
 	return nil
 }
 
+// LookupDictionaries returns the dictionaries to look the words of a scripture up in, in
+// order of preference. Dictionaries which are not configured are skipped.
+func (c *DheeConfig) LookupDictionaries(s *ScriptureDefn) []string {
+	var names []string
+	if s != nil {
+		for _, name := range s.Dictionaries {
+			if c.GetDictByName(name) != nil {
+				names = append(names, name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return []string{c.DefaultDict}
+	}
+	return names
+}
+
 func (c *DheeConfig) DictNameToId(dictName string) int {
 	for idx, d := range c.Dictionaries {
 		if d.Name == dictName {
@@ -0,0 +1,123 @@
+package dictionary
+
+import (
+	"sort"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+)
+
+// slp1Confusions lists pairs of SLP1 letters which are often confused when a word is half
+// remembered or typed without diacritics: vowel lengths, dentals and retroflexes, sibilants
+// and nasals.
+var slp1Confusions = []string{
+	"aA", "iI", "uU", "fF", "xX", "fr",
+	"tw", "TW", "dq", "DQ", "nR", "nN", "nY", "nM", "mM",
+	"sS", "sz", "Sz",
+}
+
+var slp1Confusable = func() map[[2]rune]bool {
+	m := make(map[[2]rune]bool)
+	for _, pair := range slp1Confusions {
+		a, b := rune(pair[0]), rune(pair[1])
+		m[[2]rune{a, b}] = true
+		m[[2]rune{b, a}] = true
+	}
+	return m
+}()
+
+func slp1Similar(a, b rune) bool {
+	return slp1Confusable[[2]rune{a, b}]
+}
+
+// slp1Distance returns the distance between SLP1 words in half edits, where confusable
+// letters are half an edit apart. It is a metric, as required by bkTree.
+func slp1Distance(a, b []rune, limit int) int {
+	return common.HalfEditDistance(a, b, slp1Similar, limit)
+}
+
+// bkTree indexes words for lookups of all words within a distance of a query, see
+// https://en.wikipedia.org/wiki/BK-tree. Children of a node are keyed by their distance
+// from it, so that the triangle inequality bounds the subtrees to visit.
+type bkTree struct {
+	root *bkNode
+	size int
+}
+
+type bkNode struct {
+	word     []rune
+	children map[int]*bkNode
+	// maxChild is the largest distance of a child, which bounds the distances worth computing.
+	maxChild int
+}
+
+// bkMatch is a word found by bkTree.Search, with its distance in half edits.
+type bkMatch struct {
+	Word     string
+	Distance int
+}
+
+// unboundedDistance is a limit larger than the distance between any two words.
+const unboundedDistance = 1 << 30
+
+func (t *bkTree) Add(word string) {
+	runes := []rune(word)
+	if t.root == nil {
+		t.root = &bkNode{word: runes}
+		t.size++
+		return
+	}
+	node := t.root
+	for {
+		d := slp1Distance(runes, node.word, unboundedDistance)
+		if d == 0 {
+			return
+		}
+		child, ok := node.children[d]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[d] = &bkNode{word: runes}
+			node.maxChild = max(node.maxChild, d)
+			t.size++
+			return
+		}
+		node = child
+	}
+}
+
+// Search returns the words within radius half edits of word, closest first, and shorter
+// and alphabetically earlier words first among equally close words.
+func (t *bkTree) Search(word string, radius int) []bkMatch {
+	if t.root == nil {
+		return nil
+	}
+	runes := []rune(word)
+	var matches []bkMatch
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		// beyond this limit, neither the node nor any child can match
+		d := slp1Distance(runes, node.word, max(radius, node.maxChild+radius))
+		if d <= radius {
+			matches = append(matches, bkMatch{Word: string(node.word), Distance: d})
+		}
+		for cd, child := range node.children {
+			if cd >= d-radius && cd <= d+radius {
+				stack = append(stack, child)
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if len(a.Word) != len(b.Word) {
+			return len(a.Word) < len(b.Word)
+		}
+		return a.Word < b.Word
+	})
+	return matches
+}
@@ -0,0 +1,41 @@
+package dictionary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlp1Distance(t *testing.T) {
+	dist := func(a, b string) int {
+		return slp1Distance([]rune(a), []rune(b), unboundedDistance)
+	}
+	assert.Equal(t, 0, dist("agni", "agni"))
+	// vowel length, retroflexes and sibilants are half an edit
+	assert.Equal(t, 1, dist("agni", "agnI"))
+	assert.Equal(t, 1, dist("vftra", "vrtra"))
+	assert.Equal(t, 1, dist("kfzRa", "kfzna"))
+	assert.Equal(t, 2, dist("Siva", "siva")+dist("vizRu", "visRu"))
+	assert.Equal(t, 2, dist("agni", "agnis"))
+	assert.Equal(t, 4, dist("agni", "agra"))
+}
+
+func TestBKTreeSearch(t *testing.T) {
+	tree := &bkTree{}
+	for _, w := range []string{"agni", "agnI", "agnis", "agra", "aNga", "indra", "kfzRa", "kfzna", "agni"} {
+		tree.Add(w)
+	}
+	assert.Equal(t, 8, tree.size)
+
+	assert.Equal(t, []bkMatch{
+		{Word: "agni", Distance: 0},
+		{Word: "agnI", Distance: 1},
+		{Word: "agnis", Distance: 2},
+	}, tree.Search("agni", 2))
+	assert.Equal(t, []bkMatch{
+		{Word: "kfzna", Distance: 2},
+		{Word: "kfzRa", Distance: 3},
+	}, tree.Search("krsna", 3))
+	assert.Empty(t, tree.Search("soma", 2))
+	assert.Empty(t, (&bkTree{}).Search("agni", 2))
+}
@@ -0,0 +1,119 @@
+package dictionary
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/config"
+)
+
+// Citation is a literature reference of a meaning resolved to a passage of a configured
+// scripture.
+type Citation struct {
+	// Ref is the reference as printed in the dictionary, eg: "RV. i, 32, 1".
+	Ref       string `json:"ref"`
+	Scripture string `json:"scripture"`
+	// Path is the cited passage, which may be shorter than the scripture hierarchy when a
+	// whole hymn or book is cited.
+	Path []int `json:"path"`
+}
+
+// CitingWord is a dictionary word with a meaning citing a passage.
+type CitingWord struct {
+	DictName string `json:"dict_name"`
+	Word     string `json:"word"`
+	IAST     string `json:"iast"`
+	Ref      string `json:"ref"`
+}
+
+// parseRoman parses a lowercase roman numeral like xiv.
+func parseRoman(s string) (int, bool) {
+	values := map[byte]int{'i': 1, 'v': 5, 'x': 10, 'l': 50, 'c': 100}
+	total := 0
+	for i := 0; i < len(s); i++ {
+		v, ok := values[s[i]]
+		if !ok {
+			return 0, false
+		}
+		if i+1 < len(s) && values[s[i+1]] > v {
+			total -= v
+		} else {
+			total += v
+		}
+	}
+	return total, s != ""
+}
+
+// parseCitationNumber parses a part of a reference like "32" or "x", ignoring trailing
+// remarks like "ff." or "Sch.".
+func parseCitationNumber(part string) (int, bool) {
+	fields := strings.Fields(part)
+	if len(fields) == 0 {
+		return 0, false
+	}
+	num := strings.TrimSuffix(fields[0], ".")
+	if n, err := strconv.Atoi(num); err == nil && n > 0 {
+		return n, true
+	}
+	return parseRoman(num)
+}
+
+// parseCitationPath parses the numbers of a reference like "i, 32, 1" into a path of at most
+// depth levels.
+func parseCitationPath(s string, depth int) []int {
+	var path []int
+	for _, part := range strings.Split(s, ",") {
+		n, ok := parseCitationNumber(part)
+		if !ok || len(path) == depth {
+			break
+		}
+		path = append(path, n)
+	}
+	return path
+}
+
+// citedScripture returns the scripture cited by an abbreviation at the start of ref, and the
+// rest of ref.
+func citedScripture(ref string, scriptures []config.ScriptureDefn) (*config.ScriptureDefn, string) {
+	var found *config.ScriptureDefn
+	var rest string
+	longest := 0
+	for i := range scriptures {
+		for _, abbr := range scriptures[i].CitationAbbreviations {
+			if len(abbr) > longest && strings.HasPrefix(ref, abbr) {
+				found, rest, longest = &scriptures[i], ref[len(abbr):], len(abbr)
+			}
+		}
+	}
+	return found, rest
+}
+
+// ResolveCitations resolves the literature references of a meaning against the citation
+// abbreviations of the scriptures. A reference starting with a number, like "x, 90, 1",
+// continues the work of the previous reference, as in the Monier-Williams dictionary.
+func ResolveCitations(litRefs []string, scriptures []config.ScriptureDefn) []Citation {
+	var citations []Citation
+	var last *config.ScriptureDefn
+	for _, ref := range litRefs {
+		ref = strings.TrimSpace(ref)
+		scri, rest := citedScripture(ref, scriptures)
+		if scri == nil {
+			if _, ok := parseCitationNumber(strings.Split(ref, ",")[0]); !ok {
+				// another work, which later continuations refer to
+				last = nil
+				continue
+			}
+			scri, rest = last, ref
+		}
+		if scri == nil {
+			continue
+		}
+		last = scri
+		path := parseCitationPath(rest, len(scri.Hierarchy))
+		if len(path) == 0 {
+			continue
+		}
+		citations = append(citations, Citation{Ref: ref, Scripture: scri.Name, Path: path})
+	}
+	return citations
+}
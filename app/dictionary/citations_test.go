@@ -0,0 +1,32 @@
+package dictionary
+
+import (
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveCitations(t *testing.T) {
+	scriptures := []config.ScriptureDefn{
+		{Name: "rigveda", Hierarchy: []string{"Mandala", "Sukta", "Verse"}, CitationAbbreviations: []string{"RV."}},
+	}
+	refs := []string{"RV. i, 32, 1", "x, 90, 12 ff.", "RV.", "iv, 2", "AV. iv, 2, 1", "vi, 3", "L.", "RV. ix, 1, 1, 1", "RVPrāt. i, 1", "ib."}
+	assert.Equal(t, []Citation{
+		{Ref: "RV. i, 32, 1", Scripture: "rigveda", Path: []int{1, 32, 1}},
+		{Ref: "x, 90, 12 ff.", Scripture: "rigveda", Path: []int{10, 90, 12}},
+		{Ref: "iv, 2", Scripture: "rigveda", Path: []int{4, 2}},
+		{Ref: "RV. ix, 1, 1, 1", Scripture: "rigveda", Path: []int{9, 1, 1}},
+	}, ResolveCitations(refs, scriptures))
+	assert.Empty(t, ResolveCitations([]string{"RV. i, 1, 1"}, nil))
+}
+
+func TestParseRoman(t *testing.T) {
+	for s, want := range map[string]int{"i": 1, "iv": 4, "ix": 9, "x": 10, "xiv": 14, "lxxx": 80} {
+		n, ok := parseRoman(s)
+		assert.True(t, ok, s)
+		assert.Equal(t, want, n, s)
+	}
+	_, ok := parseRoman("ib")
+	assert.False(t, ok)
+}
@@ -3,16 +3,22 @@ package dictionary
 import (
 	"context"
 	"fmt"
+	"html"
 	"log/slog"
 	"net/http"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/mahesh-hegde/dhee/app/common"
 	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/mahesh-hegde/dhee/app/inflection"
 	"github.com/mahesh-hegde/dhee/app/transliteration"
 )
 
 type DictionaryService struct {
 	store          DictStore
+	inflections    *inflection.InflectionService
 	conf           *config.DheeConfig
 	transliterator *transliteration.Transliterator
 }
@@ -42,7 +48,79 @@ func (s *DictionaryService) GetEntries(ctx context.Context, dictionaryName strin
 		return DictionaryWordResponse{}, err
 	}
 
-	return DictionaryWordResponse{Words: results, Dictionary: s.conf.GetDictByName(dictionaryName)}, nil
+	resp := DictionaryWordResponse{Words: results, Dictionary: s.conf.GetDictByName(dictionaryName)}
+	for word, entry := range results {
+		// the entries are still useful without the tables
+		tables, err := s.inflections.Declensions(ctx, word, nominalStems(entry))
+		if err != nil {
+			slog.Warn("failed to decline word", "word", word, "err", err)
+		} else if len(tables) > 0 {
+			if resp.Declensions == nil {
+				resp.Declensions = make(map[string][]inflection.DeclensionTable)
+			}
+			resp.Declensions[word] = tables
+		}
+
+		conjugations, err := s.inflections.Conjugations(ctx, verbRoots(entry))
+		if err != nil {
+			slog.Warn("failed to conjugate word", "word", word, "err", err)
+		} else if len(conjugations) > 0 {
+			if resp.Conjugations == nil {
+				resp.Conjugations = make(map[string][]inflection.ConjugationTable)
+			}
+			resp.Conjugations[word] = conjugations
+		}
+	}
+	return resp, nil
+}
+
+// verbRoots returns the roots of the verb meanings of an entry. The root of a prefixed verb
+// is the last part of its parse. Meanings without a pada are skipped if another meaning
+// gives one for the same root and class.
+func verbRoots(entry DictionaryEntry) []inflection.VerbRoot {
+	var roots []inflection.VerbRoot
+	withPada := make(map[string]bool)
+	for _, m := range entry.Meanings {
+		v := m.Verb
+		if v.VerbType == "" && v.VerbClass == 0 {
+			continue
+		}
+		r := inflection.VerbRoot{Root: entry.Word, Class: v.VerbClass, Pada: v.Pada}
+		if n := len(v.Parse); n > 1 {
+			r.Root = v.Parse[n-1]
+			r.Prefix = strings.Join(v.Parse[:n-1], "")
+		}
+		if r.Pada != "" {
+			withPada[fmt.Sprintf("%s %d", r.Root, r.Class)] = true
+		}
+		roots = append(roots, r)
+	}
+	return slices.DeleteFunc(roots, func(r inflection.VerbRoot) bool {
+		return r.Pada == "" && withPada[fmt.Sprintf("%s %d", r.Root, r.Class)]
+	})
+}
+
+// nominalStems returns the stems and lexical genders of the meanings of an entry. The stem is
+// the headword unless the meaning gives one.
+func nominalStems(entry DictionaryEntry) []inflection.NominalStem {
+	var stems []inflection.NominalStem
+	for _, m := range entry.Meanings {
+		gender := m.LexicalGender
+		if gender == "" {
+			gender = m.LexCat.LexID
+		}
+		if gender == "" {
+			continue
+		}
+		stem := entry.Word
+		if m.Stem != "" {
+			stem = m.Stem
+		} else if len(m.LexCat.Stem) > 1 && strings.HasPrefix(entry.Word, m.LexCat.Stem[:1]) {
+			stem = m.LexCat.Stem
+		}
+		stems = append(stems, inflection.NominalStem{Stem: stem, LexicalGender: gender})
+	}
+	return stems
 }
 
 func (s *DictionaryService) Suggest(ctx context.Context, dictName string, partialWord string, tl common.Transliteration) (Suggestions, error) {
@@ -70,7 +148,7 @@ func (s *DictionaryService) Search(ctx context.Context, dictionaryName string, s
 		return SearchResults{}, common.NewUserVisibleError(http.StatusNotFound, fmt.Sprintf("No such dictionary named %q", dictionaryName))
 	}
 
-	if searchParams.Mode != common.SearchTranslations {
+	if searchParams.Mode != common.SearchTranslations && searchParams.Mode != common.SearchEnglish {
 		finalQuery, err := s.transliterator.Convert(searchParams.Query, searchParams.Tl, common.TlSLP1)
 		if err != nil {
 			slog.Warn("transliteration failed for search", "query", searchParams.Query, "err", err)
@@ -79,6 +157,19 @@ func (s *DictionaryService) Search(ctx context.Context, dictionaryName string, s
 		searchParams.Query = finalQuery
 	}
 
+	var re *regexp.Regexp
+	if searchParams.Mode == common.SearchRegex {
+		pattern := searchParams.Query
+		if searchParams.TextQuery != "" {
+			pattern = searchParams.TextQuery
+		}
+		var err error
+		re, err = regexp.Compile("(?s)" + pattern)
+		if err != nil {
+			return SearchResults{}, common.NewUserVisibleError(http.StatusBadRequest, fmt.Sprintf("invalid regular expression: %v", err))
+		}
+	}
+
 	res, err := s.store.Search(ctx, dictionaryName, searchParams)
 	if err != nil {
 		return SearchResults{}, err
@@ -89,20 +180,123 @@ func (s *DictionaryService) Search(ctx context.Context, dictionaryName string, s
 			slog.Debug("error converting searched entry to devanagari", "word", itm.Word, "err", err)
 		}
 		res.Items[idx].Nagari = nagari
+		if re == nil {
+			continue
+		}
+		if searchParams.TextQuery != "" {
+			res.Items[idx].PreviewsHl = make([]string, len(itm.Previews))
+			for i, preview := range itm.Previews {
+				res.Items[idx].PreviewsHl[i] = highlightMatches(re, preview, nil)
+			}
+		} else {
+			res.Items[idx].IASTHl = s.highlightWord(re, itm.Word)
+		}
+	}
+	if len(res.Items) == 0 && searchParams.TextQuery == "" && searchParams.Page <= 1 &&
+		(searchParams.Mode == common.SearchExact || searchParams.Mode == common.SearchPrefix) {
+		// the query may be an inflected form of a headword
+		res.Analyses, err = s.inflections.Analyze(ctx, searchParams.Query, lexicon{store: s.store, dictName: dictionaryName})
+		if err != nil {
+			slog.Warn("failed to analyze query", "query", searchParams.Query, "err", err)
+		}
 	}
 	res.DictionaryReadableName = dict.ReadableName
 	res.Params = searchParams
 	return res, nil
 }
 
+// Analyze returns the lemmas of the dictionary an inflected form can be made from, with the
+// tags of the form.
+func (s *DictionaryService) Analyze(ctx context.Context, dictionaryName string, params AnalysisParams) (AnalysisResults, error) {
+	if s.conf.GetDictByName(dictionaryName) == nil {
+		return AnalysisResults{}, common.NewUserVisibleError(http.StatusNotFound, fmt.Sprintf("No such dictionary named %q", dictionaryName))
+	}
+	word := params.Word
+	switch params.Tl {
+	case common.TlIAST:
+		word = common.FoldAccents(word)
+	case common.TlNagari:
+		word = s.transliterator.FoldDevanagariAccents(word)
+	}
+	word, err := s.transliterator.Convert(strings.TrimSpace(word), params.Tl, common.TlSLP1)
+	if err != nil {
+		return AnalysisResults{}, common.NewUserVisibleError(http.StatusBadRequest, fmt.Sprintf("could not transliterate %q", params.Word))
+	}
+	analyses, err := s.inflections.Analyze(ctx, word, lexicon{store: s.store, dictName: dictionaryName})
+	if err != nil {
+		return AnalysisResults{}, err
+	}
+	return AnalysisResults{DictionaryName: dictionaryName, Params: params, Word: word, Analyses: analyses}, nil
+}
+
+// lexicon finds the candidate stems and roots of the analyzer among the headwords of a
+// dictionary.
+type lexicon struct {
+	store    DictStore
+	dictName string
+}
+
+func (l lexicon) Inflectables(ctx context.Context, words []string) (map[string]inflection.Inflectable, error) {
+	entries, err := l.store.Get(ctx, l.dictName, words)
+	if err != nil {
+		return nil, err
+	}
+	inflectables := make(map[string]inflection.Inflectable, len(entries))
+	for word, entry := range entries {
+		inflectables[word] = inflection.Inflectable{Stems: nominalStems(entry), Roots: verbRoots(entry)}
+	}
+	return inflectables, nil
+}
+
+// highlightWord returns the HTML escaped IAST form of an SLP1 word, with the matches of re in
+// the SLP1 word emphasized. Since SLP1 has a letter per sound, the matched and unmatched
+// parts can be transliterated separately.
+func (s *DictionaryService) highlightWord(re *regexp.Regexp, word string) string {
+	return highlightMatches(re, word, func(part string) string {
+		iast, err := s.transliterator.Convert(part, common.TlSLP1, common.TlIAST)
+		if err != nil {
+			return part
+		}
+		return iast
+	})
+}
+
+// highlightMatches HTML escapes text, wrapping the non-empty matches of re in <em> tags. The
+// matched and unmatched parts are passed through convert first, unless it is nil.
+func highlightMatches(re *regexp.Regexp, text string, convert func(string) string) string {
+	var b strings.Builder
+	write := func(part string, matched bool) {
+		if part == "" {
+			return
+		}
+		if convert != nil {
+			part = convert(part)
+		}
+		if matched {
+			b.WriteString("<em>" + html.EscapeString(part) + "</em>")
+		} else {
+			b.WriteString(html.EscapeString(part))
+		}
+	}
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		write(text[last:loc[0]], false)
+		write(text[loc[0]:loc[1]], true)
+		last = loc[1]
+	}
+	write(text[last:], false)
+	return b.String()
+}
+
 func (s *DictionaryService) Related(ctx context.Context, dictName string, word string) (SearchResults, error) {
 	// Assuming the input 'word' for related is already in SLP1 from a dictionary entry.
 	return s.store.Related(ctx, dictName, word)
 }
 
-func NewDictionaryService(store DictStore, conf *config.DheeConfig, transliterator *transliteration.Transliterator) *DictionaryService {
+func NewDictionaryService(store DictStore, formStore inflection.FormStore, conf *config.DheeConfig, transliterator *transliteration.Transliterator) *DictionaryService {
 	return &DictionaryService{
 		store:          store,
+		inflections:    inflection.NewInflectionService(formStore, conf, transliterator),
 		conf:           conf,
 		transliterator: transliterator,
 	}
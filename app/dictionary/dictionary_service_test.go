@@ -0,0 +1,26 @@
+package dictionary
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/transliteration"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightWord(t *testing.T) {
+	tl, err := transliteration.NewTransliterator(transliteration.TlOptions{})
+	assert.NoError(t, err)
+	s := &DictionaryService{transliterator: tl}
+
+	assert.Equal(t, "rām<em>āyaṇa</em>", s.highlightWord(regexp.MustCompile("Aya.a$"), "rAmAyaRa"))
+	assert.Equal(t, "<em>a</em>gn<em>i</em>", s.highlightWord(regexp.MustCompile("[ai]"), "agni"))
+	assert.Equal(t, "kṛṣṇa", s.highlightWord(regexp.MustCompile("x*"), "kfzRa"))
+}
+
+func TestHighlightMatches(t *testing.T) {
+	assert.Equal(t, "R. &lt;<em>am</em>&gt; &amp; s<em>am</em>a", highlightMatches(regexp.MustCompile("am"), "R. <am> & sama", nil))
+	assert.Equal(t, "a &amp; b", highlightMatches(regexp.MustCompile("amp|lt"), "a & b", nil))
+	assert.Equal(t, "<em>&lt;</em>i&gt;", highlightMatches(regexp.MustCompile("<"), "<i>", nil))
+	assert.Equal(t, "<em>aa</em>b<em>a</em>", highlightMatches(regexp.MustCompile("a*"), "aaba", nil))
+}
@@ -41,6 +41,10 @@ type DictStore interface {
 
 	// Related returns the entries starting with `{word}-` for now.
 	Related(ctx context.Context, dictName string, word string) (SearchResults, error)
+
+	// Citing returns the words of all dictionaries with meanings citing the given readable
+	// paths of a scripture, by path.
+	Citing(ctx context.Context, scripture string, paths []string) (map[string][]CitingWord, error)
 }
 
 func prepareDictEntryForDb(e *DictionaryEntry) DictionaryEntryInDB {
@@ -0,0 +1,45 @@
+package dictionary
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// apteParser parses Apte's Practical Sanskrit-English dictionary (1890), whose senses are
+// in numbered divisions.
+type apteParser struct{}
+
+func (apteParser) Name() string {
+	return "ap90"
+}
+
+func (apteParser) ParseEntry(xml CologneXmlEntry, lastPageNum string) (DictionaryEntry, Meaning) {
+	entry, meaning := newCologneEntry(xml, lastPageNum)
+	parseBody(xml.Body.Content, &meaning, handleApteElement)
+	return entry, meaning
+}
+
+func handleApteElement(elem xml.StartElement, decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning, depth int) error {
+	switch elem.Name.Local {
+	case "s":
+		return handleS(elem, decoder, plainText, meaning)
+	case "ls":
+		return handleLs(elem, decoder, plainText, meaning)
+	case "ab":
+		return handleAb(elem, decoder, plainText)
+	case "hom":
+		return handleHom(decoder, plainText, meaning)
+	case "lex":
+		return handleLex(decoder, plainText, meaning)
+	case "i", "b", "lang", "etym":
+		return handleStripTag(decoder, plainText)
+	case "pb", "pc":
+		return handlePcol(decoder, plainText)
+	case "div":
+		// numbered senses
+		plainText.WriteRune(' ')
+		return walkXMLTree(decoder, plainText, meaning, depth+1, handleApteElement)
+	default:
+		return walkXMLTree(decoder, plainText, meaning, depth+1, handleApteElement)
+	}
+}
@@ -0,0 +1,305 @@
+package dictionary
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/transliteration"
+)
+
+// CologneParser converts the entries of a dictionary of the Cologne Digital Sanskrit Lexicon
+// (https://www.sanskrit-lexicon.uni-koeln.de/), whose XML files share the entry structure
+// but differ in the tags of the body.
+type CologneParser interface {
+	// Name is the code of the dictionary in the Cologne lexicon, eg: mw.
+	Name() string
+	// ParseEntry converts an entry into its word and meaning. lastPageNum is the printed
+	// page of the previous entry, for entries which do not have one.
+	ParseEntry(xml CologneXmlEntry, lastPageNum string) (DictionaryEntry, Meaning)
+}
+
+// CologneParsers has the parsers of the supported dictionaries by code. The XML file of a
+// dictionary is named by its code, eg: gra.xml.
+var CologneParsers = map[string]CologneParser{
+	"mw":   mwParser{},
+	"gra":  grassmannParser{},
+	"md":   macdonellParser{},
+	"ap90": apteParser{},
+}
+
+type CologneXmlEntryBody struct {
+	Content string `xml:",innerxml"`
+}
+
+func attrVal(el xml.StartElement, attr string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == attr {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// CologneXmlEntry represents a single dictionary entry from the XML files of the Cologne
+// Digital Sanskrit Lexicon
+type CologneXmlEntry struct {
+	XMLName xml.Name            `xml:""`
+	Tag     string              `xml:"-"` // H1, H1A, H2, H3, etc.
+	Header  Header              `xml:"h"`
+	Body    CologneXmlEntryBody `xml:"body"`
+	Tail    Tail                `xml:"tail"`
+}
+
+type Header struct {
+	Key1 string `xml:"key1"`
+	Key2 string `xml:"key2"`
+	Hom  string `xml:"hom"`
+}
+
+type Tail struct {
+	L  string `xml:"L"`
+	PC string `xml:"pc"`
+}
+
+// newCologneEntry returns the word and the meaning of an entry with the fields of the header
+// and the tail, which are common to all the dictionaries.
+func newCologneEntry(xml CologneXmlEntry, lastPageNum string) (DictionaryEntry, Meaning) {
+	entry := DictionaryEntry{
+		Word: xml.Header.Key1,
+	}
+	meaning := Meaning{
+		Word:           entry.Word,
+		PrintedPageNum: xml.Tail.PC,
+		HTag:           xml.Tag,
+		SId:            xml.Tail.L,
+	}
+
+	iast, err := standardTl.Convert(entry.Word, common.TlSLP1, common.TlIAST)
+	if err == nil {
+		entry.IAST = iast
+	} else {
+		slog.Debug("unable to convert word to IAST", "word", entry.Word)
+	}
+
+	// Use last page number if current entry doesn't have one
+	if meaning.PrintedPageNum == "" {
+		meaning.PrintedPageNum = lastPageNum
+	}
+
+	// Parse homonym number
+	if xml.Header.Hom != "" {
+		if num, err := strconv.Atoi(xml.Header.Hom); err == nil {
+			meaning.HomonymNumber = num
+		}
+	}
+	return entry, meaning
+}
+
+func isEntryTag(tag string) bool {
+	// Matches H1, H1A, H2, H2A, H3, etc.
+	matched, _ := regexp.MatchString(`^H\d+[A-Z]?$`, tag)
+	return matched
+}
+
+// elementHandler handles an element of an entry body, writing its text to plainText and
+// collecting its data into the meaning. Each dictionary has its own tag set.
+type elementHandler func(elem xml.StartElement, decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning, depth int) error
+
+func parseBody(body string, meaning *Meaning, handle elementHandler) {
+	meaning.Body.Markup = body
+
+	// Wrap in root element to create valid XML
+	wrapped := "<root>" + body + "</root>"
+
+	decoder := xml.NewDecoder(strings.NewReader(wrapped))
+	decoder.Strict = false // tolerate malformed XML
+	decoder.Token()
+
+	var plainText strings.Builder
+
+	if err := walkXMLTree(decoder, &plainText, meaning, 0, handle); err != nil {
+		slog.Warn("error parsing body", "error", err, "id", meaning.SId)
+	}
+
+	meaning.Body.Plain = strings.TrimSpace(plainText.String())
+}
+
+func walkXMLTree(decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning, depth int, handle elementHandler) error {
+	if depth > 5 {
+		return nil
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				slog.Info("error when walking XML", "err", err)
+			}
+			return nil // EOF or error, stop gracefully
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			if err := handle(elem, decoder, plainText, meaning, depth); err != nil {
+				if !errors.Is(err, io.EOF) {
+					slog.Warn("error handling element", "tag", elem.Name.Local, "error", err)
+				}
+			}
+
+		case xml.CharData:
+			plainText.Write(elem)
+
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+func readElementText(decoder *xml.Decoder) (string, error) {
+	var text strings.Builder
+	depth := 1
+
+	for depth > 0 {
+		token, err := decoder.Token()
+		if err != nil {
+			return text.String(), err
+		}
+
+		switch elem := token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		case xml.CharData:
+			text.Write(elem)
+		}
+	}
+
+	return text.String(), nil
+}
+
+func skipElement(decoder *xml.Decoder) error {
+	_, err := readElementText(decoder)
+	return err
+}
+
+var standardTl = func() *transliteration.Transliterator {
+	tl, err := transliteration.NewTransliterator(transliteration.TlOptions{})
+	if err != nil {
+		log.Panicf("failed to instantiate transliterator: %s", err)
+	}
+	return tl
+}()
+
+// ConvertCologneDictionary converts a dictionary XML file of the Cologne Digital Sanskrit
+// Lexicon into DictionaryEntry JSONL, using the parser for its tag set.
+func ConvertCologneDictionary(parser CologneParser, inputPath, outputPath string) error {
+	// Create output directory
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	// Open input file
+	inFile, err := os.Open(inputPath)
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer inFile.Close()
+
+	// Open output file
+	outFile, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer outFile.Close()
+
+	writer := bufio.NewWriter(outFile)
+	defer writer.Flush()
+
+	// Read and parse XML entries
+	decoder := xml.NewDecoder(inFile)
+	lastPageNum := ""
+	entries := make(map[string]*DictionaryEntry)
+
+	slog.Info("parsing XML and collecting entries", "dictionary", parser.Name())
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading XML: %w", err)
+		}
+
+		if startElem, ok := token.(xml.StartElement); ok {
+			// Check if this is an entry element (H1, H1A, H2, etc.)
+			if isEntryTag(startElem.Name.Local) {
+				var entry CologneXmlEntry
+				if err := decoder.DecodeElement(&entry, &startElem); err != nil {
+					slog.Warn("error decoding entry", "err", err)
+					continue
+				}
+
+				entry.Tag = startElem.Name.Local
+
+				// Convert to dictionary entry
+				dictEntry, meaning := parser.ParseEntry(entry, lastPageNum)
+
+				// Update last page number
+				if meaning.PrintedPageNum != "" {
+					lastPageNum = meaning.PrintedPageNum
+				}
+
+				if existingEntry, ok := entries[dictEntry.Word]; ok {
+					existingEntry.Meanings = append(existingEntry.Meanings, meaning)
+				} else {
+					dictEntry.Meanings = append(dictEntry.Meanings, meaning)
+					entries[dictEntry.Word] = &dictEntry
+				}
+			}
+		}
+	}
+	// For deterministic output, get keys and sort them.
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	entryCount := 0
+	for _, key := range keys {
+		entry := entries[key]
+		// Write as JSON line
+		jsonBytes, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("error marshaling JSON: %w", err)
+		}
+		if _, err := writer.Write(jsonBytes); err != nil {
+			return fmt.Errorf("error writing JSON: %w", err)
+		}
+		if _, err := writer.WriteString("\n"); err != nil {
+			return fmt.Errorf("error writing newline: %w", err)
+		}
+		entryCount++
+		if entryCount%1000 == 0 {
+			slog.Info("writing entries", "done", entryCount)
+		}
+	}
+
+	fmt.Printf("Conversion complete. Processed %d entries.\n", len(entries))
+	return nil
+}
@@ -0,0 +1,46 @@
+package dictionary
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGrassmannLitRef(t *testing.T) {
+	assert.Equal(t, "RV. 1, 32, 5", grassmannLitRef("1,32,5", nil))
+	assert.Equal(t, "RV. 1, 32, 7", grassmannLitRef("7", []string{"RV. 1, 32, 5"}))
+	assert.Equal(t, "RV. 1, 40, 2", grassmannLitRef("40,2", []string{"RV. 1, 32, 5"}))
+	assert.Equal(t, "7", grassmannLitRef("7", nil))
+	assert.Equal(t, "AV. 4, 2", grassmannLitRef("AV. 4, 2", nil))
+}
+
+func TestConvertCologneDictionary(t *testing.T) {
+	dir := t.TempDir()
+	input := `<gra>
+<H1><h><key1>agni</key1><key2>agni</key2></h><body><s>agni</s>, <lex>m.</lex> Feuer, <ls>1,1,1</ls>; <ls>2</ls>; <ls>10,90,1</ls>.</body><tail><L>1</L><pc>1</pc></tail></H1>
+<H1><h><hom>2</hom><key1>agni</key1><key2>agni</key2></h><body>der Gott Agni.</body><tail><L>2</L></tail></H1>
+</gra>`
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "gra.xml"), []byte(input), 0o644))
+	assert.NoError(t, ConvertCologneDictionary(CologneParsers["gra"], filepath.Join(dir, "gra.xml"), filepath.Join(dir, "gra.jsonl")))
+
+	out, err := os.ReadFile(filepath.Join(dir, "gra.jsonl"))
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	assert.Len(t, lines, 1)
+	var entry DictionaryEntry
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+
+	assert.Equal(t, "agni", entry.Word)
+	if !assert.Len(t, entry.Meanings, 2) {
+		return
+	}
+	assert.Equal(t, []string{"RV. 1, 1, 1", "RV. 1, 1, 2", "RV. 10, 90, 1"}, entry.Meanings[0].LitRefs)
+	assert.Contains(t, entry.Meanings[0].Body.Plain, "Feuer")
+	assert.Equal(t, "m.", entry.Meanings[0].LexicalGender)
+	assert.Equal(t, 2, entry.Meanings[1].HomonymNumber)
+	assert.Equal(t, "1", entry.Meanings[1].PrintedPageNum)
+}
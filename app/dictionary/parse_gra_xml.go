@@ -0,0 +1,82 @@
+package dictionary
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// grassmannParser parses Grassmann's Wörterbuch zum Rig-Veda. It cites only the Rig Veda,
+// with references like 1,32,5, which may omit the mandala and the hymn of the previous
+// reference.
+type grassmannParser struct{}
+
+func (grassmannParser) Name() string {
+	return "gra"
+}
+
+func (grassmannParser) ParseEntry(xml CologneXmlEntry, lastPageNum string) (DictionaryEntry, Meaning) {
+	entry, meaning := newCologneEntry(xml, lastPageNum)
+	parseBody(xml.Body.Content, &meaning, handleGrassmannElement)
+	return entry, meaning
+}
+
+func handleGrassmannElement(elem xml.StartElement, decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning, depth int) error {
+	switch elem.Name.Local {
+	case "s":
+		return handleS(elem, decoder, plainText, meaning)
+	case "ls":
+		content, err := readElementText(decoder)
+		if err != nil {
+			return err
+		}
+		plainText.WriteRune('[')
+		plainText.WriteString(content)
+		plainText.WriteRune(']')
+		meaning.LitRefs = append(meaning.LitRefs, grassmannLitRef(content, meaning.LitRefs))
+		return nil
+	case "ab":
+		return handleAb(elem, decoder, plainText)
+	case "hom":
+		return handleHom(decoder, plainText, meaning)
+	case "lex":
+		return handleLex(decoder, plainText, meaning)
+	case "lang", "i", "etym":
+		return handleStripTag(decoder, plainText)
+	case "pb", "pc":
+		return handlePcol(decoder, plainText)
+	case "div":
+		// numbered senses
+		plainText.WriteRune(' ')
+		return walkXMLTree(decoder, plainText, meaning, depth+1, handleGrassmannElement)
+	default:
+		return walkXMLTree(decoder, plainText, meaning, depth+1, handleGrassmannElement)
+	}
+}
+
+// grassmannLitRef returns a reference like 32,5 as a complete Rig Veda reference like
+// "RV. 1, 32, 5", taking the omitted levels from the previous references.
+func grassmannLitRef(ref string, previous []string) string {
+	var path []string
+	for _, part := range strings.Split(ref, ",") {
+		part = strings.TrimSpace(part)
+		if _, err := strconv.Atoi(part); err != nil {
+			return ref
+		}
+		path = append(path, part)
+	}
+	if len(path) == 0 || len(path) > 3 {
+		return ref
+	}
+	if len(path) < 3 {
+		if len(previous) == 0 {
+			return ref
+		}
+		prev := strings.Split(strings.TrimPrefix(previous[len(previous)-1], "RV. "), ", ")
+		if len(prev) != 3 {
+			return ref
+		}
+		path = append(prev[:3-len(path)], path...)
+	}
+	return "RV. " + strings.Join(path, ", ")
+}
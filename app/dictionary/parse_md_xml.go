@@ -0,0 +1,40 @@
+package dictionary
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// macdonellParser parses Macdonell's Sanskrit-English dictionary.
+type macdonellParser struct{}
+
+func (macdonellParser) Name() string {
+	return "md"
+}
+
+func (macdonellParser) ParseEntry(xml CologneXmlEntry, lastPageNum string) (DictionaryEntry, Meaning) {
+	entry, meaning := newCologneEntry(xml, lastPageNum)
+	parseBody(xml.Body.Content, &meaning, handleMacdonellElement)
+	return entry, meaning
+}
+
+func handleMacdonellElement(elem xml.StartElement, decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning, depth int) error {
+	switch elem.Name.Local {
+	case "s":
+		return handleS(elem, decoder, plainText, meaning)
+	case "ls":
+		return handleLs(elem, decoder, plainText, meaning)
+	case "ab":
+		return handleAb(elem, decoder, plainText)
+	case "hom":
+		return handleHom(decoder, plainText, meaning)
+	case "lex":
+		return handleLex(decoder, plainText, meaning)
+	case "lang", "i", "etym", "b":
+		return handleStripTag(decoder, plainText)
+	case "pb", "pc":
+		return handlePcol(decoder, plainText)
+	default:
+		return walkXMLTree(decoder, plainText, meaning, depth+1, handleMacdonellElement)
+	}
+}
@@ -1,106 +1,35 @@
 package dictionary
 
 import (
-	"bufio"
-	"encoding/json"
 	"encoding/xml"
-	"errors"
-	"fmt"
-	"io"
-	"log"
 	"log/slog"
-	"os"
-	"path/filepath"
-	"regexp"
 	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/mahesh-hegde/dhee/app/common"
-	"github.com/mahesh-hegde/dhee/app/transliteration"
 )
 
-type MwXmlEntryBody struct {
-	Content string `xml:",innerxml"`
-}
+// mwParser parses the Monier-Williams Sanskrit-English dictionary.
+type mwParser struct{}
 
-func attrVal(el xml.StartElement, attr string) string {
-	for _, a := range el.Attr {
-		if a.Name.Local == attr {
-			return a.Value
-		}
-	}
-	return ""
+func (mwParser) Name() string {
+	return "mw"
 }
 
-// MwXmlEntry represents a single dictionary entry from the XML
-type MwXmlEntry struct {
-	XMLName xml.Name       `xml:""`
-	Tag     string         `xml:"-"` // H1, H1A, H2, H3, etc.
-	Header  Header         `xml:"h"`
-	Body    MwXmlEntryBody `xml:"body"`
-	Tail    Tail           `xml:"tail"`
-}
-
-type Header struct {
-	Key1 string `xml:"key1"`
-	Key2 string `xml:"key2"`
-	Hom  string `xml:"hom"`
-}
-
-type Tail struct {
-	L  string `xml:"L"`
-	PC string `xml:"pc"`
-}
-
-func isEntryTag(tag string) bool {
-	// Matches H1, H1A, H2, H2A, H3, etc.
-	matched, _ := regexp.MatchString(`^H\d+[A-Z]?$`, tag)
-	return matched
-}
-
-func xmlToDictionaryEntry(xml MwXmlEntry, lastPageNum string) (DictionaryEntry, Meaning) {
-	tl := standardTl
-
-	entry := DictionaryEntry{
-		Word: xml.Header.Key1,
-	}
-	meaning := Meaning{
-		Word:           entry.Word,
-		PrintedPageNum: xml.Tail.PC,
-		HTag:           xml.Tag,
-		SId:            xml.Tail.L,
-	}
-
-	iast, err := tl.Convert(entry.Word, common.TlSLP1, common.TlIAST)
-	if err == nil {
-		entry.IAST = iast
-	} else {
-		slog.Debug("unable to convert word to IAST", "word", entry.Word)
-	}
+func (mwParser) ParseEntry(xml CologneXmlEntry, lastPageNum string) (DictionaryEntry, Meaning) {
+	entry, meaning := newCologneEntry(xml, lastPageNum)
 
 	if xml.Header.Key2 != "" && xml.Header.Key2 != entry.Word {
 		meaning.Variants = []string{xml.Header.Key2}
 	}
 
-	// Use last page number if current entry doesn't have one
-	if meaning.PrintedPageNum == "" {
-		meaning.PrintedPageNum = lastPageNum
-	}
-
-	// Parse homonym number
-	if xml.Header.Hom != "" {
-		if num, err := strconv.Atoi(xml.Header.Hom); err == nil {
-			meaning.HomonymNumber = num
-		}
-	}
-
 	//	 Parse body into segments
-	parseBody(xml.Body.Content, &meaning)
+	parseBody(xml.Body.Content, &meaning, handleStartElement)
 
 	// convert otherspellins to IAST for ease of lookup from canonical scriptures
 	for _, va := range meaning.Variants {
-		iast, err := tl.Convert(va, common.TlSLP1, common.TlIAST)
+		iast, err := standardTl.Convert(va, common.TlSLP1, common.TlIAST)
 		if err != nil {
 			slog.Debug("cannot convert variant to IAST", "word", va)
 		} else {
@@ -111,56 +40,6 @@ func xmlToDictionaryEntry(xml MwXmlEntry, lastPageNum string) (DictionaryEntry,
 	return entry, meaning
 }
 
-func parseBody(body string, meaning *Meaning) {
-	meaning.Body.Markup = body
-
-	// Wrap in root element to create valid XML
-	wrapped := "<root>" + body + "</root>"
-
-	decoder := xml.NewDecoder(strings.NewReader(wrapped))
-	decoder.Strict = false // tolerate malformed XML
-	decoder.Token()
-
-	var plainText strings.Builder
-
-	if err := walkXMLTree(decoder, &plainText, meaning, 0); err != nil {
-		slog.Warn("error parsing body", "error", err, "id", meaning.SId)
-	}
-
-	meaning.Body.Plain = strings.TrimSpace(plainText.String())
-}
-
-func walkXMLTree(decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning, depth int) error {
-	if depth > 5 {
-		return nil
-	}
-
-	for {
-		token, err := decoder.Token()
-		if err != nil {
-			if !errors.Is(err, io.EOF) {
-				slog.Info("error when walking XML", "err", err)
-			}
-			return nil // EOF or error, stop gracefully
-		}
-
-		switch elem := token.(type) {
-		case xml.StartElement:
-			if err := handleStartElement(elem, decoder, plainText, meaning, depth); err != nil {
-				if !errors.Is(err, io.EOF) {
-					slog.Warn("error handling element", "tag", elem.Name.Local, "error", err)
-				}
-			}
-
-		case xml.CharData:
-			plainText.Write(elem)
-
-		case xml.EndElement:
-			return nil
-		}
-	}
-}
-
 func handleStartElement(elem xml.StartElement, decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning, depth int) error {
 	tagName := elem.Name.Local
 	switch tagName {
@@ -174,7 +53,7 @@ func handleStartElement(elem xml.StartElement, decoder *xml.Decoder, plainText *
 		return handleS(elem, decoder, plainText, meaning)
 
 	case "ls":
-		return handleLs(decoder, plainText, meaning)
+		return handleLs(elem, decoder, plainText, meaning)
 
 	case "hom":
 		return handleHom(decoder, plainText, meaning)
@@ -194,7 +73,7 @@ func handleStartElement(elem xml.StartElement, decoder *xml.Decoder, plainText *
 
 	default:
 		// Recurse into unknown tags
-		return walkXMLTree(decoder, plainText, meaning, depth+1)
+		return walkXMLTree(decoder, plainText, meaning, depth+1, handleStartElement)
 	}
 }
 
@@ -261,7 +140,7 @@ func handleS(_ xml.StartElement, decoder *xml.Decoder, plainText *strings.Builde
 	return nil
 }
 
-func handleLs(decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning) error {
+func handleLs(el xml.StartElement, decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning) error {
 	content, err := readElementText(decoder)
 	if err != nil {
 		return err
@@ -269,6 +148,10 @@ func handleLs(decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning
 	plainText.WriteRune('[')
 	plainText.WriteString(content)
 	plainText.WriteRune(']')
+	// continued references like <ls n="RV.">x, 90, 1</ls> omit the work in the text
+	if n := attrVal(el, "n"); n != "" {
+		content = n + " " + content
+	}
 	meaning.LitRefs = append(meaning.LitRefs, content)
 	return nil
 }
@@ -290,6 +173,20 @@ func handleHom(decoder *xml.Decoder, plainText *strings.Builder, meaning *Meanin
 	return nil
 }
 
+// handleLex writes a grammatical category like m. or mfn. and keeps it as the lexical
+// gender, unless the meaning has one.
+func handleLex(decoder *xml.Decoder, plainText *strings.Builder, meaning *Meaning) error {
+	content, err := readElementText(decoder)
+	if err != nil {
+		return err
+	}
+	if meaning.LexicalGender == "" {
+		meaning.LexicalGender = strings.TrimSpace(content)
+	}
+	plainText.WriteString(content)
+	return nil
+}
+
 func handleStripTag(decoder *xml.Decoder, plainText *strings.Builder) error {
 	content, err := readElementText(decoder)
 	if err != nil {
@@ -436,137 +333,3 @@ func parseOtherSpellings(value string, meaning *Meaning) {
 		}
 	}
 }
-
-func readElementText(decoder *xml.Decoder) (string, error) {
-	var text strings.Builder
-	depth := 1
-
-	for depth > 0 {
-		token, err := decoder.Token()
-		if err != nil {
-			return text.String(), err
-		}
-
-		switch elem := token.(type) {
-		case xml.StartElement:
-			depth++
-		case xml.EndElement:
-			depth--
-		case xml.CharData:
-			text.Write(elem)
-		}
-	}
-
-	return text.String(), nil
-}
-
-func skipElement(decoder *xml.Decoder) error {
-	_, err := readElementText(decoder)
-	return err
-}
-
-var standardTl = func() *transliteration.Transliterator {
-	tl, err := transliteration.NewTransliterator(transliteration.TlOptions{})
-	if err != nil {
-		log.Panicf("failed to instantiate transliterator: %s", err)
-	}
-	return tl
-}()
-
-func ConvertMonierWilliamsDictionary(inputPath, outputPath string) error {
-	// Create output directory
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
-	}
-
-	// Open input file
-	inFile, err := os.Open(inputPath)
-	if err != nil {
-		return fmt.Errorf("failed to open input file: %w", err)
-	}
-	defer inFile.Close()
-
-	// Open output file
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer outFile.Close()
-
-	writer := bufio.NewWriter(outFile)
-	defer writer.Flush()
-
-	// Read and parse XML entries
-	decoder := xml.NewDecoder(inFile)
-	lastPageNum := ""
-	entries := make(map[string]*DictionaryEntry)
-
-	slog.Info("parsing XML and collecting entries")
-
-	for {
-		token, err := decoder.Token()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return fmt.Errorf("error reading XML: %w", err)
-		}
-
-		if startElem, ok := token.(xml.StartElement); ok {
-			// Check if this is an entry element (H1, H1A, H2, etc.)
-			if isEntryTag(startElem.Name.Local) {
-				var entry MwXmlEntry
-				if err := decoder.DecodeElement(&entry, &startElem); err != nil {
-					slog.Warn("error decoding entry", "err", err)
-					continue
-				}
-
-				entry.Tag = startElem.Name.Local
-
-				// Convert to dictionary entry
-				dictEntry, meaning := xmlToDictionaryEntry(entry, lastPageNum)
-
-				// Update last page number
-				if meaning.PrintedPageNum != "" {
-					lastPageNum = meaning.PrintedPageNum
-				}
-
-				if existingEntry, ok := entries[dictEntry.Word]; ok {
-					existingEntry.Meanings = append(existingEntry.Meanings, meaning)
-				} else {
-					dictEntry.Meanings = append(dictEntry.Meanings, meaning)
-					entries[dictEntry.Word] = &dictEntry
-				}
-			}
-		}
-	}
-	// For deterministic output, get keys and sort them.
-	keys := make([]string, 0, len(entries))
-	for k := range entries {
-		keys = append(keys, k)
-	}
-	slices.Sort(keys)
-
-	entryCount := 0
-	for _, key := range keys {
-		entry := entries[key]
-		// Write as JSON line
-		jsonBytes, err := json.Marshal(entry)
-		if err != nil {
-			return fmt.Errorf("error marshaling JSON: %w", err)
-		}
-		if _, err := writer.Write(jsonBytes); err != nil {
-			return fmt.Errorf("error writing JSON: %w", err)
-		}
-		if _, err := writer.WriteString("\n"); err != nil {
-			return fmt.Errorf("error writing newline: %w", err)
-		}
-		entryCount++
-		if entryCount%1000 == 0 {
-			slog.Info("writing entries", "done", entryCount)
-		}
-	}
-
-	fmt.Printf("Conversion complete. Processed %d entries.\n", len(entries))
-	return nil
-}
@@ -0,0 +1,212 @@
+package dictionary
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Weights of English words in the reverse (English to Sanskrit) index. A meaning usually
+// starts with grammatical abbreviations followed by its primary gloss up to the first
+// semicolon, and continues with secondary senses, explanations in parentheses and citations
+// in square brackets.
+const (
+	reversePrimaryWeight   = 1.0
+	reverseSecondaryWeight = 0.4
+	// reverseParenFactor applies to words in parentheses, which are mostly explanations and
+	// quotations rather than translations.
+	reverseParenFactor = 0.25
+	// reverseHyphenFactor applies to parts of hyphenated English words, eg: horse in
+	// horse-sacrifice.
+	reverseHyphenFactor = 0.5
+	// reverseCompoundFactor applies to meanings of compounds and their derivatives, which
+	// have entry tags H3 and below.
+	reverseCompoundFactor = 0.5
+)
+
+// maxReverseTerms bounds the number of words of a reverse search.
+const maxReverseTerms = 5
+
+var englishStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "any": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "cf": true, "esp": true, "etc": true, "for": true, "from": true,
+	"he": true, "in": true, "into": true, "is": true, "it": true, "its": true, "mfn": true,
+	"ind": true, "not": true, "of": true, "on": true, "one": true, "or": true, "see": true,
+	"so": true, "the": true, "to": true, "who": true, "with": true, "which": true, "also": true,
+}
+
+// englishStem removes the common plural endings, so that horse and horses are indexed
+// alike.
+func englishStem(word string) string {
+	switch {
+	case len(word) > 4 && strings.HasSuffix(word, "ies"):
+		return word[:len(word)-3] + "y"
+	case len(word) > 4 && (strings.HasSuffix(word, "ches") || strings.HasSuffix(word, "shes") ||
+		strings.HasSuffix(word, "sses") || strings.HasSuffix(word, "xes")):
+		return word[:len(word)-2]
+	case len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") &&
+		!strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// englishTerm returns the indexed form of a word, or "" if the word is not indexed. Words
+// with letters outside ASCII are Sanskrit, and are not indexed.
+func englishTerm(word string) string {
+	if len(word) < 2 {
+		return ""
+	}
+	for _, r := range word {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return ""
+		}
+	}
+	word = strings.ToLower(word)
+	if englishStopWords[word] {
+		return ""
+	}
+	return englishStem(word)
+}
+
+// englishQueryTerms returns the distinct indexed terms of a reverse search query.
+func englishQueryTerms(query string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(query, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if t := englishTerm(w); t != "" && !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// isCompoundTag reports whether an entry tag like H3 or H4B is of a compound.
+func isCompoundTag(tag string) bool {
+	if len(tag) < 2 || tag[0] != 'H' {
+		return false
+	}
+	level, err := strconv.Atoi(strings.TrimRight(tag[1:], "ABCDEFGHIJKLMNOPQRSTUVWXYZ"))
+	return err == nil && level >= 3
+}
+
+// reverseTerms returns the weight of each English term of a meaning, which is the largest
+// weight of its occurrences.
+func reverseTerms(m *Meaning) map[string]float64 {
+	weights := make(map[string]float64)
+	factor := 1.0
+	if isCompoundTag(m.HTag) {
+		factor = reverseCompoundFactor
+	}
+	weight := reversePrimaryWeight
+	parens, brackets := 0, 0
+	var word strings.Builder
+	hyphenated := false
+	flush := func() {
+		if word.Len() == 0 {
+			return
+		}
+		if t := englishTerm(word.String()); t != "" && brackets == 0 {
+			w := weight * factor
+			if parens > 0 {
+				w *= reverseParenFactor
+			}
+			if hyphenated {
+				w *= reverseHyphenFactor
+			}
+			weights[t] = max(weights[t], w)
+		}
+		word.Reset()
+	}
+
+	text := []rune(m.Body.Plain)
+	for i, r := range text {
+		if unicode.IsLetter(r) {
+			if word.Len() == 0 {
+				// a word is a part of a hyphenated word if a hyphen precedes or follows it
+				hyphenated = i > 0 && text[i-1] == '-'
+				for j := i; j < len(text) && unicode.IsLetter(text[j]); j++ {
+					if j+1 < len(text) && text[j+1] == '-' {
+						hyphenated = true
+					}
+				}
+			}
+			word.WriteRune(r)
+			continue
+		}
+		flush()
+		switch r {
+		case '(':
+			parens++
+		case ')':
+			parens = max(parens-1, 0)
+		case '[':
+			brackets++
+		case ']':
+			brackets = max(brackets-1, 0)
+		case ';':
+			if parens == 0 && brackets == 0 {
+				weight = reverseSecondaryWeight
+			}
+		}
+	}
+	flush()
+	return weights
+}
+
+// shortDefinition returns the primary gloss of a meaning, without the leading grammatical
+// abbreviations and the citations.
+func shortDefinition(m *Meaning) string {
+	text := m.Body.Plain
+	if i := strings.Index(text, ";"); i >= 0 {
+		text = text[:i]
+	}
+	var b strings.Builder
+	brackets := 0
+	for _, r := range text {
+		switch {
+		case r == '[':
+			brackets++
+		case r == ']':
+			brackets = max(brackets-1, 0)
+		case brackets == 0:
+			b.WriteRune(r)
+		}
+	}
+	fields := strings.Fields(b.String())
+	// grammatical abbreviations like m., mfn. or cl. 1. end with a period
+	for len(fields) > 1 && strings.HasSuffix(fields[0], ".") {
+		fields = fields[1:]
+	}
+	return strings.TrimRight(strings.Join(fields, " "), " ,.:")
+}
+
+// homonymDefinitions returns the short definition of the first meaning of each homonym of an
+// entry, numbered if the entry has several homonyms.
+func homonymDefinitions(e *DictionaryEntry) []string {
+	var defs []string
+	seen := make(map[int]bool)
+	numbered := false
+	for _, m := range e.Meanings {
+		if m.HomonymNumber > 1 {
+			numbered = true
+		}
+	}
+	for i := range e.Meanings {
+		m := &e.Meanings[i]
+		if seen[m.HomonymNumber] {
+			continue
+		}
+		def := shortDefinition(m)
+		if def == "" {
+			continue
+		}
+		seen[m.HomonymNumber] = true
+		if numbered && m.HomonymNumber > 0 {
+			def = strconv.Itoa(m.HomonymNumber) + ". " + def
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
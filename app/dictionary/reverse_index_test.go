@@ -0,0 +1,44 @@
+package dictionary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReverseTerms(t *testing.T) {
+	m := &Meaning{
+		HTag: "H1",
+		Body: DictionaryEntryBody{Plain: "m. a horse, stallion [RV.] ; the number seven (from the seven horses of the sun) ; a horse-sacrifice [Cows.]"},
+	}
+	weights := reverseTerms(m)
+	assert.Equal(t, 1.0, weights["horse"])
+	assert.Equal(t, 1.0, weights["stallion"])
+	assert.Equal(t, 0.4, weights["number"])
+	assert.Equal(t, 0.1, weights["sun"])
+	assert.Equal(t, 0.2, weights["sacrifice"])
+	assert.NotContains(t, weights, "rv")
+	assert.NotContains(t, weights, "cow")
+	assert.NotContains(t, weights, "the")
+
+	compound := &Meaning{HTag: "H3", Body: DictionaryEntryBody{Plain: "m. the horse-sacrifice"}}
+	assert.Equal(t, 0.25, reverseTerms(compound)["horse"])
+}
+
+func TestEnglishQueryTerms(t *testing.T) {
+	assert.Equal(t, []string{"horse", "sacrifice"}, englishQueryTerms("The Horses of sacrifice, horse"))
+	assert.Equal(t, []string{"body", "church"}, englishQueryTerms("bodies churches"))
+	assert.Empty(t, englishQueryTerms("the of aśva"))
+}
+
+func TestHomonymDefinitions(t *testing.T) {
+	e := &DictionaryEntry{Meanings: []Meaning{
+		{HomonymNumber: 1, Body: DictionaryEntryBody{Plain: "mfn. going, moving [L.] ; a path"}},
+		{HomonymNumber: 1, Body: DictionaryEntryBody{Plain: "n. a road"}},
+		{HomonymNumber: 2, Body: DictionaryEntryBody{Plain: "m. cl. 1. P. to go [Dhātup.]"}},
+	}}
+	assert.Equal(t, []string{"1. going, moving", "2. to go"}, homonymDefinitions(e))
+	assert.Equal(t, []string{"fire"}, homonymDefinitions(&DictionaryEntry{Meanings: []Meaning{
+		{Body: DictionaryEntryBody{Plain: "m. fire;"}},
+	}}))
+}
@@ -3,14 +3,17 @@ package dictionary
 import (
 	"github.com/mahesh-hegde/dhee/app/common"
 	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/mahesh-hegde/dhee/app/inflection"
 )
 
 type SearchParams struct {
-	Query         string
-	OriginalQuery string
-	Tl            common.Transliteration
-	Mode          common.SearchMode
-	TextQuery     string
+	Query         string                 `json:"query"`
+	OriginalQuery string                 `json:"original_query"`
+	Tl            common.Transliteration `json:"tl"`
+	Mode          common.SearchMode      `json:"mode"`
+	TextQuery     string                 `json:"text_query"`
+	// Page is the 1-based page of results, see common.SearchPageSize.
+	Page int `json:"page"`
 }
 
 type SuggestParams struct {
@@ -19,28 +22,50 @@ type SuggestParams struct {
 }
 
 type DictSearchSuggestion struct {
-	IAST    string
-	HK      string
-	Nagari  string
-	Preview string
+	IAST    string `json:"iast"`
+	HK      string `json:"hk"`
+	Nagari  string `json:"nagari"`
+	Preview string `json:"preview"`
 }
 
 type Suggestions struct {
-	Items []DictSearchSuggestion
+	Items []DictSearchSuggestion `json:"items"`
 }
 
 type DictSearchResult struct {
-	IAST     string
-	Word     string
-	Nagari   string
-	Previews []string
+	IAST     string   `json:"iast"`
+	Word     string   `json:"word"`
+	Nagari   string   `json:"nagari"`
+	Previews []string `json:"previews"`
+	// IASTHl is the HTML escaped IAST word with the regex matches emphasized, in regex mode.
+	IASTHl string `json:"iast_hl,omitempty"`
+	// PreviewsHl are the HTML escaped previews with the regex matches emphasized, when
+	// searching the meanings in regex mode.
+	PreviewsHl []string `json:"previews_hl,omitempty"`
 }
 
 type SearchResults struct {
-	DictionaryName         string
-	DictionaryReadableName string
-	Items                  []DictSearchResult
-	Params                 SearchParams
+	DictionaryName         string             `json:"dictionary_name"`
+	DictionaryReadableName string             `json:"dictionary_readable_name"`
+	Items                  []DictSearchResult `json:"items"`
+	Params                 SearchParams       `json:"params"`
+	Pagination             common.Pagination  `json:"pagination"`
+	// Analyses are the lemmas the query can be an inflected form of, when an exact or
+	// prefix search finds no headword.
+	Analyses []inflection.Analysis `json:"analyses,omitempty"`
+}
+
+type AnalysisParams struct {
+	Word string                 `json:"word"`
+	Tl   common.Transliteration `json:"tl"`
+}
+
+type AnalysisResults struct {
+	DictionaryName string         `json:"dictionary_name"`
+	Params         AnalysisParams `json:"params"`
+	// Word is the SLP1 form which was analyzed.
+	Word     string                `json:"word"`
+	Analyses []inflection.Analysis `json:"analyses"`
 }
 
 type Cognate struct {
@@ -82,6 +107,8 @@ type Meaning struct {
 	Verb           Verb                `json:"verb,omitzero"`
 	// Other words referenced from this entry in SLP1 format
 	Referenced []string `json:"referenced,omitempty"`
+	// LitRefs which cite configured scriptures, resolved when the entry is indexed
+	Citations []Citation `json:"citations,omitempty"`
 }
 
 // DictionaryEntry represents the processed dictionary entry
@@ -123,6 +150,10 @@ type DictionaryEntryBody struct {
 }
 
 type DictionaryWordResponse struct {
-	Words      map[string]DictionaryEntry
-	Dictionary *config.DictDefn
+	Words      map[string]DictionaryEntry `json:"words"`
+	Dictionary *config.DictDefn           `json:"dictionary"`
+	// Declensions has the declension tables of the nominal words
+	Declensions map[string][]inflection.DeclensionTable `json:"declensions,omitempty"`
+	// Conjugations has the conjugation tables of the verbs
+	Conjugations map[string][]inflection.ConjugationTable `json:"conjugations,omitempty"`
 }
@@ -5,7 +5,11 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"log/slog"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/mahesh-hegde/dhee/app/common"
 	"github.com/mahesh-hegde/dhee/app/config"
@@ -27,10 +31,23 @@ func sanitizeNonAlphanumASCII(s string) string {
 type SQLiteDictStore struct {
 	db   *sql.DB
 	conf *config.DheeConfig
+
+	// fuzzyTrees holds the headwords of each dictionary for fuzzy search. A tree is built on
+	// the first fuzzy search of its dictionary or by BuildFuzzyIndexes, and dropped when entries
+	// are added. fuzzyMu only guards the map, so that building the tree of one dictionary
+	// does not block searches of the others.
+	fuzzyMu    sync.Mutex
+	fuzzyTrees map[string]*fuzzyIndex
+}
+
+// fuzzyIndex is the headword tree of a dictionary, built once by the first search needing it.
+type fuzzyIndex struct {
+	mu   sync.Mutex
+	tree *bkTree
 }
 
 func NewSQLiteDictStore(db *sql.DB, conf *config.DheeConfig) *SQLiteDictStore {
-	return &SQLiteDictStore{db: db, conf: conf}
+	return &SQLiteDictStore{db: db, conf: conf, fuzzyTrees: make(map[string]*fuzzyIndex)}
 }
 
 var _ DictStore = &SQLiteDictStore{}
@@ -63,13 +80,35 @@ func (s *SQLiteDictStore) Init() error {
 		return fmt.Errorf("failed to create dhee_dictionary_fts table: %w", err)
 	}
 
-	// Spellfix table
-	// _, err = s.db.Exec(`
-	// 	CREATE VIRTUAL TABLE IF NOT EXISTS dhee_dictionary_spellfix USING spellfix1;
-	// `)
-	// if err != nil {
-	// 	return fmt.Errorf("failed to create spellfix1 table: %w", err)
-	// }
+	// Reverse index from English words of the meanings to the words, see reverseTerms
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dhee_dictionary_reverse (
+			dict_name TEXT,
+			term TEXT,
+			word TEXT,
+			weight REAL
+		);
+		CREATE INDEX IF NOT EXISTS idx_dict_reverse_term ON dhee_dictionary_reverse(dict_name, term);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create dhee_dictionary_reverse table: %w", err)
+	}
+
+	// Join table of the verses cited by the entries, see ResolveCitations
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dhee_dictionary_citations (
+			dict_name TEXT,
+			word TEXT,
+			iast TEXT,
+			ref TEXT,
+			scripture TEXT,
+			path TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_dict_citations_path ON dhee_dictionary_citations(scripture, path);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create dhee_dictionary_citations table: %w", err)
+	}
 
 	return nil
 }
@@ -93,14 +132,29 @@ func (s *SQLiteDictStore) Add(ctx context.Context, dictName string, es []Diction
 	}
 	defer ftsStmt.Close()
 
-	// spellfixStmt, err := tx.Prepare("INSERT INTO dhee_dictionary_spellfix (word) VALUES (?)")
-	// if err != nil {
-	// return err
-	// }
-	// defer spellfixStmt.Close()
+	reverseStmt, err := tx.Prepare("INSERT INTO dhee_dictionary_reverse (dict_name, term, word, weight) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer reverseStmt.Close()
+
+	citationStmt, err := tx.Prepare("INSERT INTO dhee_dictionary_citations (dict_name, word, iast, ref, scripture, path) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer citationStmt.Close()
 
 	for _, e := range es {
 		e.DictName = dictName
+		for i := range e.Meanings {
+			m := &e.Meanings[i]
+			m.Citations = ResolveCitations(m.LitRefs, s.conf.Scriptures)
+			for _, c := range m.Citations {
+				if _, err := citationStmt.ExecContext(ctx, dictName, e.Word, e.IAST, c.Ref, c.Scripture, common.PathToString(c.Path)); err != nil {
+					return err
+				}
+			}
+		}
 		id := fmt.Sprintf("%d:%s", s.conf.DictNameToId(dictName), e.Word)
 
 		entryJSON, err := json.Marshal(e)
@@ -130,13 +184,27 @@ func (s *SQLiteDictStore) Add(ctx context.Context, dictName string, es []Diction
 			return err
 		}
 
-		// _, err = spellfixStmt.ExecContext(ctx, e.Word)
-		// if err != nil {
-		// return err
-		// }
+		// a word is weighted by its best meaning, so that homonyms are ranked together
+		weights := make(map[string]float64)
+		for i := range e.Meanings {
+			for term, w := range reverseTerms(&e.Meanings[i]) {
+				weights[term] = max(weights[term], w)
+			}
+		}
+		for term, w := range weights {
+			if _, err := reverseStmt.ExecContext(ctx, dictName, term, e.Word, w); err != nil {
+				return err
+			}
+		}
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	s.fuzzyMu.Lock()
+	delete(s.fuzzyTrees, dictName)
+	s.fuzzyMu.Unlock()
+	return nil
 }
 
 func (s *SQLiteDictStore) Get(ctx context.Context, dictName string, words []string) (map[string]DictionaryEntry, error) {
@@ -175,65 +243,47 @@ func (s *SQLiteDictStore) Get(ctx context.Context, dictName string, words []stri
 }
 
 func (s *SQLiteDictStore) Search(ctx context.Context, dictName string, searchParams SearchParams) (SearchResults, error) {
-	// TODO: implement fuzzy search using a combined bleve index
-	// since spellfix1 is not available in any Go bindings
-	if searchParams.Mode == "fuzzy" {
-		searchParams.Mode = "prefix"
-	}
-
-	if searchParams.Mode == "exact" || searchParams.Mode == "prefix" {
-		var rows *sql.Rows
-		var err error
-
-		switch searchParams.Mode {
-		case "exact":
-			query := `SELECT entry FROM dhee_dictionary_entries WHERE dict_name = ? AND word = ? ORDER BY word LIMIT 100`
-			rows, err = s.db.QueryContext(ctx, query, dictName, searchParams.Query)
-		case "prefix":
-			query := `SELECT entry FROM dhee_dictionary_entries WHERE dict_name = ? AND word GLOB ? ORDER BY LENGTH(word), word LIMIT 100`
-			rows, err = s.db.QueryContext(ctx, query, dictName, sanitizeNonAlphanumASCII(searchParams.Query)+"*")
-		}
-
-		if err != nil {
-			return SearchResults{}, fmt.Errorf("sqlite search failed: %w", err)
-		}
-		defer rows.Close()
-
-		var items []DictSearchResult
-		for rows.Next() {
-			var entryJSON []byte
-			if err := rows.Scan(&entryJSON); err != nil {
-				return SearchResults{}, err
-			}
-			var ent DictionaryEntry
-			if err := json.Unmarshal(entryJSON, &ent); err != nil {
-				return SearchResults{}, err
-			}
-			previews := make([]string, 0, len(ent.Meanings))
-			for _, meaning := range ent.Meanings {
-				previews = append(previews, meaning.Body.Plain)
-			}
-			items = append(items, DictSearchResult{
-				IAST:     ent.IAST,
-				Word:     ent.Word,
-				Previews: previews,
-			})
-		}
-		if err := rows.Err(); err != nil {
-			return SearchResults{}, err
-		}
-
-		return SearchResults{Items: items, DictionaryName: dictName}, nil
+	switch searchParams.Mode {
+	case common.SearchFuzzy:
+		return s.fuzzySearch(ctx, dictName, searchParams)
+	case common.SearchEnglish:
+		return s.reverseSearch(ctx, dictName, searchParams)
 	}
 
-	var ftsQuery, ftsColumn, orderBy string
-	orderBy = "ORDER BY de.word"
-
+	// from and where are shared between the count and the results query, and the
+	// order ends with rowid so that pages are stable.
+	var from, where, orderBy string
+	var args, orderArgs []any
 	switch searchParams.Mode {
+	case "exact":
+		from = "dhee_dictionary_entries AS de"
+		where = "de.dict_name = ? AND de.word = ?"
+		args = []any{dictName, searchParams.Query}
+		orderBy = "de.word, de.rowid"
+	case "prefix":
+		from = "dhee_dictionary_entries AS de"
+		where = "de.dict_name = ? AND de.word GLOB ?"
+		args = []any{dictName, sanitizeNonAlphanumASCII(searchParams.Query) + "*"}
+		orderBy = "LENGTH(de.word), de.word, de.rowid"
 	case "translations":
-		ftsQuery = searchParams.Query
-		ftsColumn = "body_text"
-		orderBy = "ORDER BY de_fts.rank" // Corresponds to _score sort
+		from = "dhee_dictionary_fts AS de_fts JOIN dhee_dictionary_entries AS de ON de_fts.rowid = de.rowid"
+		where = "de.dict_name = ? AND de_fts.body_text MATCH ?"
+		args = []any{dictName, searchParams.Query}
+		orderBy = "de_fts.rank, de.rowid" // Corresponds to _score sort
+	case common.SearchRegex:
+		from = "dhee_dictionary_fts AS de_fts JOIN dhee_dictionary_entries AS de ON de_fts.rowid = de.rowid"
+		if searchParams.TextQuery != "" {
+			where = "de.dict_name = ? AND de_fts.body_text REGEXP ?"
+			args = []any{dictName, searchParams.TextQuery}
+			orderBy = "LENGTH(de.word), de.word, de.rowid"
+			break
+		}
+		// variants are matched one per line, so that ^ and $ anchor each variant
+		where = "de.dict_name = ? AND (de.word REGEXP ? OR replace(de_fts.variants, ', ', char(10)) REGEXP ?)"
+		args = []any{dictName, searchParams.Query, "(?m-s)" + searchParams.Query}
+		// matches of the headword come first
+		orderBy = "(de.word REGEXP ?) DESC, LENGTH(de.word), de.word, de.rowid"
+		orderArgs = []any{searchParams.Query}
 	default:
 		return SearchResults{}, &common.UserVisibleError{
 			HttpCode: 500,
@@ -241,18 +291,22 @@ func (s *SQLiteDictStore) Search(ctx context.Context, dictName string, searchPar
 		}
 	}
 
-	matchClause := "de_fts.dhee_dictionary_fts MATCH ?"
-	if ftsColumn != "" {
-		matchClause = fmt.Sprintf("de_fts.%s MATCH ?", ftsColumn)
+	pagination := common.NewPagination(searchParams.Page)
+	countQuery := "SELECT count(*) FROM " + from + " WHERE " + where
+	countArgs := args
+	// a regular expression is evaluated on every entry, so the matches are counted in the
+	// same scan as the page instead of a separate count query
+	countInPage := searchParams.Mode == common.SearchRegex
+	columns := "de.entry"
+	if countInPage {
+		columns += ", count(*) OVER ()"
+	} else if err := s.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&pagination.Total); err != nil {
+		return SearchResults{}, fmt.Errorf("sqlite search count failed: %w", err)
 	}
 
-	query := `
-		SELECT de.entry 
-		FROM dhee_dictionary_fts AS de_fts JOIN dhee_dictionary_entries AS de ON de_fts.rowid = de.rowid 
-		WHERE de.dict_name = ? AND ` + matchClause
-	fullQuery := query + " " + orderBy + " LIMIT 100"
-
-	rows, err := s.db.QueryContext(ctx, fullQuery, dictName, ftsQuery)
+	query := "SELECT " + columns + " FROM " + from + " WHERE " + where + " ORDER BY " + orderBy + " LIMIT ? OFFSET ?"
+	args = append(append(args, orderArgs...), pagination.PageSize, pagination.Offset())
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return SearchResults{}, fmt.Errorf("sqlite search failed: %w", err)
 	}
@@ -261,7 +315,11 @@ func (s *SQLiteDictStore) Search(ctx context.Context, dictName string, searchPar
 	var items []DictSearchResult
 	for rows.Next() {
 		var entryJSON []byte
-		if err := rows.Scan(&entryJSON); err != nil {
+		dest := []any{&entryJSON}
+		if countInPage {
+			dest = append(dest, &pagination.Total)
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return SearchResults{}, err
 		}
 		var ent DictionaryEntry
@@ -281,8 +339,175 @@ func (s *SQLiteDictStore) Search(ctx context.Context, dictName string, searchPar
 	if err := rows.Err(); err != nil {
 		return SearchResults{}, err
 	}
+	// a page past the last match has no rows to carry the count
+	if countInPage && len(items) == 0 && pagination.Page > 1 {
+		if err := s.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&pagination.Total); err != nil {
+			return SearchResults{}, fmt.Errorf("sqlite search count failed: %w", err)
+		}
+	}
 
-	return SearchResults{Items: items, DictionaryName: dictName}, nil
+	return SearchResults{Items: items, DictionaryName: dictName, Pagination: pagination}, nil
+}
+
+// fuzzySearch returns the headwords within the configured edit distance of the query, closest
+// first, where confusable letters like a and A, t and w, or s and S are half an edit apart.
+func (s *SQLiteDictStore) fuzzySearch(ctx context.Context, dictName string, searchParams SearchParams) (SearchResults, error) {
+	tree, err := s.fuzzyTree(ctx, dictName)
+	if err != nil {
+		return SearchResults{}, err
+	}
+	query := sanitizeNonAlphanumASCII(searchParams.Query)
+	// the tree measures distances in half edits
+	radius := 2 * common.MaxEditDistance(s.conf.Fuzziness, len([]rune(query)))
+	matches := tree.Search(query, radius)
+
+	pagination := common.NewPagination(searchParams.Page)
+	pagination.Total = len(matches)
+	start := min(pagination.Offset(), len(matches))
+	end := min(start+pagination.PageSize, len(matches))
+	matches = matches[start:end]
+
+	words := make([]string, len(matches))
+	for i, m := range matches {
+		words[i] = m.Word
+	}
+	entries, err := s.Get(ctx, dictName, words)
+	if err != nil {
+		return SearchResults{}, fmt.Errorf("sqlite fuzzy search failed: %w", err)
+	}
+
+	var items []DictSearchResult
+	for _, word := range words {
+		ent, ok := entries[word]
+		if !ok {
+			continue
+		}
+		previews := make([]string, 0, len(ent.Meanings))
+		for _, meaning := range ent.Meanings {
+			previews = append(previews, meaning.Body.Plain)
+		}
+		items = append(items, DictSearchResult{
+			IAST:     ent.IAST,
+			Word:     ent.Word,
+			Previews: previews,
+		})
+	}
+	return SearchResults{Items: items, DictionaryName: dictName, Pagination: pagination}, nil
+}
+
+// reverseSearch returns the words whose meanings have all the English words of the query,
+// ranked by the sum of their weights, with the short definitions of their homonyms as
+// previews.
+func (s *SQLiteDictStore) reverseSearch(ctx context.Context, dictName string, searchParams SearchParams) (SearchResults, error) {
+	terms := englishQueryTerms(searchParams.Query)
+	if len(terms) == 0 {
+		return SearchResults{}, common.NewUserVisibleError(http.StatusBadRequest, "query has no English words to search for")
+	}
+	if len(terms) > maxReverseTerms {
+		return SearchResults{}, common.NewUserVisibleError(http.StatusBadRequest,
+			fmt.Sprintf("query has more than %d words", maxReverseTerms))
+	}
+
+	args := []any{dictName}
+	for _, t := range terms {
+		args = append(args, t)
+	}
+	args = append(args, len(terms))
+	matches := "SELECT word, SUM(weight) AS score FROM dhee_dictionary_reverse" +
+		" WHERE dict_name = ? AND term IN (?" + strings.Repeat(",?", len(terms)-1) + ")" +
+		" GROUP BY word HAVING COUNT(*) = ?"
+
+	pagination := common.NewPagination(searchParams.Page)
+	if err := s.db.QueryRowContext(ctx, "SELECT count(*) FROM ("+matches+")", args...).Scan(&pagination.Total); err != nil {
+		return SearchResults{}, fmt.Errorf("sqlite reverse search count failed: %w", err)
+	}
+
+	query := matches + " ORDER BY score DESC, LENGTH(word), word LIMIT ? OFFSET ?"
+	rows, err := s.db.QueryContext(ctx, query, append(args, pagination.PageSize, pagination.Offset())...)
+	if err != nil {
+		return SearchResults{}, fmt.Errorf("sqlite reverse search failed: %w", err)
+	}
+	defer rows.Close()
+	var words []string
+	for rows.Next() {
+		var word string
+		var score float64
+		if err := rows.Scan(&word, &score); err != nil {
+			return SearchResults{}, err
+		}
+		words = append(words, word)
+	}
+	if err := rows.Err(); err != nil {
+		return SearchResults{}, err
+	}
+
+	entries, err := s.Get(ctx, dictName, words)
+	if err != nil {
+		return SearchResults{}, fmt.Errorf("sqlite reverse search failed: %w", err)
+	}
+	var items []DictSearchResult
+	for _, word := range words {
+		ent, ok := entries[word]
+		if !ok {
+			continue
+		}
+		items = append(items, DictSearchResult{
+			IAST:     ent.IAST,
+			Word:     ent.Word,
+			Previews: homonymDefinitions(&ent),
+		})
+	}
+	return SearchResults{Items: items, DictionaryName: dictName, Pagination: pagination}, nil
+}
+
+// BuildFuzzyIndexes builds the fuzzy search trees of all configured dictionaries, so that the
+// first fuzzy searches do not wait for them.
+func (s *SQLiteDictStore) BuildFuzzyIndexes(ctx context.Context) error {
+	for _, d := range s.conf.Dictionaries {
+		if _, err := s.fuzzyTree(ctx, d.Name); err != nil {
+			return fmt.Errorf("failed to build fuzzy index of %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// fuzzyTree returns the headword tree of a dictionary, building it if needed.
+func (s *SQLiteDictStore) fuzzyTree(ctx context.Context, dictName string) (*bkTree, error) {
+	s.fuzzyMu.Lock()
+	index, ok := s.fuzzyTrees[dictName]
+	if !ok {
+		index = &fuzzyIndex{}
+		s.fuzzyTrees[dictName] = index
+	}
+	s.fuzzyMu.Unlock()
+
+	// a failed build is retried by the next search
+	index.mu.Lock()
+	defer index.mu.Unlock()
+	if index.tree != nil {
+		return index.tree, nil
+	}
+
+	start := time.Now()
+	rows, err := s.db.QueryContext(ctx, "SELECT word FROM dhee_dictionary_entries WHERE dict_name = ?", dictName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read headwords: %w", err)
+	}
+	defer rows.Close()
+	tree := &bkTree{}
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			return nil, err
+		}
+		tree.Add(word)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slog.Info("built fuzzy search index", "dictionary", dictName, "words", tree.size, "duration", time.Since(start))
+	index.tree = tree
+	return tree, nil
 }
 
 func (s *SQLiteDictStore) Suggest(ctx context.Context, dictName string, p SuggestParams) (Suggestions, error) {
@@ -329,6 +554,34 @@ func (s *SQLiteDictStore) Suggest(ctx context.Context, dictName string, p Sugges
 	return Suggestions{Items: items}, nil
 }
 
+func (s *SQLiteDictStore) Citing(ctx context.Context, scripture string, paths []string) (map[string][]CitingWord, error) {
+	results := make(map[string][]CitingWord)
+	if len(paths) == 0 {
+		return results, nil
+	}
+	args := []any{scripture}
+	for _, p := range paths {
+		args = append(args, p)
+	}
+	query := "SELECT path, dict_name, word, iast, ref FROM dhee_dictionary_citations" +
+		" WHERE scripture = ? AND path IN (?" + strings.Repeat(",?", len(paths)-1) + ")" +
+		" ORDER BY dict_name, word, rowid"
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite citing query failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var path string
+		var w CitingWord
+		if err := rows.Scan(&path, &w.DictName, &w.Word, &w.IAST, &w.Ref); err != nil {
+			return nil, err
+		}
+		results[path] = append(results[path], w)
+	}
+	return results, rows.Err()
+}
+
 func (s *SQLiteDictStore) Related(ctx context.Context, dictName string, word string) (SearchResults, error) {
 	return s.Search(ctx, dictName, SearchParams{
 		Query: word + "-",
@@ -16,6 +16,7 @@ import (
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer"
 	"github.com/yuin/goldmark/renderer/html"
+	gmtext "github.com/yuin/goldmark/text"
 	"github.com/yuin/goldmark/util"
 )
 
@@ -47,14 +48,31 @@ func NewMarkdownConverter(d dictionary.DictStore, t *transliteration.Translitera
 // ConvertToHTML converts custom markdown to HTML.
 func (mc *MarkdownConverter) ConvertToHTML(text string, scripture config.ScriptureDefn) (string, error) {
 	var buf bytes.Buffer
+	source := []byte(text)
 	ctx := parser.NewContext()
 	ctx.Set(scriptureContextKey, scripture)
-	if err := mc.goldmark.Convert([]byte(text), &buf, parser.WithContext(ctx)); err != nil {
+	doc := mc.goldmark.Parser().Parse(gmtext.NewReader(source), parser.WithContext(ctx))
+	// renderers have no parser context, so they read the scripture from the document
+	doc.OwnerDocument().AddMeta(scriptureMetaKey, scripture)
+	if err := mc.goldmark.Renderer().Render(&buf, source, doc); err != nil {
 		return "", err
 	}
 	return buf.String(), nil
 }
 
+const scriptureMetaKey = "dhee-scripture"
+
+// lookupWord returns the first of the scripture's dictionaries having the word, or "".
+func (mc *MarkdownConverter) lookupWord(scripture *config.ScriptureDefn, wordSLP1 string) string {
+	for _, dictName := range mc.conf.LookupDictionaries(scripture) {
+		entries, _ := mc.dictStore.Get(context.Background(), dictName, []string{wordSLP1})
+		if len(entries) > 0 {
+			return dictName
+		}
+	}
+	return ""
+}
+
 type dheeMarkdownExtension struct {
 	mc *MarkdownConverter
 }
@@ -146,10 +164,12 @@ func (r *dheeHTMLRenderer) renderCodeSpan(w util.BufWriter, source []byte, n ast
 		return ast.WalkSkipChildren, nil
 	}
 
-	dictName := r.mc.conf.DefaultDict
-	entries, _ := r.mc.dictStore.Get(context.Background(), dictName, []string{wordSLP1})
+	var scripture *config.ScriptureDefn
+	if scri, ok := n.OwnerDocument().Meta()[scriptureMetaKey].(config.ScriptureDefn); ok {
+		scripture = &scri
+	}
 
-	if len(entries) > 0 {
+	if dictName := r.mc.lookupWord(scripture, wordSLP1); dictName != "" {
 		url := fmt.Sprintf("/dictionaries/%s/words/%s", dictName, wordSLP1)
 		_, _ = w.WriteString(`<a href="`)
 		_, _ = w.Write(util.EscapeHTML(util.URLEscape([]byte(url), true)))
@@ -0,0 +1,50 @@
+package excerpts
+
+import (
+	"sort"
+
+	"github.com/mahesh-hegde/dhee/app/prosody"
+)
+
+// countPlacements orders the counts of placements from the end of the word to its beginning,
+// skipping the placements which do not occur.
+func countPlacements(counts map[prosody.Placement]int) []PlacementCount {
+	var res []PlacementCount
+	for _, p := range prosody.Placements {
+		if counts[p] > 0 {
+			res = append(res, PlacementCount{Placement: p, Count: counts[p]})
+		}
+	}
+	return res
+}
+
+// summarizeAccents counts the occurrences of the forms by accent placement, overall and for
+// each combination of grammatical tags, the most frequent first.
+func summarizeAccents(forms []AccentForm) (int, []PlacementCount, []AccentCategory) {
+	total := 0
+	overall := make(map[prosody.Placement]int)
+	byTags := make(map[string]map[prosody.Placement]int)
+	tagTotals := make(map[string]int)
+	for _, f := range forms {
+		total += f.Count
+		overall[f.Placement] += f.Count
+		tags := f.Tags()
+		if byTags[tags] == nil {
+			byTags[tags] = make(map[prosody.Placement]int)
+		}
+		byTags[tags][f.Placement] += f.Count
+		tagTotals[tags] += f.Count
+	}
+
+	categories := make([]AccentCategory, 0, len(byTags))
+	for tags, counts := range byTags {
+		categories = append(categories, AccentCategory{Tags: tags, Count: tagTotals[tags], Placements: countPlacements(counts)})
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Count != categories[j].Count {
+			return categories[i].Count > categories[j].Count
+		}
+		return categories[i].Tags < categories[j].Tags
+	})
+	return total, countPlacements(overall), categories
+}
@@ -0,0 +1,27 @@
+package excerpts
+
+import (
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/prosody"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeAccents(t *testing.T) {
+	forms := []AccentForm{
+		{Surface: "agním", Placement: prosody.PlacementFinal, Case: "ACC", Number: "SG", Gender: "M", Count: 5},
+		{Surface: "ágne", Placement: prosody.PlacementPenultimate, Case: "VOC", Number: "SG", Gender: "M", Count: 3},
+		{Surface: "agne", Placement: prosody.PlacementUnaccented, Case: "VOC", Number: "SG", Gender: "M", Count: 4},
+		{Surface: "agnáye", Placement: prosody.PlacementPenultimate, Case: "DAT", Number: "SG", Gender: "M", Count: 1},
+	}
+	total, placements, categories := summarizeAccents(forms)
+	assert.Equal(t, 13, total)
+	assert.Equal(t, []PlacementCount{
+		{prosody.PlacementFinal, 5}, {prosody.PlacementPenultimate, 4}, {prosody.PlacementUnaccented, 4},
+	}, placements)
+	assert.Equal(t, []AccentCategory{
+		{Tags: "VOC SG M", Count: 7, Placements: []PlacementCount{{prosody.PlacementPenultimate, 3}, {prosody.PlacementUnaccented, 4}}},
+		{Tags: "ACC SG M", Count: 5, Placements: []PlacementCount{{prosody.PlacementFinal, 5}}},
+		{Tags: "DAT SG M", Count: 1, Placements: []PlacementCount{{prosody.PlacementPenultimate, 1}}},
+	}, categories)
+}
@@ -0,0 +1,175 @@
+package excerpts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+)
+
+// Attribution is what an anukramani (traditional index) records of a hymn or verse: its
+// seers (rsi), deities (devata), meter (chandas) and the family of the seers.
+type Attribution struct {
+	Rsis    []string `json:"rsi,omitempty"`
+	Devatas []string `json:"devata,omitempty"`
+	Chandas string   `json:"chandas,omitempty"`
+	Family  string   `json:"family,omitempty"`
+}
+
+// override returns the attribution with the fields set in o replaced.
+func (a Attribution) override(o Attribution) Attribution {
+	if len(o.Rsis) > 0 {
+		a.Rsis = o.Rsis
+	}
+	if len(o.Devatas) > 0 {
+		a.Devatas = o.Devatas
+	}
+	if o.Chandas != "" {
+		a.Chandas = o.Chandas
+	}
+	if o.Family != "" {
+		a.Family = o.Family
+	}
+	return a
+}
+
+// AnukramaniHymn is a line of an anukramani JSONL file, eg:
+// {"hymn": "7.18", "rsi": ["Vasistha Maitravaruni"], "family": "Vasistha", "devata": ["Indra"], "chandas": "Tristubh",
+// "verses": [{"verses": "22-25", "devata": ["Sudas Paijavana"]}]}
+type AnukramaniHymn struct {
+	// Hymn is the path of the hymn, eg: 7.18.
+	Hymn string `json:"hymn"`
+	Attribution
+	// Verses override the attribution of the hymn for some of its verses.
+	Verses []AnukramaniVerses `json:"verses,omitempty"`
+}
+
+// AnukramaniVerses is the attribution of a verse or an inclusive range of verses of a hymn.
+type AnukramaniVerses struct {
+	// Verses is a verse number or range, eg: 4 or 4-6.
+	Verses string `json:"verses"`
+	Attribution
+}
+
+// bounds returns the first and last verse of the range.
+func (v AnukramaniVerses) bounds() (int, int, error) {
+	from, to, isRange := strings.Cut(v.Verses, "-")
+	start, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid verses %q", v.Verses)
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(strings.TrimSpace(to)); err != nil || end < start {
+			return 0, 0, fmt.Errorf("invalid verses %q", v.Verses)
+		}
+	}
+	return start, end, nil
+}
+
+// ReadAnukramani reads an anukramani JSONL file, with one AnukramaniHymn per line, and
+// returns the hymns by path.
+func ReadAnukramani(r io.Reader) (map[string]AnukramaniHymn, error) {
+	hymns := make(map[string]AnukramaniHymn)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var h AnukramaniHymn
+		if err := json.Unmarshal(scanner.Bytes(), &h); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if _, err := common.StringToPath(h.Hymn); err != nil {
+			return nil, fmt.Errorf("line %d: invalid hymn %q", n, h.Hymn)
+		}
+		for _, v := range h.Verses {
+			if _, _, err := v.bounds(); err != nil {
+				return nil, fmt.Errorf("line %d: %w", n, err)
+			}
+		}
+		hymns[h.Hymn] = h
+	}
+	return hymns, scanner.Err()
+}
+
+// applyAnukramani sets the attribution of each excerpt from its hymn, and returns the
+// number of excerpts having one. The last element of a path is taken as the verse number.
+func applyAnukramani(es []Excerpt, hymns map[string]AnukramaniHymn) int {
+	n := 0
+	for i := range es {
+		e := &es[i]
+		if len(e.Path) < 2 {
+			continue
+		}
+		h, ok := hymns[common.PathToString(e.Path[:len(e.Path)-1])]
+		if !ok {
+			continue
+		}
+		a := h.Attribution
+		verse := e.Path[len(e.Path)-1]
+		for _, v := range h.Verses {
+			// ranges are validated by ReadAnukramani
+			if start, end, _ := v.bounds(); start <= verse && verse <= end {
+				a = a.override(v.Attribution)
+			}
+		}
+		e.Anukramani = &a
+		n++
+	}
+	return n
+}
+
+// AttributionKind is a kind of name an anukramani attributes hymns to.
+type AttributionKind string
+
+const (
+	AttributionSeers    AttributionKind = "seers"
+	AttributionDeities  AttributionKind = "deities"
+	AttributionFamilies AttributionKind = "families"
+	// AttributionChandas is stored along with the others, but has no index, see the meters.
+	AttributionChandas AttributionKind = "chandas"
+)
+
+// Title returns the kind capitalized, eg: Seers.
+func (k AttributionKind) Title() string {
+	if k == "" {
+		return ""
+	}
+	return strings.ToUpper(string(k[:1])) + string(k[1:])
+}
+
+// AttributionIndexKinds lists the kinds having index pages.
+var AttributionIndexKinds = []AttributionKind{AttributionSeers, AttributionDeities, AttributionFamilies}
+
+// excerptAttribution returns the anukramani attribution of the excerpt, or one made from
+// the authors, addressees and meter if it has none.
+func excerptAttribution(e *Excerpt) Attribution {
+	if e.Anukramani != nil {
+		return *e.Anukramani
+	}
+	return Attribution{Rsis: e.Authors, Devatas: e.Addressees, Chandas: e.Meter}
+}
+
+// names returns the names of a kind in the attribution.
+func (a Attribution) names(kind AttributionKind) []string {
+	switch kind {
+	case AttributionSeers:
+		return a.Rsis
+	case AttributionDeities:
+		return a.Devatas
+	case AttributionFamilies:
+		if a.Family != "" {
+			return []string{a.Family}
+		}
+	case AttributionChandas:
+		if a.Chandas != "" {
+			return []string{a.Chandas}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package excerpts
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAnukramani(t *testing.T) {
+	hymns, err := ReadAnukramani(strings.NewReader(`{"hymn": "7.18", "rsi": ["Vasistha"], "family": "Vasistha", "devata": ["Indra"], "chandas": "Tristubh", "verses": [{"verses": "22-25", "devata": ["Sudas"]}]}
+
+{"hymn": "1.1", "rsi": ["Madhucchandas"], "devata": ["Agni"], "chandas": "Gayatri"}
+`))
+	assert.NoError(t, err)
+	assert.Len(t, hymns, 2)
+	assert.Equal(t, []string{"Vasistha"}, hymns["7.18"].Rsis)
+	assert.Equal(t, "22-25", hymns["7.18"].Verses[0].Verses)
+	assert.Equal(t, []string{"Sudas"}, hymns["7.18"].Verses[0].Devatas)
+
+	_, err = ReadAnukramani(strings.NewReader(`{"hymn": "1.1"}
+{"hymn": "1.2", "verses": [{"verses": "5-3"}]}`))
+	assert.ErrorContains(t, err, "line 2: invalid verses")
+
+	_, err = ReadAnukramani(strings.NewReader(`{"hymn": "one"}`))
+	assert.ErrorContains(t, err, "line 1: invalid hymn")
+}
+
+func TestApplyAnukramani(t *testing.T) {
+	hymns := map[string]AnukramaniHymn{
+		"7.18": {
+			Hymn:        "7.18",
+			Attribution: Attribution{Rsis: []string{"Vasistha"}, Devatas: []string{"Indra"}, Chandas: "Tristubh", Family: "Vasistha"},
+			Verses: []AnukramaniVerses{
+				{Verses: "22-23", Attribution: Attribution{Devatas: []string{"Sudas"}}},
+				{Verses: "23", Attribution: Attribution{Chandas: "Jagati"}},
+			},
+		},
+	}
+	es := []Excerpt{{Path: []int{7, 18, 1}}, {Path: []int{7, 18, 22}}, {Path: []int{7, 18, 23}}, {Path: []int{7, 19, 1}}}
+	assert.Equal(t, 3, applyAnukramani(es, hymns))
+	assert.Equal(t, []string{"Indra"}, es[0].Anukramani.Devatas)
+	assert.Equal(t, []string{"Sudas"}, es[1].Anukramani.Devatas)
+	assert.Equal(t, "Tristubh", es[1].Anukramani.Chandas)
+	assert.Equal(t, Attribution{Rsis: []string{"Vasistha"}, Devatas: []string{"Sudas"}, Chandas: "Jagati", Family: "Vasistha"}, *es[2].Anukramani)
+	assert.Nil(t, es[3].Anukramani)
+}
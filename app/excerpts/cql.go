@@ -0,0 +1,519 @@
+package excerpts
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+)
+
+// maxCQLRepeat bounds the repetition of a single token pattern, including open ended
+// quantifiers like `*` and `+`.
+const maxCQLRepeat = 8
+
+// maxCQLSpan bounds the number of tokens a pattern can match, ie: the sum of the maximum
+// repetitions of its items.
+const maxCQLSpan = 32
+
+type CQLCondOp int
+
+const (
+	CQLLeaf CQLCondOp = iota
+	CQLAnd
+	CQLOr
+	CQLNot
+)
+
+// CQLCond is a boolean condition on the features of a single token, eg: `lemma="indra" & case!="VOC"`.
+type CQLCond struct {
+	Op       CQLCondOp
+	Children []*CQLCond
+
+	Feature MorphFeature
+	// Value is a regular expression which must match the whole feature value.
+	Value string
+	// Negated is true for `!=` comparisons.
+	Negated bool
+
+	re *regexp.Regexp
+}
+
+// CQLItem is one bracketed token pattern of a sequence, with its repetition bounds.
+type CQLItem struct {
+	// Cond is nil for `[]`, which matches any token.
+	Cond *CQLCond
+	Min  int
+	Max  int
+}
+
+// CQLPattern is a sequence of token patterns, eg: `[lemma="indra"][case="GEN"]`.
+type CQLPattern struct {
+	Items []CQLItem
+}
+
+// Leaves returns all feature comparisons in the pattern.
+func (p *CQLPattern) Leaves() []*CQLCond {
+	var res []*CQLCond
+	var walk func(c *CQLCond)
+	walk = func(c *CQLCond) {
+		if c.Op == CQLLeaf {
+			res = append(res, c)
+			return
+		}
+		for _, ch := range c.Children {
+			walk(ch)
+		}
+	}
+	for _, it := range p.Items {
+		if it.Cond != nil {
+			walk(it.Cond)
+		}
+	}
+	return res
+}
+
+// Compile (re)compiles the regular expressions of all comparisons. It must be called again
+// after modifying the values, eg: when transliterating them.
+func (p *CQLPattern) Compile() error {
+	for _, l := range p.Leaves() {
+		re, err := regexp.Compile(l.regexSource())
+		if err != nil {
+			return newQueryError("invalid regular expression %q for %s", l.Value, l.Feature)
+		}
+		l.re = re
+	}
+	return nil
+}
+
+// regexSource returns the anchored regular expression for a comparison. Sanskrit values
+// are matched without accents, except for the accented surface, and grammatical tags are
+// matched case insensitively.
+func (c *CQLCond) regexSource() string {
+	if c.Feature == MorphAccented {
+		return "^(?:" + common.NormalizeAccents(c.Value) + ")$"
+	}
+	if c.Feature.IsSanskrit() {
+		return "^(?:" + common.FoldAccents(c.Value) + ")$"
+	}
+	return "(?i)^(?:" + c.Value + ")$"
+}
+
+// isLiteral reports whether the value has no regular expression syntax, so that it can be
+// compared for equality.
+func (c *CQLCond) isLiteral() bool {
+	return regexp.QuoteMeta(c.Value) == c.Value
+}
+
+func (c *CQLCond) matches(g *WordGlossing) bool {
+	switch c.Op {
+	case CQLAnd:
+		for _, ch := range c.Children {
+			if !ch.matches(g) {
+				return false
+			}
+		}
+		return true
+	case CQLOr:
+		for _, ch := range c.Children {
+			if ch.matches(g) {
+				return true
+			}
+		}
+		return false
+	case CQLNot:
+		return !c.Children[0].matches(g)
+	}
+	return c.re.MatchString(glossingFeature(g, c.Feature)) != c.Negated
+}
+
+// Match returns the tokens of all non-overlapping matches of the pattern in the excerpt.
+// The glossings of all lines are treated as one sequence, so a match may span lines.
+func (p *CQLPattern) Match(e *Excerpt) []TokenRef {
+	var refs []TokenRef
+	m := cqlMatcher{p: p}
+	for line := range e.Glossings {
+		for pos := range e.Glossings[line] {
+			refs = append(refs, TokenRef{Line: line, Position: pos})
+			m.glossings = append(m.glossings, &e.Glossings[line][pos])
+		}
+	}
+	m.ends = make([]int, (len(p.Items)+1)*(len(m.glossings)+1))
+	for i := range m.ends {
+		m.ends[i] = cqlUnknown
+	}
+
+	var matched []TokenRef
+	for start := 0; start < len(m.glossings); {
+		end := m.matchFrom(0, start)
+		if end > start {
+			matched = append(matched, refs[start:end]...)
+			start = end
+		} else {
+			start++
+		}
+	}
+	return matched
+}
+
+// cqlUnknown marks the states of a cqlMatcher which are not computed yet.
+const cqlUnknown = -2
+
+// cqlMatcher matches a pattern against the tokens of an excerpt. The end of the match of the
+// items from an item at a token does not depend on how that state was reached, so it is
+// computed once, and a match takes O(items * tokens * maxCQLRepeat) steps.
+type cqlMatcher struct {
+	p         *CQLPattern
+	glossings []*WordGlossing
+	// ends holds the result of matchFrom for each item and token, or cqlUnknown.
+	ends []int
+}
+
+// matchFrom matches the items starting at item against the tokens starting at pos, and
+// returns the end of the match, or -1. Repetitions are greedy with backtracking.
+func (m *cqlMatcher) matchFrom(item int, pos int) int {
+	if item == len(m.p.Items) {
+		return pos
+	}
+	state := item*(len(m.glossings)+1) + pos
+	if m.ends[state] != cqlUnknown {
+		return m.ends[state]
+	}
+	it := m.p.Items[item]
+	n := 0
+	for n < it.Max && pos+n < len(m.glossings) && (it.Cond == nil || it.Cond.matches(m.glossings[pos+n])) {
+		n++
+	}
+	end := -1
+	for k := n; k >= it.Min; k-- {
+		if end = m.matchFrom(item+1, pos+k); end >= 0 {
+			break
+		}
+	}
+	m.ends[state] = end
+	return end
+}
+
+type cqlParser struct {
+	input  []rune
+	pos    int
+	nConds int
+}
+
+// ParseCQL parses a CQL (CQP) style sequence query over glossing tokens.
+//
+// Each token pattern is written in brackets, eg: `[lemma="indra" & case!="VOC"]`, with
+// conditions combined using `&`, `|`, `!` and parentheses. Values are regular expressions
+// which must match the whole feature value. `[]` matches any token, and a pattern may be
+// followed by a quantifier `?`, `*`, `+`, `{n}` or `{n,m}`. Errors are returned as UserVisibleError.
+func ParseCQL(q string) (*CQLPattern, error) {
+	p := &cqlParser{input: []rune(q)}
+	var pattern CQLPattern
+	hasConstraint := false
+	span := 0
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) {
+			break
+		}
+		if len(pattern.Items) >= maxQueryTerms {
+			return nil, newQueryError("too many token patterns, at most %d are allowed", maxQueryTerms)
+		}
+		item, err := p.parseItem()
+		if err != nil {
+			return nil, err
+		}
+		if item.Cond != nil && item.Min > 0 {
+			hasConstraint = true
+		}
+		span += item.Max
+		if span > maxCQLSpan {
+			return nil, newQueryError("the pattern can match too many tokens, at most %d are allowed", maxCQLSpan)
+		}
+		pattern.Items = append(pattern.Items, item)
+	}
+	if len(pattern.Items) == 0 {
+		return nil, newQueryError("query is empty")
+	}
+	if !hasConstraint {
+		return nil, newQueryError("at least one token pattern must have a condition and not be optional")
+	}
+	if err := pattern.Compile(); err != nil {
+		return nil, err
+	}
+	return &pattern, nil
+}
+
+func (p *cqlParser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *cqlParser) peek() rune {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *cqlParser) expect(r rune) error {
+	if p.peek() != r {
+		if p.pos >= len(p.input) {
+			return newQueryError("expected %q at end of query", r)
+		}
+		return newQueryError("expected %q at position %d", r, p.pos+1)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *cqlParser) parseItem() (CQLItem, error) {
+	if err := p.expect('['); err != nil {
+		return CQLItem{}, err
+	}
+	item := CQLItem{Min: 1, Max: 1}
+	if p.peek() != ']' {
+		cond, err := p.parseOr()
+		if err != nil {
+			return CQLItem{}, err
+		}
+		item.Cond = cond
+	}
+	if err := p.expect(']'); err != nil {
+		return CQLItem{}, err
+	}
+
+	switch p.peek() {
+	case '?':
+		p.pos++
+		item.Min, item.Max = 0, 1
+	case '*':
+		p.pos++
+		item.Min, item.Max = 0, maxCQLRepeat
+	case '+':
+		p.pos++
+		item.Min, item.Max = 1, maxCQLRepeat
+	case '{':
+		start := p.pos
+		p.pos++
+		end := start
+		for end < len(p.input) && p.input[end] != '}' {
+			end++
+		}
+		if end >= len(p.input) {
+			return CQLItem{}, newQueryError("unterminated quantifier at position %d", start+1)
+		}
+		spec := string(p.input[start+1 : end])
+		p.pos = end + 1
+		minStr, maxStr, isRange := strings.Cut(spec, ",")
+		minN, err := strconv.Atoi(strings.TrimSpace(minStr))
+		if err != nil || minN < 0 {
+			return CQLItem{}, newQueryError("invalid quantifier {%s} at position %d", spec, start+1)
+		}
+		maxN := minN
+		if isRange {
+			maxN = maxCQLRepeat
+			if strings.TrimSpace(maxStr) != "" {
+				maxN, err = strconv.Atoi(strings.TrimSpace(maxStr))
+				if err != nil || maxN < minN {
+					return CQLItem{}, newQueryError("invalid quantifier {%s} at position %d", spec, start+1)
+				}
+			}
+		}
+		if maxN > maxCQLRepeat || maxN == 0 {
+			return CQLItem{}, newQueryError("quantifier {%s} must allow between 1 and %d repetitions", spec, maxCQLRepeat)
+		}
+		item.Min, item.Max = minN, maxN
+	}
+	return item, nil
+}
+
+func (p *cqlParser) parseOr() (*CQLCond, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	children := []*CQLCond{first}
+	for p.peek() == '|' {
+		p.pos++
+		c, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, c)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &CQLCond{Op: CQLOr, Children: children}, nil
+}
+
+func (p *cqlParser) parseAnd() (*CQLCond, error) {
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	children := []*CQLCond{first}
+	for p.peek() == '&' {
+		p.pos++
+		c, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, c)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &CQLCond{Op: CQLAnd, Children: children}, nil
+}
+
+func (p *cqlParser) parseUnary() (*CQLCond, error) {
+	switch p.peek() {
+	case '!':
+		p.pos++
+		c, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &CQLCond{Op: CQLNot, Children: []*CQLCond{c}}, nil
+	case '(':
+		p.pos++
+		c, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(')'); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *cqlParser) parseComparison() (*CQLCond, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.input) && (unicode.IsLetter(p.input[p.pos]) || p.input[p.pos] == '_') {
+		p.pos++
+	}
+	name := strings.ToLower(string(p.input[start:p.pos]))
+	if name == "" {
+		if p.pos >= len(p.input) {
+			return nil, newQueryError("unexpected end of query, expected a feature name")
+		}
+		return nil, newQueryError("expected a feature name at position %d", start+1)
+	}
+	feature, err := parseCQLFeature(name)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CQLCond{Op: CQLLeaf, Feature: feature}
+	switch p.peek() {
+	case '=':
+		p.pos++
+	case '!':
+		p.pos++
+		if err := p.expect('='); err != nil {
+			return nil, err
+		}
+		c.Negated = true
+	default:
+		return nil, newQueryError("expected = or != after %q at position %d", name, p.pos+1)
+	}
+
+	quote := p.peek()
+	if quote != '"' && quote != '\'' {
+		return nil, newQueryError("expected a quoted value at position %d", p.pos+1)
+	}
+	valueStart := p.pos
+	p.pos++
+	var sb strings.Builder
+	for p.pos < len(p.input) && p.input[p.pos] != quote {
+		// \" and \' escape the quote, other escapes are kept for the regular expression
+		if p.input[p.pos] == '\\' && p.pos+1 < len(p.input) && p.input[p.pos+1] == quote {
+			p.pos++
+		}
+		sb.WriteRune(p.input[p.pos])
+		p.pos++
+	}
+	if p.pos >= len(p.input) {
+		return nil, newQueryError("unterminated value starting at position %d", valueStart+1)
+	}
+	p.pos++
+	c.Value = sb.String()
+
+	p.nConds++
+	if p.nConds > maxQueryTerms {
+		return nil, newQueryError("too many conditions, at most %d are allowed", maxQueryTerms)
+	}
+	return c, nil
+}
+
+func parseCQLFeature(name string) (MorphFeature, error) {
+	if name == "word" {
+		return MorphSurface, nil
+	}
+	for _, f := range knownMorphFeatures {
+		if f != MorphPath && string(f) == name {
+			return f, nil
+		}
+	}
+	known := []string{"word"}
+	for _, f := range knownMorphFeatures {
+		if f != MorphPath {
+			known = append(known, string(f))
+		}
+	}
+	return "", newQueryError("unknown feature %q, expected one of: %s", name, strings.Join(known, ", "))
+}
+
+// String renders the pattern back in CQL syntax.
+func (p *CQLPattern) String() string {
+	var sb strings.Builder
+	for _, it := range p.Items {
+		sb.WriteString("[")
+		if it.Cond != nil {
+			sb.WriteString(it.Cond.String())
+		}
+		sb.WriteString("]")
+		switch {
+		case it.Min == 1 && it.Max == 1:
+		case it.Min == 0 && it.Max == 1:
+			sb.WriteString("?")
+		case it.Min == it.Max:
+			fmt.Fprintf(&sb, "{%d}", it.Min)
+		default:
+			fmt.Fprintf(&sb, "{%d,%d}", it.Min, it.Max)
+		}
+	}
+	return sb.String()
+}
+
+func (c *CQLCond) String() string {
+	switch c.Op {
+	case CQLLeaf:
+		op := "="
+		if c.Negated {
+			op = "!="
+		}
+		return string(c.Feature) + op + strconv.Quote(c.Value)
+	case CQLNot:
+		return "!" + c.Children[0].String()
+	default:
+		op := " & "
+		if c.Op == CQLOr {
+			op = " | "
+		}
+		parts := make([]string, len(c.Children))
+		for i, ch := range c.Children {
+			parts[i] = ch.String()
+		}
+		return "(" + strings.Join(parts, op) + ")"
+	}
+}
@@ -0,0 +1,101 @@
+package excerpts
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCQL(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{`[lemma="indra"][case="GEN"]`, `[lemma="indra"][case="GEN"]`},
+		{`[gramm=".*VOC.*"]{2}`, `[gramm=".*VOC.*"]{2}`},
+		{`[word='agnim' | lemma="soma"] [] ? [case!="NOM"]{1,3}`, `[(surface="agnim" | lemma="soma")][]?[case!="NOM"]{1,3}`},
+		{`[!(case="VOC" & number="SG")]+`, `[!(case="VOC" & number="SG")]{1,8}`},
+	}
+	for _, tc := range testCases {
+		p, err := ParseCQL(tc.query)
+		assert.NoError(t, err)
+		if p != nil {
+			assert.Equal(t, tc.expected, p.String())
+		}
+	}
+
+	for _, q := range []string{"", "[]", `[deity="agni"]`, `[lemma="agni"`, `[lemma=agni]`, `[case="GEN"]{20}`, `[lemma="("]`, strings.Repeat("[]*", 4) + `[lemma="agni"]`} {
+		_, err := ParseCQL(q)
+		var uve *common.UserVisibleError
+		assert.True(t, errors.As(err, &uve), "expected a UserVisibleError for %q, got %v", q, err)
+	}
+}
+
+func TestCQLPattern_Match(t *testing.T) {
+	e := &Excerpt{
+		Glossings: [][]WordGlossing{
+			{{Lemma: "índra-", Case: "VOC"}, {Lemma: "sóma-", Case: "GEN"}},
+			{{Lemma: "mitrá-", Case: "VOC"}, {Lemma: "váruṇa-", Case: "VOC"}, {Lemma: "√pā", Case: ""}},
+		},
+	}
+	p, err := ParseCQL(`[lemma="indra"][case="GEN"]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []TokenRef{{0, 0}, {0, 1}}, p.Match(e))
+
+	// sequences continue across lines
+	p, err = ParseCQL(`[case="gen"][case="VOC"]+`)
+	assert.NoError(t, err)
+	assert.Equal(t, []TokenRef{{0, 1}, {1, 0}, {1, 1}}, p.Match(e))
+
+	p, err = ParseCQL(`[case="VOC"]{2}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []TokenRef{{1, 0}, {1, 1}}, p.Match(e))
+
+	p, err = ParseCQL(`[gramm=".*VOC.*"]{2}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []TokenRef{{1, 0}, {1, 1}}, p.Match(e))
+
+	p, err = ParseCQL(`[lemma="soma"][lemma="pā"]`)
+	assert.NoError(t, err)
+	assert.Empty(t, p.Match(e))
+}
+
+func TestCQLPattern_MatchBacktracking(t *testing.T) {
+	line := make([]WordGlossing, 200)
+	for i := range line {
+		line[i] = WordGlossing{Lemma: "agní-"}
+	}
+	e := &Excerpt{Glossings: [][]WordGlossing{line}}
+
+	// fails at every split of the tokens between the open ended items
+	p, err := ParseCQL(`[]*[]*[]*[lemma="agni"]{1,7}[lemma="zzz"]`)
+	assert.NoError(t, err)
+	assert.Empty(t, p.Match(e))
+
+	p, err = ParseCQL(`[]*[lemma="agni"][lemma="agni"]`)
+	assert.NoError(t, err)
+	assert.Len(t, p.Match(e), 200)
+}
+
+func TestCQLPattern_MatchAccents(t *testing.T) {
+	e := &Excerpt{
+		Glossings: [][]WordGlossing{
+			{{Surface: "ágne", Case: "VOC"}, {Surface: "agne", Case: "VOC"}, {Surface: "agním", Case: "ACC"}},
+		},
+	}
+	p, err := ParseCQL(`[accented="ágne"]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []TokenRef{{0, 0}}, p.Match(e))
+
+	// as converted from SLP1 agni/m
+	p, err = ParseCQL("[accented=\"agni\u0301m\"]")
+	assert.NoError(t, err)
+	assert.Equal(t, []TokenRef{{0, 2}}, p.Match(e))
+
+	p, err = ParseCQL(`[case="VOC" & accent="unaccented"]`)
+	assert.NoError(t, err)
+	assert.Equal(t, []TokenRef{{0, 1}}, p.Match(e))
+}
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"html"
 	"log/slog"
+	"net/http"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"github.com/mahesh-hegde/dhee/app/common"
 	"github.com/mahesh-hegde/dhee/app/config"
 	"github.com/mahesh-hegde/dhee/app/dictionary"
+	"github.com/mahesh-hegde/dhee/app/prosody"
 	"github.com/mahesh-hegde/dhee/app/transliteration"
 	"github.com/patrickmn/go-cache"
 )
@@ -92,52 +95,21 @@ func (s *ExcerptService) Get(ctx context.Context, paths []QualifiedPath) (*Excer
 		words = append(words, slp1)
 	}
 
-	// Fetch dictionary entries.
-	dictName := s.conf.DefaultDict
-
-	var dictEntries []dictionary.DictionaryEntry
-	var wordsToQuery []string
-
-	if s.wordCache != nil {
-		cachedCount := 0
-		for _, w := range words {
-			if item, found := s.wordCache.Get(w); found {
-				if entry, ok := item.(dictionary.DictionaryEntry); ok {
-					dictEntries = append(dictEntries, entry)
-					cachedCount++
-				}
-			} else {
-				wordsToQuery = append(wordsToQuery, w)
-			}
-		}
-		// slog.Info("dictionary cache hit", "found", cachedCount, "total", len(words))
-	} else {
-		wordsToQuery = words
+	// Fetch dictionary entries from the dictionaries of the scripture, in order.
+	scriptureName := paths[0].Scripture
+	scri := s.scriptureMap[scriptureName]
+	dictNames := s.conf.LookupDictionaries(&scri)
+	wordMaps, err := s.lookupWords(ctx, dictNames, words, scri.DictionaryLookup == config.LookupFallback)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(wordsToQuery) > 0 {
-		fetchedEntries, err := s.ds.Get(ctx, dictName, wordsToQuery)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get dictionary entries: %w", err)
-		}
-		if s.wordCache != nil {
-			for _, entry := range fetchedEntries {
-				s.wordCache.Set(entry.Word, entry, cache.DefaultExpiration)
-				dictEntries = append(dictEntries, entry)
-			}
-		} else {
-			for _, entry := range fetchedEntries {
-				dictEntries = append(dictEntries, entry)
-			}
+	readableNames := make(map[string]string, len(dictNames))
+	for _, dictName := range dictNames {
+		if dict := s.conf.GetDictByName(dictName); dict != nil {
+			readableNames[dictName] = dict.ReadableName
 		}
 	}
 
-	// Map words to their dictionary entries for quick lookup
-	wordMap := make(map[string]dictionary.DictionaryEntry)
-	for _, entry := range dictEntries {
-		wordMap[entry.Word] = entry
-	}
-
 	// Combine excerpts with their word meanings
 	var es []ExcerptWithWords
 	for eidx, e := range excerpts {
@@ -146,23 +118,44 @@ func (s *ExcerptService) Get(ctx context.Context, paths []QualifiedPath) (*Excer
 			Words:   make(map[string]dictionary.DictionaryEntry),
 			Padas:   make([]PadaElement, 0),
 		}
+		if len(e.Formulas) > 0 {
+			ew.FormulaHl = highlightTokenLines(&e, e.FormulaTokens())
+		}
 		var glossingPEs []PadaElement
 		for _, g := range e.Glossings {
 			for _, gl := range g {
 				var lemmaMeaning, surfaceMeaning dictionary.DictionaryEntry
+				var meanings []SourcedMeaning
 				foldedSurface := common.NormalizeSurface(gl.Surface)
 				slpWord := wordsToFetch[foldedSurface]
-				if entry, ok := wordMap[slpWord]; ok {
-					surfaceMeaning = entry
-					ew.Words[gl.Surface] = entry
-				}
 				lemma := common.NormalizeLemma(gl.Lemma)
 				slpLemma := wordsToFetch[lemma]
-				if entry, ok := wordMap[slpLemma]; ok {
-					if slpWord != slpLemma {
-						lemmaMeaning = entry
+				for _, dictName := range dictNames {
+					sm := SourcedMeaning{DictName: dictName, ReadableName: readableNames[dictName]}
+					if entry, ok := wordMaps[dictName][slpWord]; ok {
+						sm.SurfaceMeaning = entry
+						if _, seen := ew.Words[gl.Surface]; !seen {
+							ew.Words[gl.Surface] = entry
+						}
 					}
-					ew.Words[gl.Lemma] = entry
+					if entry, ok := wordMaps[dictName][slpLemma]; ok {
+						if slpWord != slpLemma {
+							sm.LemmaMeaning = entry
+						}
+						if _, seen := ew.Words[gl.Lemma]; !seen {
+							ew.Words[gl.Lemma] = entry
+						}
+					}
+					if sm.SurfaceMeaning.Word == "" && sm.LemmaMeaning.Word == "" {
+						continue
+					}
+					if surfaceMeaning.Word == "" {
+						surfaceMeaning = sm.SurfaceMeaning
+					}
+					if lemmaMeaning.Word == "" {
+						lemmaMeaning = sm.LemmaMeaning
+					}
+					meanings = append(meanings, sm)
 				}
 
 				glossingPEs = append(glossingPEs, PadaElement{
@@ -173,6 +166,7 @@ func (s *ExcerptService) Get(ctx context.Context, paths []QualifiedPath) (*Excer
 					Slp1NormSurface: slpWord,
 					SurfaceMeaning:  surfaceMeaning,
 					LemmaMeaning:    lemmaMeaning,
+					Meanings:        meanings,
 				})
 			}
 		}
@@ -227,6 +221,10 @@ func (s *ExcerptService) Get(ctx context.Context, paths []QualifiedPath) (*Excer
 		es = append(es, ew)
 	}
 
+	if err := s.addCitations(ctx, es); err != nil {
+		return nil, err
+	}
+
 	sort.Slice(es, func(i, j int) bool {
 		p1 := es[i].Path
 		p2 := es[j].Path
@@ -263,10 +261,6 @@ func (s *ExcerptService) Get(ctx context.Context, paths []QualifiedPath) (*Excer
 
 	prev, next := s.store.FindBeforeAndAfter(ctx, paths[0].Scripture, beforeIds, afterIds)
 
-	// Combine excerpt with its scripture information
-	scriptureName := paths[0].Scripture
-	scri := s.scriptureMap[scriptureName]
-
 	return &ExcerptTemplateData{
 		Excerpts:        es,
 		AddressedTo:     strings.Join(es[0].Addressees, ", "),
@@ -279,9 +273,211 @@ func (s *ExcerptService) Get(ctx context.Context, paths []QualifiedPath) (*Excer
 	}, nil
 }
 
-// Search returns upto 100 Excerpts which match the search according to search parameters.
+// lookupWords returns the entries of the words in each of the dictionaries, by dictionary and
+// word. With fallback, a word is looked up only until a dictionary has it.
+func (s *ExcerptService) lookupWords(ctx context.Context, dictNames []string, words []string, fallback bool) (map[string]map[string]dictionary.DictionaryEntry, error) {
+	wordMaps := make(map[string]map[string]dictionary.DictionaryEntry, len(dictNames))
+	remaining := words
+	for _, dictName := range dictNames {
+		wordMap := make(map[string]dictionary.DictionaryEntry)
+		wordMaps[dictName] = wordMap
+
+		var wordsToQuery []string
+		for _, w := range remaining {
+			if s.wordCache != nil {
+				if item, found := s.wordCache.Get(dictName + "/" + w); found {
+					if entry, ok := item.(dictionary.DictionaryEntry); ok {
+						wordMap[w] = entry
+					}
+					continue
+				}
+			}
+			wordsToQuery = append(wordsToQuery, w)
+		}
+
+		if len(wordsToQuery) > 0 {
+			fetchedEntries, err := s.ds.Get(ctx, dictName, wordsToQuery)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get dictionary entries: %w", err)
+			}
+			for _, entry := range fetchedEntries {
+				if s.wordCache != nil {
+					s.wordCache.Set(dictName+"/"+entry.Word, entry, cache.DefaultExpiration)
+				}
+				wordMap[entry.Word] = entry
+			}
+		}
+
+		if fallback {
+			var missing []string
+			for _, w := range remaining {
+				if _, ok := wordMap[w]; !ok {
+					missing = append(missing, w)
+				}
+			}
+			remaining = missing
+		}
+	}
+	return wordMaps, nil
+}
+
+// Search returns a page of Excerpts which match the search according to search parameters,
+// along with the total number of matches and facet counts over all of them.
 func (s *ExcerptService) Search(ctx context.Context, search SearchParams) (*ExcerptSearchData, error) {
-	if search.Mode != common.SearchTranslations {
+	search, err := s.prepareSearch(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	excerpts, total, err := s.searchPage(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	pagination := common.NewPagination(search.Page)
+	pagination.Total = total
+
+	// Facets scan all matches again, so they are only counted for the first page.
+	var facets []Facet
+	if pagination.Page == 1 {
+		facets, err = s.store.Facets(ctx, search.Scriptures, search)
+		if err != nil {
+			return nil, common.WrapErrorForResponse(err, "failed to count facets")
+		}
+	}
+	return &ExcerptSearchData{
+		Excerpts:   excerpts,
+		Search:     search,
+		Scripture:  s.searchScripture(search),
+		Facets:     facets,
+		Pagination: pagination,
+	}, nil
+}
+
+// searchScripture returns the scripture searched, or an empty definition when searching
+// several scriptures.
+func (s *ExcerptService) searchScripture(search SearchParams) config.ScriptureDefn {
+	if len(search.Scriptures) == 1 {
+		if scri := s.conf.GetScriptureByName(search.Scriptures[0]); scri != nil {
+			return *scri
+		}
+	}
+	return config.ScriptureDefn{}
+}
+
+// KWIC returns a page of search results laid out as a keyword in context concordance. Facets
+// are not counted, since they would scan all matches again.
+func (s *ExcerptService) KWIC(ctx context.Context, search SearchParams, opts KWICOptions) (*KWICData, error) {
+	search, err := s.prepareSearch(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	excerpts, total, err := s.searchPage(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	lines := BuildKWIC(excerpts, opts.Context)
+	SortKWIC(lines, opts.Sort)
+
+	pagination := common.NewPagination(search.Page)
+	pagination.Total = total
+	data := ExcerptSearchData{
+		Excerpts:   excerpts,
+		Search:     search,
+		Scripture:  s.searchScripture(search),
+		Pagination: pagination,
+	}
+	return &KWICData{ExcerptSearchData: data, Options: opts, Lines: lines}, nil
+}
+
+// ExportKWIC returns the concordance lines over all pages of search results, up to
+// maxKWICExportPages pages.
+func (s *ExcerptService) ExportKWIC(ctx context.Context, search SearchParams, opts KWICOptions) ([]KWICLine, error) {
+	search, err := s.prepareSearch(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	var lines []KWICLine
+	for page := 1; page <= maxKWICExportPages; page++ {
+		search.Page = page
+		excerpts, total, err := s.searchPage(ctx, search)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, BuildKWIC(excerpts, opts.Context)...)
+		if page*common.SearchPageSize >= total {
+			break
+		}
+	}
+	SortKWIC(lines, opts.Sort)
+	return lines, nil
+}
+
+// prepareSearch parses the query and scope according to the search mode, transliterates
+// sanskrit terms to IAST and expands fuzzy queries, so that the prepared search can be run
+// for several pages and the facets.
+func (s *ExcerptService) prepareSearch(ctx context.Context, search SearchParams) (SearchParams, error) {
+	if search.Mode == common.SearchQuery {
+		parsed, err := ParseQuery(search.Q)
+		if err != nil {
+			return search, err
+		}
+		// Only sanskrit terms are transliterated, since field names, operators and
+		// english terms would be mangled by the transliterator.
+		for _, term := range parsed.Terms(false) {
+			if !term.Field.IsSanskrit() {
+				continue
+			}
+			iastTerm, err := s.transliterator.Convert(term.Text, common.Transliteration(search.Tl), common.TlIAST)
+			if err != nil {
+				slog.Warn("transliteration failed for query term", "term", term.Text, "err", err)
+				continue
+			}
+			term.Text = iastTerm
+		}
+		search.OriginalQ = search.Q
+		search.parsed = parsed
+	} else if search.Mode == common.SearchMorph {
+		mq, err := ParseMorphQuery(search.Q)
+		if err != nil {
+			return search, err
+		}
+		for _, c := range mq.Constraints {
+			if !c.Feature.IsSanskrit() {
+				continue
+			}
+			for i, v := range c.Values {
+				iastValue, err := s.transliterator.Convert(v, common.Transliteration(search.Tl), common.TlIAST)
+				if err != nil {
+					slog.Warn("transliteration failed for morphological query", "value", v, "err", err)
+					continue
+				}
+				c.Values[i] = iastValue
+			}
+		}
+		search.OriginalQ = search.Q
+		search.morph = mq
+	} else if search.Mode == common.SearchCQL {
+		pattern, err := ParseCQL(search.Q)
+		if err != nil {
+			return search, err
+		}
+		// Regular expressions are written in IAST, since the transliterator would mangle their syntax.
+		for _, l := range pattern.Leaves() {
+			if !l.Feature.IsSanskrit() || !l.isLiteral() {
+				continue
+			}
+			iastValue, err := s.transliterator.Convert(l.Value, common.Transliteration(search.Tl), common.TlIAST)
+			if err != nil {
+				slog.Warn("transliteration failed for CQL value", "value", l.Value, "err", err)
+				continue
+			}
+			l.Value = iastValue
+		}
+		if err := pattern.Compile(); err != nil {
+			return search, err
+		}
+		search.OriginalQ = search.Q
+		search.cql = pattern
+	} else if search.Mode != common.SearchTranslations {
 		iastQuery, err := s.transliterator.Convert(search.Q, common.Transliteration(search.Tl), common.TlIAST)
 		if err != nil {
 			slog.Warn("transliteration failed for scripture search", "query", search.Q, "err", err)
@@ -290,7 +486,55 @@ func (s *ExcerptService) Search(ctx context.Context, search SearchParams) (*Exce
 		search.OriginalQ = search.Q
 		search.Q = iastQuery
 	}
+	if search.Mode == common.SearchFuzzy {
+		fq, err := s.store.ExpandFuzzy(ctx, search.Q)
+		if err != nil {
+			return search, common.WrapErrorForResponse(err, "failed to expand fuzzy query")
+		}
+		search.fuzzy = fq
+	}
+
+	if search.Scope != "" {
+		scope, err := ParseScope(search.Scope, s.scripturesByName(search.Scriptures))
+		if err != nil {
+			return search, err
+		}
+		search.scope = scope
+	}
+	return search, nil
+}
+
+// addCitations sets the dictionary words citing each excerpt, or one of its ancestors, eg:
+// the hymn, for references without a verse number.
+func (s *ExcerptService) addCitations(ctx context.Context, es []ExcerptWithWords) error {
+	pathsByScripture := make(map[string][]string)
+	for _, e := range es {
+		for n := len(e.Path); n > 0; n-- {
+			pathsByScripture[e.Scripture] = append(pathsByScripture[e.Scripture], common.PathToString(e.Path[:n]))
+		}
+	}
+	for scripture, paths := range pathsByScripture {
+		slices.Sort(paths)
+		citing, err := s.ds.Citing(ctx, scripture, slices.Compact(paths))
+		if err != nil {
+			return fmt.Errorf("failed to get dictionary citations: %w", err)
+		}
+		for i := range es {
+			if es[i].Scripture != scripture {
+				continue
+			}
+			// the citations of the verse first, then of the wider units
+			for n := len(es[i].Path); n > 0; n-- {
+				es[i].CitedIn = append(es[i].CitedIn, citing[common.PathToString(es[i].Path[:n])]...)
+			}
+		}
+	}
+	return nil
+}
 
+// searchPage returns the requested page of matches of a prepared search, with the matches
+// highlighted, and the total number of matches.
+func (s *ExcerptService) searchPage(ctx context.Context, search SearchParams) ([]HighlightedExcerpt, int, error) {
 	var re *regexp.Regexp
 	var err error
 	if search.Mode == "regex" {
@@ -300,12 +544,12 @@ func (s *ExcerptService) Search(ctx context.Context, search SearchParams) (*Exce
 		}
 	}
 
-	excerpts, err := s.store.Search(ctx, search.Scriptures, search)
+	excerpts, total, err := s.store.Search(ctx, search.Scriptures, search)
 	if err != nil {
-		return nil, common.WrapErrorForResponse(err, "failed to search excerpts")
+		return nil, 0, common.WrapErrorForResponse(err, "failed to search excerpts")
 	}
 
-	if search.Mode == "regex" {
+	if search.Mode == "regex" && re != nil {
 		for i := range excerpts {
 			fullRomanText := strings.Join(excerpts[i].Excerpt.RomanText, "\n")
 			escapedText := html.EscapeString(fullRomanText)
@@ -314,15 +558,205 @@ func (s *ExcerptService) Search(ctx context.Context, search SearchParams) (*Exce
 		}
 	}
 
-	scripture := ""
-	if len(search.Scriptures) == 1 {
-		scripture = search.Scriptures[0]
+	if search.Mode == common.SearchMorph || search.Mode == common.SearchCQL || search.Mode == common.SearchAccent {
+		for i := range excerpts {
+			excerpts[i].RomanHl = highlightTokens(&excerpts[i].Excerpt, excerpts[i].Tokens)
+		}
 	}
-	return &ExcerptSearchData{
-		Excerpts:  excerpts,
-		Search:    search,
-		Scripture: *s.conf.GetScriptureByName(scripture),
-	}, nil
+	return excerpts, total, nil
+}
+
+// Stats returns statistics over a scripture, restricted to the scope if it is not empty.
+func (s *ExcerptService) Stats(ctx context.Context, scriptureName string, scope string) (*ScopeStats, error) {
+	scri, ok := s.scriptureMap[scriptureName]
+	if !ok {
+		return nil, common.NewUserVisibleError(http.StatusNotFound, "scripture not found: "+scriptureName)
+	}
+	ranges, err := ParseScope(scope, []config.ScriptureDefn{scri})
+	if err != nil {
+		return nil, err
+	}
+	return s.store.Stats(ctx, scriptureName, ranges)
+}
+
+// Export returns the scripture and its excerpts within path, which is a path, range or verse
+// set as accepted by ParseScope. If limit is positive, paths covering more than limit excerpts
+// are rejected.
+func (s *ExcerptService) Export(ctx context.Context, scriptureName string, path string, limit int) (*config.ScriptureDefn, []Excerpt, error) {
+	scri, ok := s.scriptureMap[scriptureName]
+	if !ok {
+		return nil, nil, common.NewUserVisibleError(http.StatusNotFound, "scripture not found: "+scriptureName)
+	}
+	ranges, err := ParseScope(path, []config.ScriptureDefn{scri})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(ranges) == 0 {
+		return nil, nil, common.NewUserVisibleError(http.StatusBadRequest, "path must not be empty")
+	}
+	storeLimit := 0
+	if limit > 0 {
+		storeLimit = limit + 1
+	}
+	es, err := s.store.GetRange(ctx, scriptureName, ranges, storeLimit)
+	if err != nil {
+		return nil, nil, common.WrapErrorForResponse(err, "failed to get excerpts")
+	}
+	if len(es) == 0 {
+		return nil, nil, common.NewUserVisibleError(http.StatusNotFound, "no excerpts found in "+path)
+	}
+	if limit > 0 && len(es) > limit {
+		return nil, nil, common.NewUserVisibleError(http.StatusBadRequest,
+			fmt.Sprintf("at most %d excerpts can be exported at once", limit))
+	}
+	return &scri, es, nil
+}
+
+// ListFormulas returns a page of the repeated formulas of a scripture, most frequent first.
+func (s *ExcerptService) ListFormulas(ctx context.Context, scriptureName string, page int) (*FormulaIndexData, error) {
+	scri, ok := s.scriptureMap[scriptureName]
+	if !ok {
+		return nil, common.NewUserVisibleError(http.StatusNotFound, "scripture not found: "+scriptureName)
+	}
+	formulas, total, err := s.store.ListFormulas(ctx, scriptureName, page)
+	if err != nil {
+		return nil, common.WrapErrorForResponse(err, "failed to list formulas")
+	}
+	pagination := common.NewPagination(page)
+	pagination.Total = total
+	return &FormulaIndexData{Scripture: scri, Formulas: formulas, Pagination: pagination}, nil
+}
+
+// GetFormula returns a formula along with a page of its occurrences, highlighted.
+func (s *ExcerptService) GetFormula(ctx context.Context, scriptureName string, id string, page int) (*FormulaData, error) {
+	scri, ok := s.scriptureMap[scriptureName]
+	if !ok {
+		return nil, common.NewUserVisibleError(http.StatusNotFound, "scripture not found: "+scriptureName)
+	}
+	formula, excerpts, total, err := s.store.GetFormula(ctx, scriptureName, id, page)
+	if err != nil {
+		return nil, common.WrapErrorForResponse(err, "failed to get formula")
+	}
+	for i := range excerpts {
+		excerpts[i].RomanHl = highlightTokens(&excerpts[i].Excerpt, excerpts[i].Tokens)
+	}
+	pagination := common.NewPagination(page)
+	pagination.Total = total
+	return &FormulaData{Scripture: scri, Formula: *formula, Excerpts: excerpts, Pagination: pagination}, nil
+}
+
+// ListMeters returns the scansion statistics of the meters of a scripture.
+func (s *ExcerptService) ListMeters(ctx context.Context, scriptureName string) (*MeterIndexData, error) {
+	scri, ok := s.scriptureMap[scriptureName]
+	if !ok {
+		return nil, common.NewUserVisibleError(http.StatusNotFound, "scripture not found: "+scriptureName)
+	}
+	meters, err := s.store.ListMeters(ctx, scriptureName)
+	if err != nil {
+		return nil, common.WrapErrorForResponse(err, "failed to list meters")
+	}
+	for i := range meters {
+		_, meters[i].Checked = prosody.LookupMeter(meters[i].Meter)
+	}
+	return &MeterIndexData{Scripture: scri, Meters: meters}, nil
+}
+
+// GetMeter returns the scansion statistics of a meter along with a page of the verses
+// deviating from it.
+func (s *ExcerptService) GetMeter(ctx context.Context, scriptureName string, meter string, page int) (*MeterData, error) {
+	scri, ok := s.scriptureMap[scriptureName]
+	if !ok {
+		return nil, common.NewUserVisibleError(http.StatusNotFound, "scripture not found: "+scriptureName)
+	}
+	stats, excerpts, total, err := s.store.GetMeter(ctx, scriptureName, meter, page)
+	if err != nil {
+		return nil, common.WrapErrorForResponse(err, "failed to get meter")
+	}
+	_, stats.Checked = prosody.LookupMeter(stats.Meter)
+	pagination := common.NewPagination(page)
+	pagination.Total = total
+	return &MeterData{Scripture: scri, Stats: *stats, Excerpts: excerpts, Pagination: pagination}, nil
+}
+
+// GetAccents summarizes the accentuation of the attested forms of a lemma, given in the
+// transliteration tl. An empty lemma gives an empty summary.
+func (s *ExcerptService) GetAccents(ctx context.Context, scriptureName string, lemma string, tl string) (*AccentData, error) {
+	scri, ok := s.scriptureMap[scriptureName]
+	if !ok {
+		return nil, common.NewUserVisibleError(http.StatusNotFound, "scripture not found: "+scriptureName)
+	}
+	if tl == "" {
+		tl = string(common.TlIAST)
+	}
+	data := &AccentData{Scripture: scri, Lemma: strings.TrimSpace(lemma), Tl: tl}
+	if data.Lemma == "" {
+		return data, nil
+	}
+	iastLemma, err := s.transliterator.Convert(data.Lemma, common.Transliteration(tl), common.TlIAST)
+	if err != nil {
+		slog.Warn("transliteration failed for lemma", "lemma", data.Lemma, "err", err)
+		iastLemma = data.Lemma
+	}
+	forms, err := s.store.AccentForms(ctx, scriptureName, iastLemma)
+	if err != nil {
+		return nil, common.WrapErrorForResponse(err, "failed to get accented forms")
+	}
+	data.Forms = forms
+	data.Total, data.Placements, data.Categories = summarizeAccents(forms)
+	return data, nil
+}
+
+// attributionScripture returns the scripture, checking that kind has an index.
+func (s *ExcerptService) attributionScripture(scriptureName string, kind AttributionKind) (config.ScriptureDefn, error) {
+	scri, ok := s.scriptureMap[scriptureName]
+	if !ok {
+		return scri, common.NewUserVisibleError(http.StatusNotFound, "scripture not found: "+scriptureName)
+	}
+	if !slices.Contains(AttributionIndexKinds, kind) {
+		return scri, common.NewUserVisibleError(http.StatusNotFound, "unknown attribution kind: "+string(kind))
+	}
+	return scri, nil
+}
+
+// ListAttributions lists the seers, deities or families of the hymns of a scripture.
+func (s *ExcerptService) ListAttributions(ctx context.Context, scriptureName string, kind AttributionKind) (*AttributionIndexData, error) {
+	scri, err := s.attributionScripture(scriptureName, kind)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.store.ListAttributions(ctx, scriptureName, kind)
+	if err != nil {
+		return nil, common.WrapErrorForResponse(err, "failed to list attributions")
+	}
+	return &AttributionIndexData{Scripture: scri, Kind: kind, Names: names}, nil
+}
+
+// GetAttribution returns the hymns attributed to a seer, deity or family.
+func (s *ExcerptService) GetAttribution(ctx context.Context, scriptureName string, kind AttributionKind, name string) (*AttributionData, error) {
+	scri, err := s.attributionScripture(scriptureName, kind)
+	if err != nil {
+		return nil, err
+	}
+	hymns, err := s.store.GetAttribution(ctx, scriptureName, kind, name)
+	if err != nil {
+		return nil, common.WrapErrorForResponse(err, "failed to get attribution")
+	}
+	data := &AttributionData{Scripture: scri, Kind: kind, Name: name, Hymns: hymns}
+	for _, h := range hymns {
+		data.Verses += h.Verses
+	}
+	return data, nil
+}
+
+// scripturesByName returns the definitions of the named scriptures, skipping unknown names.
+func (s *ExcerptService) scripturesByName(names []string) []config.ScriptureDefn {
+	var scriptures []config.ScriptureDefn
+	for _, name := range names {
+		if scri, ok := s.scriptureMap[name]; ok {
+			scriptures = append(scriptures, scri)
+		}
+	}
+	return scriptures
 }
 
 // GetHier returns the hierarchy for a given path.
@@ -0,0 +1,82 @@
+package excerpts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/dictionary"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeDictStore struct {
+	dictionary.DictStore
+	words   map[string][]string
+	queried map[string][]string
+}
+
+func (f *fakeDictStore) Get(ctx context.Context, dictName string, words []string) (map[string]dictionary.DictionaryEntry, error) {
+	f.queried[dictName] = append(f.queried[dictName], words...)
+	entries := make(map[string]dictionary.DictionaryEntry)
+	for _, w := range words {
+		for _, have := range f.words[dictName] {
+			if w == have {
+				entries[w] = dictionary.DictionaryEntry{Word: w}
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (f *fakeDictStore) Citing(ctx context.Context, scripture string, paths []string) (map[string][]dictionary.CitingWord, error) {
+	f.queried[scripture] = append(f.queried[scripture], paths...)
+	citing := make(map[string][]dictionary.CitingWord)
+	for _, p := range paths {
+		for _, w := range f.words[p] {
+			citing[p] = append(citing[p], dictionary.CitingWord{Word: w})
+		}
+	}
+	return citing, nil
+}
+
+func TestLookupWords(t *testing.T) {
+	ds := &fakeDictStore{
+		words: map[string][]string{
+			"grassmann":       {"agni", "vftra"},
+			"monier-williams": {"agni", "indra"},
+		},
+		queried: map[string][]string{},
+	}
+	s := &ExcerptService{ds: ds}
+	dicts := []string{"grassmann", "monier-williams"}
+	words := []string{"agni", "indra", "vftra", "soma"}
+
+	merged, err := s.lookupWords(context.Background(), dicts, words, false)
+	assert.NoError(t, err)
+	assert.Len(t, merged["grassmann"], 2)
+	assert.Len(t, merged["monier-williams"], 2)
+	assert.Equal(t, words, ds.queried["monier-williams"])
+
+	ds.queried = map[string][]string{}
+	fallback, err := s.lookupWords(context.Background(), dicts, words, true)
+	assert.NoError(t, err)
+	assert.Contains(t, fallback["grassmann"], "agni")
+	assert.NotContains(t, fallback["monier-williams"], "agni")
+	assert.Contains(t, fallback["monier-williams"], "indra")
+	assert.Equal(t, []string{"indra", "soma"}, ds.queried["monier-williams"])
+}
+
+func TestAddCitations(t *testing.T) {
+	ds := &fakeDictStore{
+		words:   map[string][]string{"4.2.3": {"agni"}, "4.2": {"hotf"}, "4.3": {"soma"}},
+		queried: make(map[string][]string),
+	}
+	s := &ExcerptService{ds: ds}
+	es := []ExcerptWithWords{
+		{Excerpt: Excerpt{Scripture: "rigveda", Path: []int{4, 2, 3}}},
+		{Excerpt: Excerpt{Scripture: "rigveda", Path: []int{4, 2, 4}}},
+	}
+	assert.NoError(t, s.addCitations(context.Background(), es))
+	assert.Equal(t, []string{"4", "4.2", "4.2.3", "4.2.4"}, ds.queried["rigveda"])
+	assert.Equal(t, []dictionary.CitingWord{{Word: "agni"}, {Word: "hotf"}}, es[0].CitedIn)
+	assert.Equal(t, []dictionary.CitingWord{{Word: "hotf"}}, es[1].CitedIn)
+}
@@ -17,7 +17,39 @@ type ExcerptStore interface {
 	// FindBeforeAndAfter, given a set of possible idsBefore and idsAfter in priority order,
 	// finds the immediate previous and next ID with one query
 	FindBeforeAndAfter(ctx context.Context, scripture string, idsBefore []string, idsAfter []string) (prev string, next string)
-	Search(ctx context.Context, scriptures []string, params SearchParams) ([]HighlightedExcerpt, error)
+	// ExpandFuzzy finds the indexed words close to each word of a fuzzy query. It must be
+	// called before searching with common.SearchFuzzy.
+	ExpandFuzzy(ctx context.Context, q string) (*FuzzyQuery, error)
+	// Search returns the requested page of matching excerpts and the total number of matches.
+	Search(ctx context.Context, scriptures []string, params SearchParams) ([]HighlightedExcerpt, int, error)
+	// Facets counts the facet values over all excerpts matching the search.
+	Facets(ctx context.Context, scriptures []string, params SearchParams) ([]Facet, error)
+	// GetRange returns the excerpts of a scripture within the scope in text order, at most
+	// limit excerpts if limit is positive.
+	GetRange(ctx context.Context, scripture string, scope []PathRange, limit int) ([]Excerpt, error)
+	// Stats counts the excerpts, words and distinct lemmas of a scripture within the scope.
+	// An empty scope covers the whole scripture.
+	Stats(ctx context.Context, scripture string, scope []PathRange) (*ScopeStats, error)
+	// ListFormulas returns a page of the repeated formulas of a scripture, most frequent first,
+	// and the total number of formulas.
+	ListFormulas(ctx context.Context, scripture string, page int) ([]Formula, int, error)
+	// GetFormula returns a formula and a page of the excerpts it occurs in, with the formula
+	// tokens set, and the total number of such excerpts.
+	GetFormula(ctx context.Context, scripture string, id string, page int) (*Formula, []HighlightedExcerpt, int, error)
+	// ListMeters returns the scansion statistics of the labelled meters of a scripture, most
+	// frequent first.
+	ListMeters(ctx context.Context, scripture string) ([]MeterStats, error)
+	// GetMeter returns the scansion statistics of a meter and a page of its irregular verses,
+	// and the total number of such verses.
+	GetMeter(ctx context.Context, scripture string, meter string, page int) (*MeterStats, []Excerpt, int, error)
+	// AccentForms returns the attested forms of a lemma with their accent placement and
+	// grammatical tags, most frequent first. The lemma is compared without accents.
+	AccentForms(ctx context.Context, scripture string, lemma string) ([]AccentForm, error)
+	// ListAttributions returns the names of a kind attributed by the anukramani, with the
+	// number of their hymns and verses, most hymns first.
+	ListAttributions(ctx context.Context, scripture string, kind AttributionKind) ([]AttributionCount, error)
+	// GetAttribution returns the hymns attributed to a name, in order.
+	GetAttribution(ctx context.Context, scripture string, kind AttributionKind, name string) ([]AttributedHymn, error)
 	GetHier(ctx context.Context, scripture *config.ScriptureDefn, path []int) (*Hierarchy, error)
 }
 
@@ -0,0 +1,555 @@
+package excerpts
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/config"
+)
+
+// MaxExportExcerpts bounds the number of excerpts exported by one HTTP request.
+const MaxExportExcerpts = 2500
+
+// ExportFormat is the file format of exported excerpts.
+type ExportFormat string
+
+const (
+	ExportText     ExportFormat = "text"
+	ExportCSV      ExportFormat = "csv"
+	ExportMarkdown ExportFormat = "markdown"
+	// ExportTEI writes TEI XML in the layout of the VedaWeb files, which can be read back
+	// with ConvertRvTeiToExcerpts.
+	ExportTEI ExportFormat = "tei"
+)
+
+// Fields which can be exported, besides the auxiliaries of the scripture.
+const (
+	ExportFieldSource    = "source"
+	ExportFieldRoman     = "roman"
+	ExportFieldPadapatha = "padapatha"
+	ExportFieldGlossings = "glossings"
+)
+
+// padaAuxiliary is the name of the auxiliary holding the padapatha.
+const padaAuxiliary = "pada"
+
+const teiNamespace = "http://www.tei-c.org/ns/1.0"
+
+// ContentType returns the MIME type of the format.
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportCSV:
+		return "text/csv; charset=utf-8"
+	case ExportMarkdown:
+		return "text/markdown; charset=utf-8"
+	case ExportTEI:
+		return "application/tei+xml; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// FileExtension returns the usual file extension of the format, without the dot.
+func (f ExportFormat) FileExtension() string {
+	switch f {
+	case ExportCSV:
+		return "csv"
+	case ExportMarkdown:
+		return "md"
+	case ExportTEI:
+		return "tei"
+	default:
+		return "txt"
+	}
+}
+
+// ExportOptions selects the format and the fields of exported excerpts.
+type ExportOptions struct {
+	Format ExportFormat `json:"format"`
+	// Fields in output order. Each is one of the ExportField constants or an auxiliary name.
+	Fields []string `json:"fields"`
+}
+
+// ParseExportOptions validates the format and the comma separated fields. An empty format
+// means plain text, and empty fields mean source, roman and the translation auxiliary.
+func ParseExportOptions(format string, fields string, scripture *config.ScriptureDefn) (ExportOptions, error) {
+	opts := ExportOptions{Format: ExportFormat(format)}
+	switch opts.Format {
+	case "":
+		opts.Format = ExportText
+	case ExportText, ExportCSV, ExportMarkdown, ExportTEI:
+	default:
+		return opts, common.NewUserVisibleError(http.StatusBadRequest,
+			"format must be one of text, csv, markdown or tei")
+	}
+	if opts.Format == ExportTEI && len(scripture.Hierarchy) != 3 {
+		return opts, common.NewUserVisibleError(http.StatusBadRequest,
+			"TEI export is only supported for scriptures with three hierarchy levels")
+	}
+
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if field == padaAuxiliary {
+			field = ExportFieldPadapatha
+		}
+		switch field {
+		case ExportFieldSource, ExportFieldRoman, ExportFieldPadapatha, ExportFieldGlossings:
+		default:
+			if findAuxiliary(scripture, field) == nil {
+				return opts, common.NewUserVisibleError(http.StatusBadRequest, "unknown export field: "+field)
+			}
+		}
+		if !slices.Contains(opts.Fields, field) {
+			opts.Fields = append(opts.Fields, field)
+		}
+	}
+	if len(opts.Fields) == 0 {
+		opts.Fields = []string{ExportFieldSource, ExportFieldRoman}
+		if scripture.TranslationAuxiliary != "" {
+			opts.Fields = append(opts.Fields, scripture.TranslationAuxiliary)
+		}
+	}
+	return opts, nil
+}
+
+func findAuxiliary(scripture *config.ScriptureDefn, name string) *config.AuxiliaryDefinition {
+	for i := range scripture.Auxiliaries {
+		if scripture.Auxiliaries[i].Name == name {
+			return &scripture.Auxiliaries[i]
+		}
+	}
+	return nil
+}
+
+// exportFieldLabel returns the heading of a field in text and markdown exports.
+func exportFieldLabel(scripture *config.ScriptureDefn, field string) string {
+	switch field {
+	case ExportFieldSource:
+		return "Source"
+	case ExportFieldRoman:
+		return "Roman"
+	case ExportFieldGlossings:
+		return "Glossings"
+	case ExportFieldPadapatha:
+		if aux := findAuxiliary(scripture, padaAuxiliary); aux != nil {
+			return aux.ReadableName
+		}
+		return "Padapatha"
+	}
+	if aux := findAuxiliary(scripture, field); aux != nil && aux.ReadableName != "" {
+		return aux.ReadableName
+	}
+	return field
+}
+
+// exportFieldLines returns the lines of a field of the excerpt, with glossings rendered as
+// one line of words per division of the verse.
+func exportFieldLines(e *Excerpt, field string) []string {
+	switch field {
+	case ExportFieldSource:
+		return e.SourceText
+	case ExportFieldRoman:
+		return e.RomanText
+	case ExportFieldPadapatha:
+		return e.Auxiliaries[padaAuxiliary].Text
+	case ExportFieldGlossings:
+		var lines []string
+		for _, line := range e.Glossings {
+			words := make([]string, len(line))
+			for i, g := range line {
+				words[i] = fmt.Sprintf("%s (%s)", g.Surface, strings.Join(glossingAnalysis(g), ", "))
+			}
+			lines = append(lines, strings.Join(words, " "))
+		}
+		return lines
+	}
+	return e.Auxiliaries[field].Text
+}
+
+// glossingAnalysis returns the lemma and grammatical description of a word, skipping
+// empty values.
+func glossingAnalysis(g WordGlossing) []string {
+	var parts []string
+	if g.Lemma != "" {
+		parts = append(parts, g.Lemma)
+	}
+	if grammar := glossingGrammar(g); grammar != "" {
+		parts = append(parts, grammar)
+	}
+	return parts
+}
+
+func glossingGrammar(g WordGlossing) string {
+	var parts []string
+	for _, v := range []string{g.Gramm, g.Case, g.Number, g.Gender, g.Person, g.Tense, g.Mood, g.Voice} {
+		if v != "" {
+			parts = append(parts, v)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// WriteExport writes the excerpts of a scripture in the chosen format and fields.
+func WriteExport(w io.Writer, scripture *config.ScriptureDefn, es []Excerpt, opts ExportOptions) error {
+	switch opts.Format {
+	case ExportCSV:
+		return writeExportCSV(w, scripture, es, opts.Fields)
+	case ExportMarkdown:
+		return writeExportMarkdown(w, scripture, es, opts.Fields)
+	case ExportTEI:
+		return writeExportTEI(w, scripture, es, opts.Fields)
+	default:
+		return writeExportText(w, scripture, es, opts.Fields)
+	}
+}
+
+// writeExportText writes each excerpt as a reference followed by its fields, with the
+// source and roman text unlabelled.
+func writeExportText(w io.Writer, scripture *config.ScriptureDefn, es []Excerpt, fields []string) error {
+	var b strings.Builder
+	for i := range es {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s %s\n", scripture.ReadableName, es[i].ReadableIndex)
+		for _, field := range fields {
+			lines := exportFieldLines(&es[i], field)
+			if len(lines) == 0 {
+				continue
+			}
+			if field != ExportFieldSource && field != ExportFieldRoman {
+				fmt.Fprintf(&b, "%s:\n", exportFieldLabel(scripture, field))
+			}
+			for _, line := range lines {
+				b.WriteString(line)
+				b.WriteString("\n")
+			}
+		}
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+		b.Reset()
+	}
+	return nil
+}
+
+// writeExportCSV writes one row per excerpt, with the lines of each field separated by
+// newlines.
+func writeExportCSV(w io.Writer, scripture *config.ScriptureDefn, es []Excerpt, fields []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(append([]string{"scripture", "reference"}, fields...)); err != nil {
+		return err
+	}
+	for i := range es {
+		row := []string{scripture.Name, es[i].ReadableIndex}
+		for _, field := range fields {
+			row = append(row, strings.Join(exportFieldLines(&es[i], field), "\n"))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "|", `\|`, "#", `\#`,
+)
+
+// writeExportMarkdown writes a section per excerpt. Glossings are written as a table, and
+// auxiliaries other than the padapatha as block quotes.
+func writeExportMarkdown(w io.Writer, scripture *config.ScriptureDefn, es []Excerpt, fields []string) error {
+	var b strings.Builder
+	for i := range es {
+		e := &es[i]
+		fmt.Fprintf(&b, "## %s %s\n\n", markdownEscaper.Replace(scripture.ReadableName), e.ReadableIndex)
+		for _, field := range fields {
+			if field == ExportFieldGlossings {
+				writeMarkdownGlossings(&b, e.Glossings)
+				continue
+			}
+			lines := exportFieldLines(e, field)
+			if len(lines) == 0 {
+				continue
+			}
+			prefix := ""
+			if field != ExportFieldSource && field != ExportFieldRoman {
+				fmt.Fprintf(&b, "**%s**\n\n", markdownEscaper.Replace(exportFieldLabel(scripture, field)))
+				if field != ExportFieldPadapatha {
+					prefix = "> "
+				}
+			}
+			for j, line := range lines {
+				b.WriteString(prefix)
+				b.WriteString(markdownEscaper.Replace(line))
+				if j < len(lines)-1 {
+					// trailing backslash is a hard line break
+					b.WriteString("\\")
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+		b.Reset()
+	}
+	return nil
+}
+
+func writeMarkdownGlossings(b *strings.Builder, glossings [][]WordGlossing) {
+	if len(glossings) == 0 {
+		return
+	}
+	b.WriteString("| Surface | Lemma | Grammar |\n| --- | --- | --- |\n")
+	for _, line := range glossings {
+		for _, g := range line {
+			fmt.Fprintf(b, "| %s | %s | %s |\n", markdownEscaper.Replace(g.Surface),
+				markdownEscaper.Replace(g.Lemma), markdownEscaper.Replace(glossingGrammar(g)))
+		}
+	}
+	b.WriteString("\n")
+}
+
+// teiWriter writes indented TEI elements, remembering the first error.
+type teiWriter struct {
+	w      io.Writer
+	indent int
+	err    error
+}
+
+func (t *teiWriter) line(format string, args ...any) {
+	if t.err != nil {
+		return
+	}
+	_, t.err = fmt.Fprintf(t.w, "%s%s\n", strings.Repeat("  ", t.indent), fmt.Sprintf(format, args...))
+}
+
+func (t *teiWriter) open(format string, args ...any) {
+	t.line(format, args...)
+	t.indent++
+}
+
+func (t *teiWriter) close(tag string) {
+	t.indent--
+	t.line("</%s>", tag)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func teiLang(lang common.Language) string {
+	switch lang {
+	case common.English:
+		return "eng"
+	case common.Sanskrit:
+		return "san-Latn"
+	}
+	return string(lang)
+}
+
+// writeExportTEI writes the excerpts as book, hymn and stanza divisions, so the scripture must
+// have three hierarchy levels. Dedications are written once per hymn, from its first exported
+// stanza.
+func writeExportTEI(w io.Writer, scripture *config.ScriptureDefn, es []Excerpt, fields []string) error {
+	t := &teiWriter{w: w}
+	t.line(`<?xml version="1.0" encoding="UTF-8"?>`)
+	t.open(`<TEI xmlns="%s">`, teiNamespace)
+	t.open("<teiHeader>")
+	t.open("<fileDesc>")
+	t.open("<titleStmt>")
+	t.line("<title>%s</title>", xmlEscape(scripture.ReadableName))
+	t.close("titleStmt")
+	t.open("<publicationStmt>")
+	t.line("<p>Exported from Dhee</p>")
+	t.close("publicationStmt")
+	t.open("<sourceDesc>")
+	t.line("<p>%s</p>", xmlEscape(scripture.Description))
+	t.close("sourceDesc")
+	t.close("fileDesc")
+	t.close("teiHeader")
+	t.open("<text>")
+	t.open("<body>")
+
+	var book, hymn []int
+	for i := range es {
+		e := &es[i]
+		if len(e.Path) != 3 {
+			return fmt.Errorf("excerpt %s does not have a three level path", e.ReadableIndex)
+		}
+		if hymn != nil && !slices.Equal(hymn, e.Path[:2]) {
+			t.close("div")
+		}
+		if book != nil && book[0] != e.Path[0] {
+			t.close("div")
+		}
+		bookID := fmt.Sprintf("b%02d", e.Path[0])
+		hymnID := fmt.Sprintf("%s_h%03d", bookID, e.Path[1])
+		if book == nil || book[0] != e.Path[0] {
+			book = e.Path[:1]
+			hymn = nil
+			t.open(`<div xml:id="%s" type="book">`, bookID)
+		}
+		if hymn == nil || !slices.Equal(hymn, e.Path[:2]) {
+			hymn = e.Path[:2]
+			t.open(`<div xml:id="%s" type="hymn">`, hymnID)
+			writeTEIDedication(t, e)
+		}
+		writeTEIStanza(t, scripture, e, fmt.Sprintf("%s_%02d", hymnID, e.Path[2]), fields)
+	}
+	if hymn != nil {
+		t.close("div")
+		t.close("div")
+	}
+
+	t.close("body")
+	t.close("text")
+	t.close("TEI")
+	return t.err
+}
+
+func writeTEIDedication(t *teiWriter, e *Excerpt) {
+	type part struct {
+		kind   string
+		values []string
+	}
+	parts := []part{{"addressee", e.Addressees}, {"group", nil}, {"author", e.Authors}, {"meter", nil}}
+	if e.Group != "" {
+		parts[1].values = []string{e.Group}
+	}
+	if e.Meter != "" {
+		parts[3].values = []string{e.Meter}
+	}
+	if len(e.Addressees)+len(parts[1].values)+len(e.Authors)+len(parts[3].values) == 0 {
+		return
+	}
+	t.open(`<div type="dedication">`)
+	for _, p := range parts {
+		if len(p.values) == 0 {
+			continue
+		}
+		t.open(`<div type="%s">`, p.kind)
+		for _, v := range p.values {
+			t.line(`<p xml:lang="eng">%s</p>`, xmlEscape(v))
+		}
+		t.close("div")
+	}
+	t.close("div")
+}
+
+func writeTEIStanza(t *teiWriter, scripture *config.ScriptureDefn, e *Excerpt, id string, fields []string) {
+	t.open(`<div xml:id="%s" type="stanza">`, id)
+	writeTEILines := func(source, lang string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		if lang != "" {
+			t.open(`<lg source="%s" xml:lang="%s">`, xmlEscape(source), lang)
+		} else {
+			t.open(`<lg source="%s">`, xmlEscape(source))
+		}
+		for _, line := range lines {
+			t.line("<l>%s</l>", xmlEscape(line))
+		}
+		t.close("lg")
+	}
+
+	var roman []string
+	if slices.Contains(fields, ExportFieldRoman) {
+		roman = e.RomanText
+	}
+	var glossings [][]WordGlossing
+	if slices.Contains(fields, ExportFieldGlossings) {
+		glossings = e.Glossings
+	}
+	zurichWritten := false
+	for _, field := range fields {
+		switch field {
+		case ExportFieldSource:
+			writeTEILines("eichler", "san-Deva", e.SourceText)
+		case ExportFieldRoman, ExportFieldGlossings:
+			// the roman text and the glossings share the zurich line group
+			if zurichWritten || len(roman)+len(glossings) == 0 {
+				continue
+			}
+			zurichWritten = true
+			t.open(`<lg source="zurich" xml:lang="san-Latn">`)
+			for _, line := range roman {
+				t.line("<l>%s</l>", xmlEscape(line))
+			}
+			for i, line := range glossings {
+				writeTEIGlossings(t, fmt.Sprintf("%s_%c_tokens", id, 'a'+i), line)
+			}
+			t.close("lg")
+		case ExportFieldPadapatha:
+			pada := e.Auxiliaries[padaAuxiliary].Text
+			if len(pada) == 0 {
+				continue
+			}
+			t.open(`<lg source="padapatha" xml:lang="san-Latn">`)
+			for _, line := range pada {
+				t.open("<l>")
+				for _, word := range strings.Split(line, "|") {
+					if word = strings.TrimSpace(word); word != "" {
+						t.line("<w>%s</w>", xmlEscape(word))
+					}
+				}
+				t.close("l")
+			}
+			t.close("lg")
+		default:
+			lang := ""
+			if aux := findAuxiliary(scripture, field); aux != nil {
+				lang = teiLang(aux.Language)
+			}
+			writeTEILines(field, lang, e.Auxiliaries[field].Text)
+		}
+	}
+	t.close("div")
+}
+
+func writeTEIGlossings(t *teiWriter, id string, line []WordGlossing) {
+	t.open(`<l xml:id="%s">`, id)
+	for _, g := range line {
+		t.open(`<fs type="zurich_info">`)
+		t.line(`<f name="surface"><string>%s</string></f>`, xmlEscape(g.Surface))
+		t.line(`<f name="gra_lemma"><string>%s</string></f>`, xmlEscape(g.Lemma))
+		if g.Gramm != "" {
+			t.line(`<f name="gra_gramm"><symbol value="%s"/></f>`, xmlEscape(g.Gramm))
+		}
+		features := []struct{ name, value string }{
+			{"case", g.Case}, {"number", g.Number}, {"gender", g.Gender}, {"tense", g.Tense},
+			{"voice", g.Voice}, {"person", g.Person}, {"mood", g.Mood},
+		}
+		for _, m := range g.Modifiers {
+			name, value, _ := strings.Cut(string(m), ":")
+			features = append(features, struct{ name, value string }{name, value})
+		}
+		t.open(`<f name="morphosyntax">`)
+		t.open("<fs>")
+		for _, f := range features {
+			if f.value != "" {
+				t.line(`<f name="%s"><symbol value="%s"/></f>`, xmlEscape(f.name), xmlEscape(f.value))
+			}
+		}
+		t.close("fs")
+		t.close("f")
+		t.close("fs")
+	}
+	t.close("l")
+}
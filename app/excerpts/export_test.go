@@ -0,0 +1,116 @@
+package excerpts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+var exportScripture = &config.ScriptureDefn{
+	Name:         "rigveda",
+	ReadableName: "Rig Veda",
+	Hierarchy:    []string{"Mandala", "Sukta", "Verse"},
+	Auxiliaries: []config.AuxiliaryDefinition{
+		{Name: "pada", ReadableName: "Padapatha", Language: "sanskrit"},
+		{Name: "griffith", ReadableName: "Griffith", Language: "english"},
+		{Name: "oldenberg", ReadableName: "Oldenberg", Language: "english"},
+	},
+	TranslationAuxiliary: "griffith",
+}
+
+var exportExcerpts = []Excerpt{
+	{
+		Scripture:     "rigveda",
+		ReadableIndex: "1.1.1",
+		Path:          []int{1, 1, 1},
+		SourceText:    []string{"अ॒ग्निमी॑ळे पु॒रोहि॑तं", "य॒ज्ञस्य॑ दे॒वमृ॒त्विज॑म्"},
+		RomanText:     []string{"agním īḷe puróhitaṁ", "yajñásya devám r̥tvíjam"},
+		Authors:       []string{"Madhuchandas Vaishvamitra"},
+		Meter:         "Gayatri",
+		Addressees:    []string{"Agni"},
+		Group:         "Agni",
+		Glossings: [][]WordGlossing{
+			{
+				{Surface: "agním", Lemma: "agní-", Gramm: "m", Case: "ACC", Number: "SG", Gender: "M"},
+				{Surface: "īḷe", Lemma: "īḍ-", Gramm: "root", Root: "īḍ", Number: "SG", Person: "1", Tense: "PRS", Voice: "MED", Mood: "IND"},
+			},
+			{
+				{Surface: "yajñásya", Lemma: "yajñá-", Gramm: "m", Case: "GEN", Number: "SG", Gender: "M", Modifiers: []Modifier{"note:<R&D>"}},
+			},
+		},
+		Auxiliaries: map[string]Auxiliary{
+			"pada":     {Text: []string{"agnim | īḷe | purohitam"}},
+			"griffith": {Text: []string{"I Laud Agni, the chosen Priest, God, minister of sacrifice,"}},
+		},
+	},
+	{
+		Scripture:     "rigveda",
+		ReadableIndex: "1.1.2",
+		Path:          []int{1, 1, 2},
+		RomanText:     []string{"agníḥ pū́rvebhir ŕ̥ṣibhir"},
+		Authors:       []string{"Madhuchandas Vaishvamitra"},
+		Meter:         "Gayatri",
+		Addressees:    []string{"Agni"},
+		Group:         "Agni",
+		Auxiliaries:   map[string]Auxiliary{},
+	},
+	{
+		Scripture:     "rigveda",
+		ReadableIndex: "2.3.4",
+		Path:          []int{2, 3, 4},
+		RomanText:     []string{"a < b & c"},
+		Addressees:    []string{"Indra", "Varuna"},
+		Auxiliaries:   map[string]Auxiliary{"oldenberg": {Text: []string{"Oldenberg line"}}},
+	},
+}
+
+func TestExportTEIRoundTrip(t *testing.T) {
+	opts, err := ParseExportOptions("tei", "source,roman,pada,glossings,griffith,oldenberg", exportScripture)
+	assert.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteExport(&buf, exportScripture, exportExcerpts, opts))
+
+	parsed, err := ConvertRvTeiToExcerpts(&buf)
+	assert.NoError(t, err)
+	assert.Len(t, parsed, len(exportExcerpts))
+	for i, want := range exportExcerpts {
+		got := parsed[i]
+		assert.Equal(t, want.ReadableIndex, got.ReadableIndex)
+		assert.Equal(t, want.Path, got.Path)
+		assert.Equal(t, want.SourceText, got.SourceText)
+		assert.Equal(t, want.RomanText, got.RomanText)
+		assert.Equal(t, want.Authors, got.Authors)
+		assert.Equal(t, want.Meter, got.Meter)
+		assert.Equal(t, want.Addressees, got.Addressees)
+		assert.Equal(t, want.Group, got.Group)
+		assert.Equal(t, want.Glossings, got.Glossings)
+		assert.Equal(t, want.Auxiliaries, got.Auxiliaries)
+	}
+}
+
+func TestParseExportOptions(t *testing.T) {
+	opts, err := ParseExportOptions("", "", exportScripture)
+	assert.NoError(t, err)
+	assert.Equal(t, ExportOptions{Format: ExportText, Fields: []string{"source", "roman", "griffith"}}, opts)
+
+	opts, err = ParseExportOptions("csv", "pada, roman,padapatha", exportScripture)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"padapatha", "roman"}, opts.Fields)
+
+	_, err = ParseExportOptions("pdf", "", exportScripture)
+	assert.Error(t, err)
+	_, err = ParseExportOptions("text", "ralph", exportScripture)
+	assert.Error(t, err)
+}
+
+func TestWriteExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	opts := ExportOptions{Format: ExportCSV, Fields: []string{"roman", "griffith"}}
+	assert.NoError(t, WriteExport(&buf, exportScripture, exportExcerpts[:2], opts))
+	assert.Equal(t, "scripture,reference,roman,griffith\n"+
+		"rigveda,1.1.1,\"agním īḷe puróhitaṁ\nyajñásya devám r̥tvíjam\",\"I Laud Agni, the chosen Priest, God, minister of sacrifice,\"\n"+
+		"rigveda,1.1.2,agníḥ pū́rvebhir ŕ̥ṣibhir,\n", buf.String())
+}
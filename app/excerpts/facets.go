@@ -0,0 +1,106 @@
+package excerpts
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+)
+
+// Facet names, which are also stored as the facet column of dhee_excerpt_facets.
+const (
+	FacetAddressee = "addressee"
+	FacetAuthor    = "author"
+	// FacetFamily is the family of the seers, given by the anukramani.
+	FacetFamily = "family"
+	FacetMeter  = "meter"
+	// FacetPath is the top level unit of the scripture hierarchy, eg: the mandala.
+	FacetPath = "path"
+)
+
+// KnownFacets lists the facets in display order.
+var KnownFacets = []string{FacetPath, FacetAddressee, FacetAuthor, FacetFamily, FacetMeter}
+
+// FacetFilter is a facet value, used to narrow down search results.
+type FacetFilter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+func (f FacetFilter) String() string {
+	return f.Name + ":" + f.Value
+}
+
+// ParseFacetFilter parses a facet filter of the form `name:value`, eg: `addressee:Agni`.
+func ParseFacetFilter(s string) (FacetFilter, error) {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok || value == "" || !slices.Contains(KnownFacets, name) {
+		return FacetFilter{}, common.NewUserVisibleError(http.StatusBadRequest,
+			"invalid facet "+strconv.Quote(s)+", expected one of "+strings.Join(KnownFacets, ", ")+" followed by :value")
+	}
+	return FacetFilter{Name: name, Value: value}, nil
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Facet holds the number of matching excerpts for each value of a facet.
+type Facet struct {
+	Name   string       `json:"name"`
+	Counts []FacetCount `json:"counts"`
+}
+
+// excerptFacets returns the facet values of an excerpt.
+func excerptFacets(e *Excerpt) []FacetFilter {
+	var facets []FacetFilter
+	if len(e.Path) > 0 {
+		facets = append(facets, FacetFilter{FacetPath, strconv.Itoa(e.Path[0])})
+	}
+	for _, a := range e.Addressees {
+		facets = append(facets, FacetFilter{FacetAddressee, a})
+	}
+	for _, a := range e.Authors {
+		facets = append(facets, FacetFilter{FacetAuthor, a})
+	}
+	if e.Anukramani != nil && e.Anukramani.Family != "" {
+		facets = append(facets, FacetFilter{FacetFamily, e.Anukramani.Family})
+	}
+	if e.Meter != "" {
+		facets = append(facets, FacetFilter{FacetMeter, e.Meter})
+	}
+	return facets
+}
+
+// buildFacets groups facet counts by facet in display order. Hierarchy units are sorted by
+// number, other values by decreasing count.
+func buildFacets(counts map[FacetFilter]int) []Facet {
+	var facets []Facet
+	for _, name := range KnownFacets {
+		facet := Facet{Name: name}
+		for f, n := range counts {
+			if f.Name == name {
+				facet.Counts = append(facet.Counts, FacetCount{Value: f.Value, Count: n})
+			}
+		}
+		if len(facet.Counts) == 0 {
+			continue
+		}
+		slices.SortFunc(facet.Counts, func(a, b FacetCount) int {
+			if name == FacetPath {
+				an, _ := strconv.Atoi(a.Value)
+				bn, _ := strconv.Atoi(b.Value)
+				return an - bn
+			}
+			if a.Count != b.Count {
+				return b.Count - a.Count
+			}
+			return strings.Compare(a.Value, b.Value)
+		})
+		facets = append(facets, facet)
+	}
+	return facets
+}
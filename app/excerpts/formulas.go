@@ -0,0 +1,225 @@
+package excerpts
+
+import (
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+)
+
+const (
+	// Shortest sequence of words considered a formula.
+	minFormulaLength = 3
+	// Minimum number of distinct excerpts a formula must occur in.
+	minFormulaExcerpts = 2
+)
+
+// FormulaBasis is the glossing feature whose normalized values are compared to find formulas.
+type FormulaBasis string
+
+const (
+	FormulaSurface FormulaBasis = "surface"
+	FormulaLemma   FormulaBasis = "lemma"
+)
+
+// Formula is a sequence of words repeated across excerpts, eg: a refrain.
+type Formula struct {
+	ID    string       `json:"id"`
+	Basis FormulaBasis `json:"basis"`
+	// Normalized surfaces or lemmas, separated by space.
+	Text   string `json:"text"`
+	Length int    `json:"length"`
+	// Number of occurrences in the whole scripture.
+	Occurrences int `json:"occurrences"`
+}
+
+// FormulaOccurrence is a formula found in an excerpt, starting at the glossing token Start
+// and continuing for Length tokens, across lines if required.
+type FormulaOccurrence struct {
+	Formula
+	Start TokenRef `json:"start"`
+}
+
+// formulaID derives a stable, URL safe ID for a formula.
+func formulaID(basis FormulaBasis, text string) string {
+	h := fnv.New64a()
+	h.Write([]byte(string(basis) + ":" + text))
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// formulaTokens returns the glossing tokens covered by an occurrence of a formula
+// of given length starting at start.
+func formulaTokens(e *Excerpt, start TokenRef, length int) []TokenRef {
+	var tokens []TokenRef
+	line, position := start.Line, start.Position
+	for len(tokens) < length && line < len(e.Glossings) {
+		if position >= len(e.Glossings[line]) {
+			line, position = line+1, 0
+			continue
+		}
+		tokens = append(tokens, TokenRef{line, position})
+		position++
+	}
+	return tokens
+}
+
+// FormulaTokens returns the glossing tokens covered by all formulas of the excerpt.
+func (e *Excerpt) FormulaTokens() []TokenRef {
+	var tokens []TokenRef
+	for _, f := range e.Formulas {
+		tokens = append(tokens, formulaTokens(e, f.Start, f.Length)...)
+	}
+	return tokens
+}
+
+type ngramPos struct {
+	excerpt, start int
+}
+
+type repeatedNgram struct {
+	length    int
+	positions []ngramPos
+}
+
+// findFormulas returns the maximal repeated n-grams over the given token keys, grouped by
+// n-gram text. An empty key, eg: a missing lemma, is never part of an n-gram. Repeats are
+// maximal when they are not always contained in a longer repeat.
+func findFormulas(keys [][]string) map[string]repeatedNgram {
+	// levels[n] holds the repeated n-grams, grown from the repeated (n-1)-grams since
+	// every prefix of a repeated n-gram is itself repeated.
+	levels := make(map[int]map[string][]ngramPos)
+	var candidates []ngramPos
+	for e := range keys {
+		for s := range keys[e] {
+			candidates = append(candidates, ngramPos{e, s})
+		}
+	}
+	for n := minFormulaLength; len(candidates) > 0; n++ {
+		groups := make(map[string][]ngramPos)
+		for _, c := range candidates {
+			seq := keys[c.excerpt]
+			if c.start+n > len(seq) || slices.Contains(seq[c.start:c.start+n], "") {
+				continue
+			}
+			k := strings.Join(seq[c.start:c.start+n], " ")
+			groups[k] = append(groups[k], c)
+		}
+		candidates = candidates[:0:0]
+		for k, positions := range groups {
+			if distinctExcerpts(positions) < minFormulaExcerpts {
+				delete(groups, k)
+				continue
+			}
+			candidates = append(candidates, positions...)
+		}
+		levels[n] = groups
+	}
+
+	formulas := make(map[string]repeatedNgram)
+	for n, groups := range levels {
+		// an occurrence is covered when a repeated (n+1)-gram extends it on either side
+		covered := make(map[ngramPos]bool)
+		for _, positions := range levels[n+1] {
+			for _, p := range positions {
+				covered[p] = true
+				covered[ngramPos{p.excerpt, p.start + 1}] = true
+			}
+		}
+		for k, positions := range groups {
+			for _, p := range positions {
+				if !covered[p] {
+					formulas[k] = repeatedNgram{n, positions}
+					break
+				}
+			}
+		}
+	}
+	return formulas
+}
+
+func distinctExcerpts(positions []ngramPos) int {
+	n := 0
+	for i, p := range positions {
+		// positions are in excerpt order
+		if i == 0 || positions[i-1].excerpt != p.excerpt {
+			n++
+		}
+	}
+	return n
+}
+
+// computeFormulas finds word sequences repeated across excerpts, using both normalized
+// surfaces and lemmas, and records each occurrence in the excerpt. Lemma formulas with the
+// same occurrences as a surface formula are skipped, since they add nothing new.
+func computeFormulas(excerpts []Excerpt) {
+	if len(excerpts) == 0 {
+		return
+	}
+	slog.Info("Computing repeated formulas...")
+
+	// tokens flattened across lines, as for CQL matching
+	refs := make([][]TokenRef, len(excerpts))
+	surfaces := make([][]string, len(excerpts))
+	lemmas := make([][]string, len(excerpts))
+	for i := range excerpts {
+		for line, glossings := range excerpts[i].Glossings {
+			for position, g := range glossings {
+				refs[i] = append(refs[i], TokenRef{line, position})
+				surfaces[i] = append(surfaces[i], common.NormalizeSurface(g.Surface))
+				lemmas[i] = append(lemmas[i], common.NormalizeLemma(g.Lemma))
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	occurrenceKey := func(positions []ngramPos, length int) string {
+		var sb strings.Builder
+		for _, p := range positions {
+			fmt.Fprintf(&sb, "%d:%d,", p.excerpt, p.start)
+		}
+		return fmt.Sprintf("%s%d", sb.String(), length)
+	}
+
+	count := 0
+	for _, basis := range []FormulaBasis{FormulaSurface, FormulaLemma} {
+		keys := surfaces
+		if basis == FormulaLemma {
+			keys = lemmas
+		}
+		formulas := findFormulas(keys)
+		texts := make([]string, 0, len(formulas))
+		for text := range formulas {
+			texts = append(texts, text)
+		}
+		sort.Strings(texts)
+
+		for _, text := range texts {
+			ngram := formulas[text]
+			key := occurrenceKey(ngram.positions, ngram.length)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			f := Formula{
+				ID:          formulaID(basis, text),
+				Basis:       basis,
+				Text:        text,
+				Length:      ngram.length,
+				Occurrences: len(ngram.positions),
+			}
+			for _, p := range ngram.positions {
+				excerpts[p.excerpt].Formulas = append(excerpts[p.excerpt].Formulas, FormulaOccurrence{
+					Formula: f,
+					Start:   refs[p.excerpt][p.start],
+				})
+			}
+			count++
+		}
+	}
+	slog.Info("Finished computing repeated formulas.", "count", count)
+}
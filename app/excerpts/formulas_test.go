@@ -0,0 +1,47 @@
+package excerpts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func glossedExcerpt(lines ...[]string) Excerpt {
+	var e Excerpt
+	for _, line := range lines {
+		var glossings []WordGlossing
+		for _, w := range line {
+			glossings = append(glossings, WordGlossing{Surface: w, Lemma: w + "-"})
+		}
+		e.Glossings = append(e.Glossings, glossings)
+	}
+	return e
+}
+
+func TestComputeFormulas(t *testing.T) {
+	es := []Excerpt{
+		glossedExcerpt([]string{"a", "b", "c", "d"}, []string{"e", "x"}),
+		glossedExcerpt([]string{"y", "a", "b"}, []string{"c", "d", "e"}),
+		glossedExcerpt([]string{"b", "c", "d", "z"}),
+	}
+	// the same lemmas, inflected differently
+	es[2].Glossings[0][0].Surface = "bam"
+	computeFormulas(es)
+
+	var texts []string
+	for _, f := range es[0].Formulas {
+		texts = append(texts, string(f.Basis)+":"+f.Text)
+	}
+	// "a b c d" is contained in "a b c d e", but "b c d" occurs on its own by lemma
+	assert.Equal(t, []string{"surface:a b c d e", "lemma:b c d"}, texts)
+
+	f := es[1].Formulas[0]
+	assert.Equal(t, TokenRef{0, 1}, f.Start)
+	assert.Equal(t, 5, f.Length)
+	assert.Equal(t, 2, f.Occurrences)
+	assert.Equal(t, []TokenRef{{0, 1}, {0, 2}, {1, 0}, {1, 1}, {1, 2}}, formulaTokens(&es[1], f.Start, f.Length))
+
+	assert.Empty(t, es[2].Formulas[1:])
+	assert.Equal(t, FormulaLemma, es[2].Formulas[0].Basis)
+	assert.Equal(t, 3, es[2].Formulas[0].Occurrences)
+}
@@ -0,0 +1,173 @@
+package excerpts
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+)
+
+const (
+	// maxFuzzyWords bounds the number of words of a fuzzy query.
+	maxFuzzyWords = 5
+	// maxFuzzyExpansions bounds the number of indexed words each query word can match.
+	maxFuzzyExpansions = 100
+)
+
+// FuzzyQuery holds the indexed words matched by each word of a fuzzy search, see
+// ExcerptStore.ExpandFuzzy.
+type FuzzyQuery struct {
+	// expansions has the ids of the indexed words within the allowed distance of each query
+	// word, closest first.
+	expansions [][]int64
+	// exact has the ids of indexed words equal to a query word.
+	exact []int64
+	// matched is the set of all matched indexed words, for highlighting.
+	matched map[string]bool
+}
+
+// iastBaseLetters maps IAST letters with diacritics to the plain letters, which users often
+// type instead.
+var iastBaseLetters = map[rune]rune{
+	'ā': 'a', 'ī': 'i', 'ū': 'u', 'ṛ': 'r', 'ṝ': 'r', 'ḷ': 'l', 'ḹ': 'l', 'ṅ': 'n', 'ñ': 'n',
+	'ṭ': 't', 'ḍ': 'd', 'ṇ': 'n', 'ś': 's', 'ṣ': 's', 'ṃ': 'm', 'ṁ': 'm', 'ḥ': 'h', 'ḻ': 'l',
+}
+
+func baseLetter(r rune) rune {
+	if b, ok := iastBaseLetters[r]; ok {
+		return b
+	}
+	return r
+}
+
+// fuzzyNormalize folds accents and case, and strips characters other than letters and
+// marks, so that the romanized text and glossing surfaces can be compared by edit distance.
+func fuzzyNormalize(word string) string {
+	word = strings.ToLower(common.FoldAccents(word))
+	return strings.Map(func(r rune) rune {
+		// the svarita is not folded by FoldAccents
+		if !isFuzzyWordRune(r) || r == '\u0300' {
+			return -1
+		}
+		return r
+	}, word)
+}
+
+// fuzzyBase strips the diacritics of a normalized word.
+func fuzzyBase(word string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsMark(r) {
+			return -1
+		}
+		return baseLetter(r)
+	}, word)
+}
+
+func isFuzzyWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsMark(r)
+}
+
+// fuzzyWords splits text into normalized words.
+func fuzzyWords(text string) []string {
+	var words []string
+	for _, w := range strings.FieldsFunc(text, func(r rune) bool { return !isFuzzyWordRune(r) }) {
+		if w = fuzzyNormalize(w); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// excerptFuzzyWords returns the distinct normalized words of the romanized text and the
+// glossing surfaces, which differ from the text where sandhi applies.
+func excerptFuzzyWords(e *Excerpt) []string {
+	seen := make(map[string]bool)
+	var words []string
+	add := func(text string) {
+		for _, w := range fuzzyWords(text) {
+			if !seen[w] {
+				seen[w] = true
+				words = append(words, w)
+			}
+		}
+	}
+	for _, line := range e.RomanText {
+		add(line)
+	}
+	for _, line := range e.Glossings {
+		for _, g := range line {
+			add(common.NormalizeSurface(g.Surface))
+		}
+	}
+	return words
+}
+
+// trigrams returns the distinct trigrams of a word without diacritics, padded with a space on
+// either side.
+func trigrams(word string) []string {
+	runes := []rune(" " + fuzzyBase(word) + " ")
+	seen := make(map[string]bool)
+	var result []string
+	for i := 0; i+3 <= len(runes); i++ {
+		t := string(runes[i : i+3])
+		if !seen[t] {
+			seen[t] = true
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// minSharedTrigrams returns the number of distinct trigrams which any word within distance
+// of a word with the given trigrams must share with it, since an edit changes at most three
+// trigrams, and diacritics are not part of trigrams.
+func minSharedTrigrams(trigramCount int, distance int) int {
+	return trigramCount - 3*distance
+}
+
+func sameBaseLetter(a, b rune) bool {
+	return baseLetter(a) == baseLetter(b)
+}
+
+// fuzzyDistance returns the edit distance between normalized words in half edits, see
+// common.HalfEditDistance. Letters differing only in diacritics, like a and ā, are similar.
+func fuzzyDistance(a, b []rune, limit int) int {
+	return common.HalfEditDistance(a, b, sameBaseLetter, limit)
+}
+
+// highlightFuzzy returns the HTML escaped romanized text with the matched words emphasized.
+func highlightFuzzy(e *Excerpt, matched map[string]bool) string {
+	var b strings.Builder
+	for i, line := range e.RomanText {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		start := -1
+		flush := func(end int) {
+			word := line[start:end]
+			if matched[fuzzyNormalize(word)] {
+				b.WriteString("<em>" + html.EscapeString(word) + "</em>")
+			} else {
+				b.WriteString(html.EscapeString(word))
+			}
+			start = -1
+		}
+		for j, r := range line {
+			if isFuzzyWordRune(r) {
+				if start < 0 {
+					start = j
+				}
+				continue
+			}
+			if start >= 0 {
+				flush(j)
+			}
+			b.WriteString(html.EscapeString(string(r)))
+		}
+		if start >= 0 {
+			flush(len(line))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,54 @@
+package excerpts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFuzzyDistance(t *testing.T) {
+	dist := func(a, b string) int {
+		return fuzzyDistance([]rune(fuzzyNormalize(a)), []rune(fuzzyNormalize(b)), 10)
+	}
+	assert.Equal(t, 0, dist("agním", "agnim"))
+	assert.Equal(t, 0, dist("vīryā̀ṇi", "vīryāṇi"))
+	// diacritics are half an edit each
+	assert.Equal(t, 3, dist("vr̥trā́ṇi", "vrtrani"))
+	assert.Equal(t, 2, dist("jaṅghanat", "jaghanat"))
+	assert.Equal(t, 1, dist("puróhitam", "purohitaṁ"))
+	assert.Equal(t, 6, dist("agnim", "agnaye"))
+	assert.Equal(t, 3, fuzzyDistance([]rune("indra"), []rune("indrasya"), 2))
+}
+
+func TestFuzzyTrigramBound(t *testing.T) {
+	pairs := [][2]string{
+		{"puróhitam", "purohitam"},
+		{"vr̥trā́ṇi", "vrtrani"},
+		{"jaṅghanat", "jaghanat"},
+		{"yajñásya", "yajasya"},
+		{"hiraṇyagarbha", "hiranyagrbha"},
+	}
+	for _, p := range pairs {
+		a, b := fuzzyNormalize(p[0]), fuzzyNormalize(p[1])
+		d := fuzzyDistance([]rune(a), []rune(b), 4)
+		assert.LessOrEqual(t, d, 4, p[0])
+		// a word within the distance is never pruned by the trigram filter
+		shared := 0
+		tb := trigrams(b)
+		for _, t := range trigrams(a) {
+			for _, u := range tb {
+				if t == u {
+					shared++
+				}
+			}
+		}
+		assert.GreaterOrEqual(t, shared, minSharedTrigrams(len(tb), (d+1)/2), p[0])
+	}
+}
+
+func TestHighlightFuzzy(t *testing.T) {
+	e := &Excerpt{RomanText: []string{"agním īḷe puróhitaṁ", "yajñásya devám r̥tvíjam <x>"}}
+	matched := map[string]bool{"agnim": true, "ṛtvijam": true}
+	assert.Equal(t, "<em>agním</em> īḷe puróhitaṁ\nyajñásya devám <em>r̥tvíjam</em> &lt;x&gt;", highlightFuzzy(e, matched))
+	assert.Equal(t, []string{"agnim", "īḷe", "purohitaṃ"}, fuzzyWords("agním, īḷe | puróhitaṁ"))
+}
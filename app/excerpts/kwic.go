@@ -0,0 +1,207 @@
+package excerpts
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+)
+
+const (
+	// DefaultKWICContext is the default number of words shown on either side of a hit.
+	DefaultKWICContext = 5
+	MaxKWICContext     = 20
+	// maxKWICExportPages bounds the number of search pages exported as TSV.
+	maxKWICExportPages = 50
+)
+
+// KWICSort orders concordance lines.
+type KWICSort string
+
+const (
+	// KWICSortText keeps the lines in the order of search results.
+	KWICSortText  KWICSort = ""
+	KWICSortLeft  KWICSort = "left"
+	KWICSortRight KWICSort = "right"
+)
+
+// KWICOptions controls the layout of a keyword in context concordance.
+type KWICOptions struct {
+	// Number of words of context on either side of the hit.
+	Context int      `json:"context"`
+	Sort    KWICSort `json:"sort"`
+}
+
+// ParseKWICOptions validates the context size and sort order, using defaults for empty values.
+func ParseKWICOptions(contextStr string, sortStr string) (KWICOptions, error) {
+	opts := KWICOptions{Context: DefaultKWICContext, Sort: KWICSort(sortStr)}
+	if contextStr != "" {
+		n, err := strconv.Atoi(contextStr)
+		if err != nil || n < 0 || n > MaxKWICContext {
+			return opts, common.NewUserVisibleError(http.StatusBadRequest,
+				fmt.Sprintf("context must be a number between 0 and %d", MaxKWICContext))
+		}
+		opts.Context = n
+	}
+	switch opts.Sort {
+	case KWICSortText, KWICSortLeft, KWICSortRight:
+	default:
+		return opts, common.NewUserVisibleError(http.StatusBadRequest, "sort must be one of left or right")
+	}
+	return opts, nil
+}
+
+// KWICLine is one hit with its left and right context.
+type KWICLine struct {
+	Scripture     string `json:"scripture"`
+	ReadableIndex string `json:"readable_index"`
+	Left          string `json:"left"`
+	Keyword       string `json:"keyword"`
+	Right         string `json:"right"`
+}
+
+// KWICData holds the concordance lines of a page of search results.
+type KWICData struct {
+	ExcerptSearchData
+	Options KWICOptions `json:"options"`
+	Lines   []KWICLine  `json:"lines"`
+}
+
+type kwicWord struct {
+	text string
+	hit  bool
+}
+
+// highlightedWords splits highlighted HTML into words, marking the words which overlap
+// an <em> span. Other tags are dropped and entities are unescaped.
+func highlightedWords(hl string) []kwicWord {
+	var words []kwicWord
+	var current strings.Builder
+	inEm, currentHit := false, false
+	flush := func() {
+		if current.Len() > 0 {
+			words = append(words, kwicWord{html.UnescapeString(current.String()), currentHit})
+		}
+		current.Reset()
+		currentHit = false
+	}
+	for i := 0; i < len(hl); {
+		switch {
+		case hl[i] == '<':
+			end := strings.IndexByte(hl[i:], '>')
+			if end < 0 {
+				end = len(hl) - i - 1
+			}
+			switch strings.ToLower(hl[i : i+end+1]) {
+			case "<em>":
+				inEm = true
+			case "</em>":
+				inEm = false
+			}
+			i += end + 1
+		case hl[i] == ' ' || hl[i] == '\n' || hl[i] == '\t' || hl[i] == '\r':
+			flush()
+			i++
+		default:
+			current.WriteByte(hl[i])
+			currentHit = currentHit || inEm
+			i++
+		}
+	}
+	flush()
+	return words
+}
+
+// BuildKWIC returns one concordance line per highlighted hit in the excerpts, using the roman
+// text highlights, or the translation highlights if the roman text has none. Adjacent
+// highlighted words make up a single hit.
+func BuildKWIC(excerpts []HighlightedExcerpt, context int) []KWICLine {
+	var lines []KWICLine
+	for _, he := range excerpts {
+		hl := he.RomanHl
+		if !strings.Contains(hl, "<em>") {
+			hl = he.TranslationHl
+		}
+		words := highlightedWords(hl)
+		for i := 0; i < len(words); i++ {
+			if !words[i].hit {
+				continue
+			}
+			j := i
+			for j < len(words) && words[j].hit {
+				j++
+			}
+			lines = append(lines, KWICLine{
+				Scripture:     he.Excerpt.Scripture,
+				ReadableIndex: he.Excerpt.ReadableIndex,
+				Left:          joinKWICWords(words[max(0, i-context):i]),
+				Keyword:       joinKWICWords(words[i:j]),
+				Right:         joinKWICWords(words[j:min(len(words), j+context)]),
+			})
+			i = j
+		}
+	}
+	return lines
+}
+
+func joinKWICWords(words []kwicWord) string {
+	texts := make([]string, len(words))
+	for i, w := range words {
+		texts[i] = w.text
+	}
+	return strings.Join(texts, " ")
+}
+
+// kwicSortKey folds accents and case, so that sorting follows the letters alone.
+func kwicSortKey(s string) string {
+	return strings.ToLower(common.FoldAccents(s))
+}
+
+// SortKWIC sorts the lines by the words right of the hit, or by the words left of it read
+// outwards from the hit, then by the hit itself. The sort is stable.
+func SortKWIC(lines []KWICLine, by KWICSort) {
+	var keyOf func(l KWICLine) string
+	switch by {
+	case KWICSortLeft:
+		keyOf = func(l KWICLine) string {
+			words := strings.Fields(kwicSortKey(l.Left))
+			for i, j := 0, len(words)-1; i < j; i, j = i+1, j-1 {
+				words[i], words[j] = words[j], words[i]
+			}
+			return strings.Join(words, " ")
+		}
+	case KWICSortRight:
+		keyOf = func(l KWICLine) string { return kwicSortKey(l.Right) }
+	default:
+		return
+	}
+	sort.SliceStable(lines, func(i, j int) bool {
+		ki, kj := keyOf(lines[i]), keyOf(lines[j])
+		if ki != kj {
+			return ki < kj
+		}
+		return kwicSortKey(lines[i].Keyword) < kwicSortKey(lines[j].Keyword)
+	})
+}
+
+// WriteKWICTSV writes the lines as tab separated values with a header row.
+func WriteKWICTSV(w io.Writer, lines []KWICLine) error {
+	clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	if _, err := fmt.Fprintln(w, "scripture\treference\tleft\tkeyword\tright"); err != nil {
+		return err
+	}
+	for _, l := range lines {
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			clean.Replace(l.Scripture), clean.Replace(l.ReadableIndex),
+			clean.Replace(l.Left), clean.Replace(l.Keyword), clean.Replace(l.Right))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
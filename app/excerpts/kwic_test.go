@@ -0,0 +1,52 @@
+package excerpts
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildKWIC(t *testing.T) {
+	es := []HighlightedExcerpt{
+		{
+			Excerpt: Excerpt{Scripture: "rigveda", ReadableIndex: "1.32.1"},
+			RomanHl: "índrasya nú <em>vīryā̀ṇi</em> prá vocaṃ\náhann áhim &amp; <em>ánv</em> <em>apás</em>",
+		},
+		{
+			Excerpt:       Excerpt{Scripture: "rigveda", ReadableIndex: "1.1.1"},
+			TranslationHl: "I Laud <em>Agni</em>, the chosen Priest",
+		},
+		{
+			// regex matches inside a word mark the whole word
+			Excerpt: Excerpt{Scripture: "rigveda", ReadableIndex: "10.1.1"},
+			RomanHl: "agnír <em>vr̥</em>trā́ṇi jaṅghanat",
+		},
+	}
+	lines := BuildKWIC(es, 2)
+	assert.Equal(t, []KWICLine{
+		{"rigveda", "1.32.1", "índrasya nú", "vīryā̀ṇi", "prá vocaṃ"},
+		{"rigveda", "1.32.1", "áhim &", "ánv apás", ""},
+		{"rigveda", "1.1.1", "I Laud", "Agni,", "the chosen"},
+		{"rigveda", "10.1.1", "agnír", "vr̥trā́ṇi", "jaṅghanat"},
+	}, lines)
+
+	SortKWIC(lines, KWICSortRight)
+	assert.Equal(t, []string{"1.32.1", "10.1.1", "1.32.1", "1.1.1"}, kwicRefs(lines))
+
+	// left context is compared from the word nearest to the hit
+	SortKWIC(lines, KWICSortLeft)
+	assert.Equal(t, []string{"1.32.1", "10.1.1", "1.1.1", "1.32.1"}, kwicRefs(lines))
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteKWICTSV(&buf, lines[:1]))
+	assert.Equal(t, "scripture\treference\tleft\tkeyword\tright\nrigveda\t1.32.1\táhim &\tánv apás\t\n", buf.String())
+}
+
+func kwicRefs(lines []KWICLine) []string {
+	var refs []string
+	for _, l := range lines {
+		refs = append(refs, l.ReadableIndex)
+	}
+	return refs
+}
@@ -0,0 +1,239 @@
+package excerpts
+
+import (
+	"html"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/prosody"
+)
+
+// MorphFeature is an attribute of a glossing token which can be constrained in a morphological search.
+type MorphFeature string
+
+const (
+	MorphLemma   MorphFeature = "lemma"
+	MorphSurface MorphFeature = "surface"
+	MorphRoot    MorphFeature = "root"
+	MorphGramm   MorphFeature = "gramm"
+	MorphCase    MorphFeature = "case"
+	MorphNumber  MorphFeature = "number"
+	MorphGender  MorphFeature = "gender"
+	MorphTense   MorphFeature = "tense"
+	MorphVoice   MorphFeature = "voice"
+	MorphPerson  MorphFeature = "person"
+	MorphMood    MorphFeature = "mood"
+	// MorphAccented is the surface with its accents, compared without folding them.
+	MorphAccented MorphFeature = "accented"
+	// MorphAccent is where the surface is accented, see prosody.Placement.
+	MorphAccent MorphFeature = "accent"
+	// MorphPath is not a token attribute, but restricts the search to a part of the scripture.
+	MorphPath MorphFeature = "path"
+)
+
+var knownMorphFeatures = []MorphFeature{
+	MorphLemma, MorphSurface, MorphRoot, MorphGramm, MorphCase, MorphNumber,
+	MorphGender, MorphTense, MorphVoice, MorphPerson, MorphMood, MorphAccented, MorphAccent, MorphPath,
+}
+
+// IsSanskrit reports whether the values of this feature are sanskrit words rather than grammatical tags.
+func (f MorphFeature) IsSanskrit() bool {
+	return f == MorphLemma || f == MorphSurface || f == MorphRoot || f == MorphAccented
+}
+
+// morphTagFeatures lets well known grammatical tags be used without a feature prefix, eg: `GEN DU`.
+var morphTagFeatures = map[string]MorphFeature{
+	"ABL": MorphCase, "ACC": MorphCase, "DAT": MorphCase, "GEN": MorphCase,
+	"INS": MorphCase, "LOC": MorphCase, "NOM": MorphCase, "VOC": MorphCase,
+	"SG": MorphNumber, "DU": MorphNumber, "PL": MorphNumber,
+	"M": MorphGender, "F": MorphGender, "N": MorphGender,
+	"PRS": MorphTense, "AOR": MorphTense, "PRF": MorphTense, "IPRF": MorphTense,
+	"FUT": MorphTense, "PLUPRF": MorphTense,
+	"IND": MorphMood, "SBJV": MorphMood, "OPT": MorphMood, "IMP": MorphMood,
+	"INJ": MorphMood, "COND": MorphMood,
+	"ACT": MorphVoice, "MED": MorphVoice, "PASS": MorphVoice,
+}
+
+// MorphConstraint requires a token feature to be equal to one of Values.
+type MorphConstraint struct {
+	Feature MorphFeature
+	Values  []string
+	// Prefix is true if the values ended with `*`, only allowed for sanskrit features.
+	Prefix bool
+}
+
+// MorphQuery matches excerpts having at least one glossing token which satisfies all constraints.
+type MorphQuery struct {
+	Constraints []MorphConstraint
+}
+
+// ParseMorphQuery parses a morphological query such as `lemma:aśvin case:GEN number:DU`.
+//
+// Each space separated part is a `feature:value` constraint, where alternatives are
+// separated by `|` (eg: `case:GEN|DAT`). Well known grammatical tags can be given without
+// the feature name, eg: `AOR SBJV path:7`.
+func ParseMorphQuery(q string) (*MorphQuery, error) {
+	parts := strings.Fields(q)
+	if len(parts) == 0 {
+		return nil, newQueryError("query is empty")
+	}
+	if len(parts) > maxQueryTerms {
+		return nil, newQueryError("too many constraints, at most %d are allowed", maxQueryTerms)
+	}
+
+	var mq MorphQuery
+	hasTokenConstraint := false
+	for _, part := range parts {
+		name, value, hasFeature := strings.Cut(part, ":")
+		var feature MorphFeature
+		if !hasFeature {
+			// bare tags, eg: `GEN` or `GEN|DAT`, must all belong to the same feature
+			for tag := range strings.SplitSeq(part, "|") {
+				f, ok := morphTagFeatures[strings.ToUpper(tag)]
+				if !ok {
+					return nil, newQueryError("%q is not a known grammatical tag, use feature:value syntax (eg: lemma:%s)", tag, tag)
+				}
+				if feature != "" && f != feature {
+					return nil, newQueryError("alternatives in %q are not of the same feature", part)
+				}
+				feature = f
+			}
+			value = part
+		} else {
+			name = strings.ToLower(name)
+			for _, f := range knownMorphFeatures {
+				if string(f) == name {
+					feature = f
+				}
+			}
+			if feature == "" {
+				known := make([]string, len(knownMorphFeatures))
+				for i, f := range knownMorphFeatures {
+					known[i] = string(f)
+				}
+				return nil, newQueryError("unknown feature %q, expected one of: %s", name, strings.Join(known, ", "))
+			}
+		}
+
+		c := MorphConstraint{Feature: feature}
+		for v := range strings.SplitSeq(value, "|") {
+			if strings.HasSuffix(v, "*") {
+				if !feature.IsSanskrit() {
+					return nil, newQueryError("prefix search is only supported for lemma, surface, root and accented, found %q", part)
+				}
+				c.Prefix = true
+				v = strings.TrimSuffix(v, "*")
+			}
+			if v == "" || strings.ContainsRune(v, '*') {
+				return nil, newQueryError("invalid value in %q", part)
+			}
+			if feature == MorphPath {
+				if _, err := common.StringToPath(v); err != nil {
+					return nil, newQueryError("invalid path %q", v)
+				}
+			} else if !feature.IsSanskrit() {
+				v = strings.ToUpper(v)
+			}
+			c.Values = append(c.Values, v)
+		}
+		if feature != MorphPath {
+			hasTokenConstraint = true
+		}
+		mq.Constraints = append(mq.Constraints, c)
+	}
+	if !hasTokenConstraint {
+		return nil, newQueryError("at least one constraint on lemma, surface or grammatical features is required")
+	}
+	return &mq, nil
+}
+
+// glossingFeature returns the value of a feature of the token, normalized the same way
+// as it is stored in the glossings index.
+func glossingFeature(g *WordGlossing, f MorphFeature) string {
+	switch f {
+	case MorphLemma:
+		return common.NormalizeLemma(g.Lemma)
+	case MorphSurface:
+		return common.NormalizeSurface(g.Surface)
+	case MorphRoot:
+		return common.NormalizeLemma(g.Root)
+	case MorphGramm:
+		return grammTags(g)
+	case MorphCase:
+		return g.Case
+	case MorphNumber:
+		return g.Number
+	case MorphGender:
+		return g.Gender
+	case MorphTense:
+		return g.Tense
+	case MorphVoice:
+		return g.Voice
+	case MorphPerson:
+		return g.Person
+	case MorphMood:
+		return g.Mood
+	case MorphAccented:
+		return accentedSurface(g.Surface)
+	case MorphAccent:
+		return string(prosody.AccentOf(g.Surface).Placement())
+	}
+	return ""
+}
+
+// grammTags joins the grammatical tags of a glossing, eg: "VOC SG M", so that a pattern like
+// `.*VOC.*` can match any of them.
+func grammTags(g *WordGlossing) string {
+	var tags []string
+	for _, t := range []string{g.Case, g.Number, g.Gender, g.Tense, g.Voice, g.Person, g.Mood} {
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return strings.Join(tags, " ")
+}
+
+// accentedSurface normalizes a surface like common.NormalizeSurface, but keeps the accents.
+func accentedSurface(surface string) string {
+	return common.NormalizeAccents(strings.TrimSuffix(surface, " +"))
+}
+
+// highlightTokens renders the roman text of the excerpt with the given glossing tokens wrapped
+// in <em> tags. If the words of a line cannot be aligned with its glossings, the whole line
+// (pada) is highlighted instead.
+func highlightTokens(e *Excerpt, tokens []TokenRef) string {
+	return strings.Join(highlightTokenLines(e, tokens), "\n")
+}
+
+// highlightTokenLines is like highlightTokens, but returns the lines separately.
+func highlightTokenLines(e *Excerpt, tokens []TokenRef) []string {
+	matched := make(map[int]map[int]bool)
+	for _, t := range tokens {
+		if matched[t.Line] == nil {
+			matched[t.Line] = make(map[int]bool)
+		}
+		matched[t.Line][t.Position] = true
+	}
+
+	lines := make([]string, len(e.RomanText))
+	for i, line := range e.RomanText {
+		positions := matched[i]
+		if len(positions) == 0 {
+			lines[i] = html.EscapeString(line)
+			continue
+		}
+		words := strings.Fields(line)
+		if i >= len(e.Glossings) || len(words) != len(e.Glossings[i]) {
+			lines[i] = "<em>" + html.EscapeString(line) + "</em>"
+			continue
+		}
+		for j, w := range words {
+			words[j] = html.EscapeString(w)
+			if positions[j] {
+				words[j] = "<em>" + words[j] + "</em>"
+			}
+		}
+		lines[i] = strings.Join(words, " ")
+	}
+	return lines
+}
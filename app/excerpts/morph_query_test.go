@@ -0,0 +1,39 @@
+package excerpts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMorphQuery(t *testing.T) {
+	mq, err := ParseMorphQuery("lemma:aśvin gen|dat DU path:1.116 surface:agn*")
+	assert.NoError(t, err)
+	assert.Equal(t, []MorphConstraint{
+		{Feature: MorphLemma, Values: []string{"aśvin"}},
+		{Feature: MorphCase, Values: []string{"GEN", "DAT"}},
+		{Feature: MorphNumber, Values: []string{"DU"}},
+		{Feature: MorphPath, Values: []string{"1.116"}},
+		{Feature: MorphSurface, Values: []string{"agn"}, Prefix: true},
+	}, mq.Constraints)
+
+	for _, q := range []string{"", "agni", "deity:agni", "case:GEN*", "path:1", "lemma:", "path:x"} {
+		_, err := ParseMorphQuery(q)
+		var uve *common.UserVisibleError
+		assert.True(t, errors.As(err, &uve), "expected a UserVisibleError for %q, got %v", q, err)
+	}
+}
+
+func TestHighlightTokens(t *testing.T) {
+	e := &Excerpt{
+		RomanText: []string{"agním īḷe puróhitaṁ", "yajñásya devám r̥tvíjam"},
+		Glossings: [][]WordGlossing{
+			{{Surface: "agním"}, {Surface: "īḷe"}, {Surface: "puróhitam"}},
+			{{Surface: "yajñásya"}, {Surface: "devám"}},
+		},
+	}
+	assert.Equal(t, "agním <em>īḷe</em> puróhitaṁ\n<em>yajñásya devám r̥tvíjam</em>",
+		highlightTokens(e, []TokenRef{{Line: 0, Position: 1}, {Line: 1, Position: 0}}))
+}
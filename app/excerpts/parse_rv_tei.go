@@ -27,7 +27,7 @@ type Text struct {
 }
 
 type Body struct {
-	Div Div `xml:"div"`
+	Divs []Div `xml:"div"`
 }
 
 type P struct {
@@ -131,8 +131,16 @@ func ConvertRvTeiToExcerpts(file io.Reader) ([]Excerpt, error) {
 
 	var excerpts []Excerpt
 
-	// Navigate to book level
-	bookDiv := tei.Text.Body.Div
+	// Navigate to book level. VedaWeb files have one book each, exports may have several.
+	for _, bookDiv := range tei.Text.Body.Divs {
+		excerpts = append(excerpts, convertRvTeiBook(bookDiv)...)
+	}
+
+	return excerpts, nil
+}
+
+func convertRvTeiBook(bookDiv Div) []Excerpt {
+	var excerpts []Excerpt
 	var bookNum int
 	fmt.Sscanf(bookDiv.ID, "b%d", &bookNum)
 
@@ -164,6 +172,20 @@ func ConvertRvTeiToExcerpts(file io.Reader) ([]Excerpt, error) {
 							}
 						}
 					}
+					if subDiv.Type == "author" {
+						for _, p := range subDiv.Ps {
+							if p.Lang == "eng" {
+								authors = append(authors, p.CharData)
+							}
+						}
+					}
+					if subDiv.Type == "meter" {
+						for _, p := range subDiv.Ps {
+							if p.Lang == "eng" {
+								meter = p.CharData
+							}
+						}
+					}
 				}
 			}
 
@@ -248,7 +270,7 @@ func ConvertRvTeiToExcerpts(file io.Reader) ([]Excerpt, error) {
 		}
 	}
 
-	return excerpts, nil
+	return excerpts
 }
 
 func extractNumber(id string) int {
@@ -348,6 +370,10 @@ func extractGlossings(lg *LG) [][]WordGlossing {
 			if len(modifiers) > 0 {
 				glossing.Modifiers = modifiers
 			}
+			if glossing.Gramm == "root" {
+				// verb forms are glossed with their root as the lemma, eg: īḍ-
+				glossing.Root = strings.TrimSuffix(glossing.Lemma, "-")
+			}
 
 			lineGlossings = append(lineGlossings, glossing)
 		}
@@ -498,7 +524,7 @@ func WriteExcerptsToJsonL(excerpts []Excerpt, outPath string) error {
 	return nil
 }
 
-func PreprocessRvDataset(teiInputDir, outputDir, embeddingsFile string) error {
+func PreprocessRvDataset(teiInputDir, outputDir, embeddingsFile, anukramaniFile string) error {
 	// Load embeddings
 	embeddings := make(map[string][]embeddingRelated)
 	if embeddingsFile != "" {
@@ -526,6 +552,25 @@ func PreprocessRvDataset(teiInputDir, outputDir, embeddingsFile string) error {
 			slog.Info("Loaded embeddings for excerpts", "count", len(embeddings))
 		}
 	}
+	// Load anukramani
+	var anukramani map[string]AnukramaniHymn
+	if anukramaniFile != "" {
+		file, err := os.Open(anukramaniFile)
+		if err != nil {
+			if os.IsNotExist(err) {
+				slog.Warn("anukramani file not found, skipping", "path", anukramaniFile)
+			} else {
+				return fmt.Errorf("opening anukramani file: %w", err)
+			}
+		} else {
+			anukramani, err = ReadAnukramani(file)
+			file.Close()
+			if err != nil {
+				return fmt.Errorf("reading anukramani file: %w", err)
+			}
+			slog.Info("Loaded anukramani of hymns", "count", len(anukramani))
+		}
+	}
 	// Ensure output directory exists
 	if err := os.MkdirAll(outputDir, 0o755); err != nil {
 		return fmt.Errorf("creating output directory: %w", err)
@@ -594,7 +639,13 @@ func PreprocessRvDataset(teiInputDir, outputDir, embeddingsFile string) error {
 		}
 	}
 
+	if len(anukramani) > 0 {
+		n := applyAnukramani(allExcerpts, anukramani)
+		slog.Info("Applied anukramani to excerpts", "count", n)
+	}
+
 	computeTextualSuggestions(allExcerpts)
+	computeFormulas(allExcerpts)
 
 	// Append excerpts to output file
 	if err := WriteExcerptsToJsonL(allExcerpts, outputPath); err != nil {
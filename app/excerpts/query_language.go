@@ -0,0 +1,388 @@
+package excerpts
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+)
+
+// QueryField is a field prefix usable in the advanced query language, eg: `addressee:agni`.
+type QueryField string
+
+const (
+	// FieldDefault is used for terms without a field prefix, and searches the roman text.
+	FieldDefault     QueryField = ""
+	FieldAddressee   QueryField = "addressee"
+	FieldAuthor      QueryField = "author"
+	FieldMeter       QueryField = "meter"
+	FieldLemma       QueryField = "lemma"
+	FieldSurface     QueryField = "surface"
+	FieldTranslation QueryField = "translation"
+	FieldPath        QueryField = "path"
+)
+
+var knownQueryFields = []QueryField{
+	FieldAddressee, FieldAuthor, FieldMeter, FieldLemma, FieldSurface, FieldTranslation, FieldPath,
+}
+
+// IsSanskrit reports whether the terms of this field are sanskrit text, which should be
+// transliterated from the user's input scheme before searching.
+func (f QueryField) IsSanskrit() bool {
+	return f == FieldDefault || f == FieldLemma || f == FieldSurface
+}
+
+type QueryOp int
+
+const (
+	OpTerm QueryOp = iota
+	OpAnd
+	OpOr
+	OpNot
+)
+
+// maxQueryTerms bounds the number of terms in a single query, since every term
+// becomes a separate FTS lookup.
+const maxQueryTerms = 32
+
+// QueryNode is a node of the parsed advanced query. Leaf nodes have Op == OpTerm.
+type QueryNode struct {
+	Op       QueryOp
+	Children []*QueryNode
+
+	Field QueryField
+	Text  string
+	// Phrase is true if the term was quoted.
+	Phrase bool
+	// Prefix is true if the term ended with `*`.
+	Prefix bool
+}
+
+// Terms returns all leaf nodes in the query. If positiveOnly is set, terms under a NOT are skipped.
+func (n *QueryNode) Terms(positiveOnly bool) []*QueryNode {
+	var res []*QueryNode
+	var walk func(n *QueryNode)
+	walk = func(n *QueryNode) {
+		switch n.Op {
+		case OpTerm:
+			res = append(res, n)
+		case OpNot:
+			if !positiveOnly {
+				walk(n.Children[0])
+			}
+		default:
+			for _, c := range n.Children {
+				walk(c)
+			}
+		}
+	}
+	walk(n)
+	return res
+}
+
+// String renders the query back in the query language syntax, with explicit operators.
+func (n *QueryNode) String() string {
+	switch n.Op {
+	case OpTerm:
+		var sb strings.Builder
+		if n.Field != FieldDefault {
+			sb.WriteString(string(n.Field))
+			sb.WriteString(":")
+		}
+		if n.Phrase {
+			sb.WriteString(`"` + n.Text + `"`)
+		} else {
+			sb.WriteString(n.Text)
+		}
+		if n.Prefix {
+			sb.WriteString("*")
+		}
+		return sb.String()
+	case OpNot:
+		return "NOT " + n.Children[0].String()
+	default:
+		op := " AND "
+		if n.Op == OpOr {
+			op = " OR "
+		}
+		parts := make([]string, len(n.Children))
+		for i, c := range n.Children {
+			parts[i] = c.String()
+		}
+		return "(" + strings.Join(parts, op) + ")"
+	}
+}
+
+type queryTokenKind int
+
+const (
+	tokWord queryTokenKind = iota
+	tokPhrase
+	tokField
+	tokLParen
+	tokRParen
+	tokAnd
+	tokOr
+	tokNot
+	tokEOF
+)
+
+type queryToken struct {
+	kind queryTokenKind
+	text string
+	pos  int
+}
+
+func newQueryError(format string, args ...any) *common.UserVisibleError {
+	return common.NewUserVisibleError(http.StatusBadRequest, "invalid query: "+fmt.Sprintf(format, args...))
+}
+
+func isQuerySpecial(r rune) bool {
+	return r == '(' || r == ')' || r == '"' || unicode.IsSpace(r)
+}
+
+func tokenizeQuery(q string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(q)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == '"':
+			start := i
+			i++
+			var sb strings.Builder
+			for i < len(runes) && runes[i] != '"' {
+				sb.WriteRune(runes[i])
+				i++
+			}
+			if i >= len(runes) {
+				return nil, newQueryError("unterminated quote starting at position %d", start+1)
+			}
+			i++ // closing quote
+			tok := queryToken{kind: tokPhrase, text: strings.TrimSpace(sb.String()), pos: start}
+			if i < len(runes) && runes[i] == '*' {
+				tok.text += "*"
+				i++
+			}
+			tokens = append(tokens, tok)
+		case r == '-' && (len(tokens) == 0 || i == 0 || isQuerySpecial(runes[i-1])):
+			// `-term` is a shorthand for `NOT term`
+			tokens = append(tokens, queryToken{kind: tokNot, text: "-", pos: i})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !isQuerySpecial(runes[i]) && runes[i] != ':' {
+				i++
+			}
+			word := string(runes[start:i])
+			if i < len(runes) && runes[i] == ':' {
+				i++
+				tokens = append(tokens, queryToken{kind: tokField, text: word, pos: start})
+				continue
+			}
+			switch word {
+			case "AND":
+				tokens = append(tokens, queryToken{kind: tokAnd, text: word, pos: start})
+			case "OR":
+				tokens = append(tokens, queryToken{kind: tokOr, text: word, pos: start})
+			case "NOT":
+				tokens = append(tokens, queryToken{kind: tokNot, text: word, pos: start})
+			default:
+				tokens = append(tokens, queryToken{kind: tokWord, text: word, pos: start})
+			}
+		}
+	}
+	tokens = append(tokens, queryToken{kind: tokEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+	nTerms int
+}
+
+func (p *queryParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() queryToken {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// ParseQuery parses the advanced query syntax.
+//
+// Terms are combined with AND (default when adjacent), OR and NOT (or a leading `-`), in
+// decreasing order of precedence NOT, AND, OR. Parentheses group expressions, double quotes
+// form phrases, a trailing `*` makes a prefix term, and a `field:` prefix restricts a term
+// or a parenthesized group to one field. Errors are returned as UserVisibleError.
+func ParseQuery(q string) (*QueryNode, error) {
+	tokens, err := tokenizeQuery(q)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, newQueryError("query is empty")
+	}
+	node, err := p.parseOr(FieldDefault)
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, newQueryError("unexpected %q at position %d", t.text, t.pos+1)
+	}
+	if len(node.Terms(true)) == 0 {
+		return nil, newQueryError("query must contain at least one term which is not negated")
+	}
+	return node, nil
+}
+
+func (p *queryParser) parseOr(field QueryField) (*QueryNode, error) {
+	first, err := p.parseAnd(field)
+	if err != nil {
+		return nil, err
+	}
+	children := []*QueryNode{first}
+	for p.peek().kind == tokOr {
+		p.next()
+		c, err := p.parseAnd(field)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, c)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &QueryNode{Op: OpOr, Children: children}, nil
+}
+
+func (p *queryParser) parseAnd(field QueryField) (*QueryNode, error) {
+	first, err := p.parseUnary(field)
+	if err != nil {
+		return nil, err
+	}
+	children := []*QueryNode{first}
+	for {
+		t := p.peek()
+		if t.kind == tokAnd {
+			p.next()
+		} else if t.kind == tokEOF || t.kind == tokOr || t.kind == tokRParen {
+			break
+		}
+		c, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, c)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &QueryNode{Op: OpAnd, Children: children}, nil
+}
+
+func (p *queryParser) parseUnary(field QueryField) (*QueryNode, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		c, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryNode{Op: OpNot, Children: []*QueryNode{c}}, nil
+	}
+	return p.parsePrimary(field)
+}
+
+func (p *queryParser) parsePrimary(field QueryField) (*QueryNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokLParen:
+		node, err := p.parseOr(field)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, newQueryError("missing closing parenthesis for the one at position %d", t.pos+1)
+		}
+		return node, nil
+	case tokField:
+		if field != FieldDefault {
+			return nil, newQueryError("nested field %q at position %d inside field %q", t.text, t.pos+1, field)
+		}
+		f, err := parseQueryField(t)
+		if err != nil {
+			return nil, err
+		}
+		if nt := p.peek(); nt.kind != tokWord && nt.kind != tokPhrase && nt.kind != tokLParen {
+			return nil, newQueryError("expected a term after %q at position %d", t.text+":", t.pos+1)
+		}
+		return p.parsePrimary(f)
+	case tokWord, tokPhrase:
+		return p.newTerm(t, field)
+	case tokEOF:
+		return nil, newQueryError("unexpected end of query, expected a term")
+	default:
+		return nil, newQueryError("unexpected %q at position %d, expected a term", t.text, t.pos+1)
+	}
+}
+
+func parseQueryField(t queryToken) (QueryField, error) {
+	name := strings.ToLower(t.text)
+	for _, f := range knownQueryFields {
+		if string(f) == name {
+			return f, nil
+		}
+	}
+	known := make([]string, len(knownQueryFields))
+	for i, f := range knownQueryFields {
+		known[i] = string(f)
+	}
+	return "", newQueryError("unknown field %q at position %d, expected one of: %s", t.text, t.pos+1, strings.Join(known, ", "))
+}
+
+func (p *queryParser) newTerm(t queryToken, field QueryField) (*QueryNode, error) {
+	p.nTerms++
+	if p.nTerms > maxQueryTerms {
+		return nil, newQueryError("too many terms, at most %d are allowed", maxQueryTerms)
+	}
+	text := t.text
+	prefix := strings.HasSuffix(text, "*")
+	text = strings.TrimRight(text, "*")
+	if strings.ContainsRune(text, '*') {
+		return nil, newQueryError("'*' is only allowed at the end of a term, at position %d", t.pos+1)
+	}
+	if text == "" {
+		return nil, newQueryError("empty term at position %d", t.pos+1)
+	}
+	if field == FieldPath {
+		if prefix || t.kind == tokPhrase {
+			return nil, newQueryError("path must be a plain verse path like 10 or 1.32, at position %d", t.pos+1)
+		}
+		if _, err := common.StringToPath(text); err != nil {
+			return nil, newQueryError("invalid path %q at position %d", text, t.pos+1)
+		}
+	}
+	return &QueryNode{
+		Op:     OpTerm,
+		Field:  field,
+		Text:   text,
+		Phrase: t.kind == tokPhrase,
+		Prefix: prefix,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package excerpts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{"Single term", "agni", "agni"},
+		{"Implicit AND", "agni indra", "(agni AND indra)"},
+		{"Explicit operators", "agni AND indra OR soma", "((agni AND indra) OR soma)"},
+		{"NOT binds tighter than AND", "agni NOT indra", "(agni AND NOT indra)"},
+		{"Minus as NOT", "vṛtra -indra", "(vṛtra AND NOT indra)"},
+		{"Grouping", "(agni OR indra) soma", "((agni OR indra) AND soma)"},
+		{"Phrase", `"agnim īḷe"`, `"agnim īḷe"`},
+		{"Prefix", "agn*", "agn*"},
+		{"Field prefix", "addressee:Agni meter:triṣṭubh", "(addressee:Agni AND meter:triṣṭubh)"},
+		{"Field case insensitive", "Lemma:indra", "lemma:indra"},
+		{"Field with group", "addressee:(Agni OR Indra)", "(addressee:Agni OR addressee:Indra)"},
+		{"Field with phrase", `translation:"mighty one"`, `translation:"mighty one"`},
+		{"Path", "path:10 agni", "(path:10 AND agni)"},
+		{
+			"Combined facets",
+			"addressee:agni meter:triṣṭubh vṛtra NOT indra",
+			"(addressee:agni AND meter:triṣṭubh AND vṛtra AND NOT indra)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			node, err := ParseQuery(tc.query)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, node.String())
+		})
+	}
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	testCases := []struct {
+		name  string
+		query string
+	}{
+		{"Empty", "   "},
+		{"Unknown field", "deity:agni"},
+		{"Unbalanced open", "(agni OR indra"},
+		{"Unbalanced close", "agni)"},
+		{"Dangling operator", "agni OR"},
+		{"Unterminated quote", `"agnim īḷe`},
+		{"Only negations", "NOT indra"},
+		{"Field without term", "lemma: OR agni"},
+		{"Nested field", "lemma:(surface:agni)"},
+		{"Invalid path", "path:1.x"},
+		{"Wildcard in middle", "ag*ni"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseQuery(tc.query)
+			var uve *common.UserVisibleError
+			assert.True(t, errors.As(err, &uve), "expected a UserVisibleError, got %v", err)
+			if uve != nil {
+				assert.Equal(t, 400, uve.HttpCode)
+			}
+		})
+	}
+}
+
+func TestCompileQueryNode(t *testing.T) {
+	node, err := ParseQuery("addressee:agni path:1 -indra")
+	assert.NoError(t, err)
+
+	var args []any
+	sql := compileQueryNode(node, &args)
+	assert.Equal(t, "(ex.rowid IN (SELECT rowid FROM dhee_excerpts_fts WHERE dhee_excerpts_fts MATCH ?)"+
+		" AND (ex.sort_index = ? OR ex.sort_index LIKE ?)"+
+		" AND (NOT ex.rowid IN (SELECT rowid FROM dhee_excerpts_fts WHERE dhee_excerpts_fts MATCH ?)))", sql)
+	assert.Equal(t, []any{`addressees : "agni"`, "00001", "00001.%", `roman_t : "indra"`}, args)
+
+	assert.Equal(t, "", queryHighlightMatch(node, FieldDefault))
+	assert.Equal(t, `addressees : ("agni")`, queryHighlightMatch(node, FieldAddressee))
+}
@@ -1,9 +1,12 @@
 package excerpts
 
 import (
+	"strings"
+
 	"github.com/mahesh-hegde/dhee/app/common"
 	"github.com/mahesh-hegde/dhee/app/config"
 	"github.com/mahesh-hegde/dhee/app/dictionary"
+	"github.com/mahesh-hegde/dhee/app/prosody"
 )
 
 type Modifier string
@@ -25,14 +28,26 @@ type WordGlossing struct {
 }
 
 type PadaElement struct {
-	Word            string
-	Found           bool
-	ExactMatched    bool
-	G               WordGlossing
-	Slp1NormLemma   string
-	Slp1NormSurface string
-	SurfaceMeaning  dictionary.DictionaryEntry
-	LemmaMeaning    dictionary.DictionaryEntry
+	Word            string                     `json:"word"`
+	Found           bool                       `json:"found"`
+	ExactMatched    bool                       `json:"exact_matched"`
+	G               WordGlossing               `json:"g"`
+	Slp1NormLemma   string                     `json:"slp1_norm_lemma"`
+	Slp1NormSurface string                     `json:"slp1_norm_surface"`
+	SurfaceMeaning  dictionary.DictionaryEntry `json:"surface_meaning"`
+	LemmaMeaning    dictionary.DictionaryEntry `json:"lemma_meaning"`
+	// Meanings has the entries of the surface and the lemma in each dictionary having either,
+	// in the order of the scripture's dictionaries. SurfaceMeaning and LemmaMeaning are the
+	// first of these.
+	Meanings []SourcedMeaning `json:"meanings,omitempty"`
+}
+
+// SourcedMeaning has the entries of a word's surface and lemma in one dictionary.
+type SourcedMeaning struct {
+	DictName       string                     `json:"dict_name"`
+	ReadableName   string                     `json:"readable_name"`
+	SurfaceMeaning dictionary.DictionaryEntry `json:"surface_meaning"`
+	LemmaMeaning   dictionary.DictionaryEntry `json:"lemma_meaning"`
 }
 
 type Auxiliary struct {
@@ -54,13 +69,30 @@ type Related struct {
 
 type SearchParams struct {
 	// name of scriptures to search
-	Scriptures []string
-	Mode       common.SearchMode
-	Q          string
-	OriginalQ  string
-	Tl         string
+	Scriptures []string          `json:"scriptures"`
+	Mode       common.SearchMode `json:"mode"`
+	Q          string            `json:"q"`
+	OriginalQ  string            `json:"original_q"`
+	Tl         string            `json:"tl"`
 	// Name of auxiliary, or "0" for sanskrit text, or "1" for roman text. Empty implies all auxiliaries, roman and source text
-	SearchIn []string
+	SearchIn []string `json:"search_in"`
+	// Facets narrows the results to excerpts having all of these facet values.
+	Facets []FacetFilter `json:"facets"`
+	// Page is the 1-based page of results, see common.SearchPageSize.
+	Page int `json:"page"`
+	// Scope restricts the search to paths, ranges or verse sets, see ParseScope.
+	Scope string `json:"scope"`
+
+	// parsed holds the parsed query when Mode is common.SearchQuery.
+	parsed *QueryNode
+	// morph holds the parsed query when Mode is common.SearchMorph.
+	morph *MorphQuery
+	// cql holds the parsed pattern when Mode is common.SearchCQL.
+	cql *CQLPattern
+	// fuzzy holds the expanded query words when Mode is common.SearchFuzzy.
+	fuzzy *FuzzyQuery
+	// scope holds the parsed Scope.
+	scope []PathRange
 }
 
 // Excerpt represents a single atomic unit from the source text. Eg: a Rik in case of Rigveda.
@@ -91,6 +123,13 @@ type Excerpt struct {
 	Suggested         []Related            `json:"suggested,omitempty"`
 	SuggestedSemantic []Related            `json:"suggested_semantic,omitempty"`
 	SuggestedTextual  []Related            `json:"suggested_textual,omitempty"`
+	// Repeated word sequences occurring in this excerpt
+	Formulas []FormulaOccurrence `json:"formulas,omitempty"`
+	// Scansion of the roman text, computed while indexing
+	Scansion *prosody.Scansion `json:"scansion,omitempty"`
+	// Anukramani is the attribution of the verse by an anukramani dataset, if one was given
+	// to preprocess.
+	Anukramani *Attribution `json:"anukramani,omitempty"`
 }
 
 // ExcerptInDB is the type sent to SQLite3, with the content of main excerpt serialized without indexing,
@@ -126,25 +165,32 @@ type ExcerptInDB struct {
 }
 
 type HierParent struct {
-	Number   int
-	FullPath string
-	Type     string
+	Number   int    `json:"number"`
+	FullPath string `json:"full_path"`
+	Type     string `json:"type"`
 }
 
 type Hierarchy struct {
-	Scripture *config.ScriptureDefn
-	Path      []HierParent
-	ChildType string
-	Children  []int
-	IsLeaf    bool
+	Scripture *config.ScriptureDefn `json:"scripture"`
+	Path      []HierParent          `json:"path"`
+	ChildType string                `json:"child_type"`
+	Children  []int                 `json:"children"`
+	IsLeaf    bool                  `json:"is_leaf"`
 }
 
 type HighlightedExcerpt struct {
-	Excerpt       Excerpt
-	SourceHl      string // Highlighted source text
-	RomanHl       string // Highlighted roman text
-	TranslationHl string // Highlighted translation text
-	NotesHl       []string
+	Excerpt       Excerpt    `json:"excerpt"`
+	SourceHl      string     `json:"source_hl"`      // Highlighted source text
+	RomanHl       string     `json:"roman_hl"`       // Highlighted roman text
+	TranslationHl string     `json:"translation_hl"` // Highlighted translation text
+	NotesHl       []string   `json:"notes_hl"`
+	Tokens        []TokenRef `json:"tokens"` // Matched glossing tokens, set by token level searches
+}
+
+// TokenRef locates a glossing token in an excerpt, as indices into Excerpt.Glossings.
+type TokenRef struct {
+	Line     int `json:"line"`
+	Position int `json:"position"`
 }
 
 // Type implements mapping.Classifier.
@@ -155,26 +201,175 @@ func (e *ExcerptInDB) Type() string {
 type ExcerptWithWords struct {
 	Excerpt
 	// Dict words indexed by morphological surface & lemma
-	Words map[string]dictionary.DictionaryEntry
-	Padas []PadaElement
+	Words map[string]dictionary.DictionaryEntry `json:"words"`
+	Padas []PadaElement                         `json:"padas"`
+	// Roman text lines with the words of repeated formulas highlighted
+	FormulaHl []string `json:"formula_hl"`
+	// Dictionary words with meanings citing this excerpt
+	CitedIn []dictionary.CitingWord `json:"cited_in,omitempty"`
 }
 
 type ExcerptTemplateData struct {
-	Excerpts        []ExcerptWithWords
-	Scripture       config.ScriptureDefn
-	GlossingMap     map[string]WordGlossing
-	AddressedTo     string
-	Next            string
-	Previous        string
-	Up              string
-	UpType          string
-	GrammaticalTags map[string]common.GrammaticalTagStyle
+	Excerpts        []ExcerptWithWords                    `json:"excerpts"`
+	Scripture       config.ScriptureDefn                  `json:"scripture"`
+	GlossingMap     map[string]WordGlossing               `json:"glossing_map"`
+	AddressedTo     string                                `json:"addressed_to"`
+	Next            string                                `json:"next"`
+	Previous        string                                `json:"previous"`
+	Up              string                                `json:"up"`
+	UpType          string                                `json:"up_type"`
+	GrammaticalTags map[string]common.GrammaticalTagStyle `json:"grammatical_tags"`
 }
 
 type ExcerptSearchData struct {
-	Excerpts  []HighlightedExcerpt
-	Search    SearchParams
-	Scripture config.ScriptureDefn
+	Excerpts  []HighlightedExcerpt `json:"excerpts"`
+	Search    SearchParams         `json:"search"`
+	Scripture config.ScriptureDefn `json:"scripture"`
+	// Facets are counted over all matches, not only the returned excerpts, and only for the
+	// first page.
+	Facets     []Facet           `json:"facets"`
+	Pagination common.Pagination `json:"pagination"`
+}
+
+// FormulaIndexData holds a page of the repeated formulas of a scripture.
+type FormulaIndexData struct {
+	Scripture  config.ScriptureDefn `json:"scripture"`
+	Formulas   []Formula            `json:"formulas"`
+	Pagination common.Pagination    `json:"pagination"`
+}
+
+// FormulaData holds a formula and a page of the excerpts it occurs in.
+type FormulaData struct {
+	Scripture  config.ScriptureDefn `json:"scripture"`
+	Formula    Formula              `json:"formula"`
+	Excerpts   []HighlightedExcerpt `json:"excerpts"`
+	Pagination common.Pagination    `json:"pagination"`
+}
+
+// SyllableCount counts the padas of a meter having a number of syllables.
+type SyllableCount struct {
+	Syllables int `json:"syllables"`
+	Padas     int `json:"padas"`
+	Irregular int `json:"irregular"`
+}
+
+// MeterStats summarizes the scansion of the verses labelled with a meter.
+type MeterStats struct {
+	Meter string `json:"meter"`
+	// Checked is true if the meter is a regular meter the verses were compared with.
+	Checked         bool            `json:"checked"`
+	Verses          int             `json:"verses"`
+	IrregularVerses int             `json:"irregular_verses"`
+	Padas           int             `json:"padas"`
+	IrregularPadas  int             `json:"irregular_padas"`
+	SyllableCounts  []SyllableCount `json:"syllable_counts"`
+}
+
+// MeterIndexData holds the meters of a scripture.
+type MeterIndexData struct {
+	Scripture config.ScriptureDefn `json:"scripture"`
+	Meters    []MeterStats         `json:"meters"`
+}
+
+// MeterData holds the statistics of a meter and a page of the verses deviating from it.
+type MeterData struct {
+	Scripture  config.ScriptureDefn `json:"scripture"`
+	Stats      MeterStats           `json:"stats"`
+	Excerpts   []Excerpt            `json:"excerpts"`
+	Pagination common.Pagination    `json:"pagination"`
+}
+
+// AccentForm is an attested form of a lemma with its accentuation and grammatical tags.
+type AccentForm struct {
+	// Surface is the accented surface, see common.NormalizeAccents.
+	Surface   string            `json:"surface"`
+	Placement prosody.Placement `json:"placement"`
+	Case      string            `json:"case,omitempty"`
+	Number    string            `json:"number,omitempty"`
+	Gender    string            `json:"gender,omitempty"`
+	Tense     string            `json:"tense,omitempty"`
+	Mood      string            `json:"mood,omitempty"`
+	Voice     string            `json:"voice,omitempty"`
+	Person    string            `json:"person,omitempty"`
+	Count     int               `json:"count"`
+}
+
+// Tags joins the grammatical tags of the form, eg: "ACC SG M".
+func (f AccentForm) Tags() string {
+	var tags []string
+	for _, t := range []string{f.Case, f.Number, f.Gender, f.Tense, f.Mood, f.Voice, f.Person} {
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return strings.Join(tags, " ")
+}
+
+// PlacementCount counts the occurrences of forms accented on a syllable.
+type PlacementCount struct {
+	Placement prosody.Placement `json:"placement"`
+	Count     int               `json:"count"`
+}
+
+// AccentCategory counts the accent placements of the forms sharing grammatical tags.
+type AccentCategory struct {
+	Tags       string           `json:"tags"`
+	Count      int              `json:"count"`
+	Placements []PlacementCount `json:"placements"`
+}
+
+// AccentData summarizes the accentuation of the attested forms of a lemma.
+type AccentData struct {
+	Scripture config.ScriptureDefn `json:"scripture"`
+	// Lemma is the lemma as given, and empty when none was requested.
+	Lemma      string           `json:"lemma"`
+	Tl         string           `json:"tl"`
+	Total      int              `json:"total"`
+	Placements []PlacementCount `json:"placements"`
+	Categories []AccentCategory `json:"categories"`
+	Forms      []AccentForm     `json:"forms"`
+}
+
+// AttributionCount counts the hymns and verses attributed to a name.
+type AttributionCount struct {
+	Name   string `json:"name"`
+	Hymns  int    `json:"hymns"`
+	Verses int    `json:"verses"`
+}
+
+// AttributionIndexData holds the names of a kind attributed by the anukramani of a scripture.
+type AttributionIndexData struct {
+	Scripture config.ScriptureDefn `json:"scripture"`
+	Kind      AttributionKind      `json:"kind"`
+	Names     []AttributionCount   `json:"names"`
+}
+
+// AttributedHymn is a hymn with some of its verses attributed to a name.
+type AttributedHymn struct {
+	// Hymn is the readable path of the hymn, eg: 7.18.
+	Hymn string `json:"hymn"`
+	// Verses is the number of verses attributed to the name, out of TotalVerses.
+	Verses      int `json:"verses"`
+	TotalVerses int `json:"total_verses"`
+	// Attribution merges the attributions of all verses of the hymn.
+	Attribution Attribution `json:"attribution"`
+}
+
+// AttributionData holds the hymns attributed to a name.
+type AttributionData struct {
+	Scripture config.ScriptureDefn `json:"scripture"`
+	Kind      AttributionKind      `json:"kind"`
+	Name      string               `json:"name"`
+	Verses    int                  `json:"verses"`
+	Hymns     []AttributedHymn     `json:"hymns"`
+}
+
+// ScopeStats holds statistics over a scripture or a part of it.
+type ScopeStats struct {
+	Excerpts int `json:"excerpts"`
+	// Words is the number of glossed words.
+	Words  int `json:"words"`
+	Lemmas int `json:"lemmas"`
 }
 
 type QualifiedPath struct {
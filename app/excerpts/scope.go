@@ -0,0 +1,113 @@
+package excerpts
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/config"
+)
+
+// PathRange is an inclusive range of hierarchy subtrees. A single subtree such as
+// mandala 10 has the same Start and End.
+type PathRange struct {
+	Start []int
+	End   []int
+}
+
+func (r PathRange) String() string {
+	if slices.Equal(r.Start, r.End) {
+		return common.PathToString(r.Start)
+	}
+	return common.PathToString(r.Start) + "-" + common.PathToString(r.End)
+}
+
+// sortIndexBounds returns the bounds of sort_index values within the range, the
+// upper bound being exclusive. Since '/' sorts right after '.', appending it to
+// the end path gives a bound past every descendant of the end subtree.
+func (r PathRange) sortIndexBounds() (lower, upper string) {
+	return common.PathToSortString(r.Start), common.PathToSortString(r.End) + "/"
+}
+
+// ParseScope parses a comma separated list of paths (`10`), ranges (`1.1-1.50`
+// or `1.1-50`) and names of verse sets defined for the given scriptures.
+func ParseScope(scope string, scriptures []config.ScriptureDefn) ([]PathRange, error) {
+	var ranges []PathRange
+	for _, part := range strings.Split(scope, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if part[0] < '0' || part[0] > '9' {
+			set := findVerseSet(part, scriptures)
+			if set == nil {
+				return nil, newScopeError("unknown verse set %q", part)
+			}
+			for _, p := range set.Paths {
+				r, err := parsePathRange(p)
+				if err != nil {
+					return nil, err
+				}
+				ranges = append(ranges, r)
+			}
+			continue
+		}
+		r, err := parsePathRange(part)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func findVerseSet(name string, scriptures []config.ScriptureDefn) *config.VerseSetDefn {
+	for i := range scriptures {
+		if set := scriptures[i].GetVerseSet(name); set != nil {
+			return set
+		}
+	}
+	return nil
+}
+
+func parsePathRange(s string) (PathRange, error) {
+	startStr, endStr, isRange := strings.Cut(s, "-")
+	start, err := parseScopePath(startStr)
+	if err != nil {
+		return PathRange{}, err
+	}
+	if !isRange {
+		return PathRange{Start: start, End: start}, nil
+	}
+	end, err := parseScopePath(endStr)
+	if err != nil {
+		return PathRange{}, err
+	}
+	// 1.1-50 is a shorthand for 1.1-1.50, like in excerpt URLs
+	if len(end) < len(start) {
+		end = append(append([]int{}, start[:len(start)-len(end)]...), end...)
+	}
+	if common.PathToSortString(start) > common.PathToSortString(end) {
+		return PathRange{}, newScopeError("range %q ends before it starts", s)
+	}
+	return PathRange{Start: start, End: end}, nil
+}
+
+func parseScopePath(s string) ([]int, error) {
+	var path []int
+	for _, part := range strings.Split(strings.TrimSpace(s), ".") {
+		i, err := strconv.Atoi(part)
+		if err != nil || i < 0 {
+			return nil, newScopeError("invalid path %q", s)
+		}
+		path = append(path, i)
+	}
+	return path, nil
+}
+
+func newScopeError(format string, args ...any) *common.UserVisibleError {
+	return common.NewUserVisibleError(http.StatusBadRequest, "invalid scope: "+fmt.Sprintf(format, args...))
+}
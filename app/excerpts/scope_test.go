@@ -0,0 +1,51 @@
+package excerpts
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScope(t *testing.T) {
+	scriptures := []config.ScriptureDefn{{
+		Name: "rigveda",
+		VerseSets: []config.VerseSetDefn{
+			{Name: "family-books", Paths: []string{"2-7"}},
+		},
+	}}
+	testCases := []struct {
+		scope    string
+		expected []PathRange
+	}{
+		{"10", []PathRange{{[]int{10}, []int{10}}}},
+		{"1.1-1.50", []PathRange{{[]int{1, 1}, []int{1, 50}}}},
+		{"1.1-50", []PathRange{{[]int{1, 1}, []int{1, 50}}}},
+		{"family-books, 1.164", []PathRange{{[]int{2}, []int{7}}, {[]int{1, 164}, []int{1, 164}}}},
+		{"", nil},
+	}
+	for _, tc := range testCases {
+		ranges, err := ParseScope(tc.scope, scriptures)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, ranges)
+	}
+
+	for _, s := range []string{"late-books", "1.x", "7-2", "1.50-1.1"} {
+		_, err := ParseScope(s, scriptures)
+		var uve *common.UserVisibleError
+		assert.True(t, errors.As(err, &uve), "expected a UserVisibleError for %q, got %v", s, err)
+	}
+
+	// the upper bound includes every descendant of the end subtree, but not the next sibling
+	lower, upper := PathRange{[]int{2}, []int{7}}.sortIndexBounds()
+	for _, path := range [][]int{{2}, {2, 1, 1}, {7, 104, 25}} {
+		s := common.PathToSortString(path)
+		assert.True(t, s >= lower && s < upper, "%v should be within the range", path)
+	}
+	for _, path := range [][]int{{1, 191, 16}, {8, 1, 1}, {10}} {
+		s := common.PathToSortString(path)
+		assert.False(t, s >= lower && s < upper, "%v should be outside the range", path)
+	}
+}
@@ -8,11 +8,14 @@ import (
 	"fmt"
 	"html"
 	"log/slog"
+	"net/http"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/mahesh-hegde/dhee/app/common"
 	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/mahesh-hegde/dhee/app/prosody"
 )
 
 type SQLiteExcerptStore struct {
@@ -74,6 +77,139 @@ func (s *SQLiteExcerptStore) Init() error {
 	if err != nil {
 		return fmt.Errorf("failed to create dhee_excerpts_translations_fts table: %w", err)
 	}
+
+	// one row per glossing token, for morphological searches
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dhee_glossings (
+			excerpt_rowid INTEGER NOT NULL,
+			line INTEGER NOT NULL,
+			position INTEGER NOT NULL,
+			surface TEXT,
+			lemma TEXT,
+			root TEXT,
+			gramm TEXT,
+			nominal_case TEXT,
+			number TEXT,
+			gender TEXT,
+			tense TEXT,
+			voice TEXT,
+			person TEXT,
+			mood TEXT,
+			accented TEXT,
+			accent TEXT
+		);
+		CREATE INDEX IF NOT EXISTS idx_glossing_lemma ON dhee_glossings(lemma);
+		CREATE INDEX IF NOT EXISTS idx_glossing_surface ON dhee_glossings(surface);
+		CREATE INDEX IF NOT EXISTS idx_glossing_root ON dhee_glossings(root);
+		CREATE INDEX IF NOT EXISTS idx_glossing_accented ON dhee_glossings(accented);
+		CREATE INDEX IF NOT EXISTS idx_glossing_excerpt ON dhee_glossings(excerpt_rowid);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create dhee_glossings table: %w", err)
+	}
+
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dhee_excerpt_facets (
+			excerpt_rowid INTEGER NOT NULL,
+			facet TEXT NOT NULL,
+			value TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_excerpt_facets_value ON dhee_excerpt_facets(facet, value);
+		CREATE INDEX IF NOT EXISTS idx_excerpt_facets_excerpt ON dhee_excerpt_facets(excerpt_rowid);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create dhee_excerpt_facets table: %w", err)
+	}
+
+	// repeated formulas, computed during preprocessing, and where they start in each excerpt
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dhee_formulas (
+			scripture TEXT NOT NULL,
+			id TEXT NOT NULL,
+			basis TEXT NOT NULL,
+			text TEXT NOT NULL,
+			length INTEGER NOT NULL,
+			occurrences INTEGER NOT NULL,
+			PRIMARY KEY (scripture, id)
+		);
+		CREATE INDEX IF NOT EXISTS idx_formulas_occurrences ON dhee_formulas(scripture, occurrences);
+		CREATE TABLE IF NOT EXISTS dhee_formula_occurrences (
+			scripture TEXT NOT NULL,
+			formula_id TEXT NOT NULL,
+			excerpt_rowid INTEGER NOT NULL,
+			line INTEGER NOT NULL,
+			position INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_formula_occurrences_formula ON dhee_formula_occurrences(scripture, formula_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create dhee_formulas tables: %w", err)
+	}
+
+	// scansions of the verses computed while indexing, and the syllable counts of their padas
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dhee_scansions (
+			excerpt_rowid INTEGER PRIMARY KEY,
+			scripture TEXT NOT NULL,
+			meter TEXT NOT NULL,
+			padas INTEGER NOT NULL,
+			irregular_padas INTEGER NOT NULL,
+			irregular INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_scansions_meter ON dhee_scansions(scripture, meter);
+		CREATE TABLE IF NOT EXISTS dhee_padas (
+			excerpt_rowid INTEGER NOT NULL,
+			scripture TEXT NOT NULL,
+			meter TEXT NOT NULL,
+			line INTEGER NOT NULL,
+			syllables INTEGER NOT NULL,
+			irregular INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_padas_meter ON dhee_padas(scripture, meter);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create dhee_scansions tables: %w", err)
+	}
+
+	// seers, deities, families and meters of the verses, from the anukramani or the excerpt
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dhee_attributions (
+			excerpt_rowid INTEGER NOT NULL,
+			scripture TEXT NOT NULL,
+			hymn TEXT NOT NULL,
+			hymn_sort TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			name TEXT NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_attributions_name ON dhee_attributions(scripture, kind, name);
+		CREATE INDEX IF NOT EXISTS idx_attributions_hymn ON dhee_attributions(scripture, hymn);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create dhee_attributions table: %w", err)
+	}
+
+	// vocabulary of normalized words with their trigrams, for fuzzy searches
+	_, err = s.db.Exec(`
+		CREATE TABLE IF NOT EXISTS dhee_fuzzy_words (
+			id INTEGER PRIMARY KEY,
+			word TEXT NOT NULL UNIQUE,
+			length INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_fuzzy_words_length ON dhee_fuzzy_words(length);
+		CREATE TABLE IF NOT EXISTS dhee_fuzzy_trigrams (
+			trigram TEXT NOT NULL,
+			word_id INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_fuzzy_trigrams_trigram ON dhee_fuzzy_trigrams(trigram);
+		CREATE TABLE IF NOT EXISTS dhee_fuzzy_postings (
+			word_id INTEGER NOT NULL,
+			excerpt_rowid INTEGER NOT NULL
+		);
+		CREATE INDEX IF NOT EXISTS idx_fuzzy_postings_word ON dhee_fuzzy_postings(word_id);
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create dhee_fuzzy tables: %w", err)
+	}
 	return nil
 }
 
@@ -115,12 +251,115 @@ func (s *SQLiteExcerptStore) Add(ctx context.Context, scripture string, es []Exc
 	}
 	defer translFtsStmt.Close()
 
+	glossingStmt, err := tx.Prepare(`
+		INSERT INTO dhee_glossings (
+			excerpt_rowid, line, position, surface, lemma, root, gramm,
+			nominal_case, number, gender, tense, voice, person, mood, accented, accent
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer glossingStmt.Close()
+
+	facetStmt, err := tx.Prepare("INSERT INTO dhee_excerpt_facets (excerpt_rowid, facet, value) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer facetStmt.Close()
+
+	formulaStmt, err := tx.Prepare(`
+		INSERT INTO dhee_formulas (scripture, id, basis, text, length, occurrences)
+		VALUES (?, ?, ?, ?, ?, ?) ON CONFLICT DO NOTHING
+	`)
+	if err != nil {
+		return err
+	}
+	defer formulaStmt.Close()
+
+	formulaOccStmt, err := tx.Prepare("INSERT INTO dhee_formula_occurrences (scripture, formula_id, excerpt_rowid, line, position) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer formulaOccStmt.Close()
+
+	scansionStmt, err := tx.Prepare(`
+		INSERT INTO dhee_scansions (excerpt_rowid, scripture, meter, padas, irregular_padas, irregular)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer scansionStmt.Close()
+
+	padaStmt, err := tx.Prepare("INSERT INTO dhee_padas (excerpt_rowid, scripture, meter, line, syllables, irregular) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer padaStmt.Close()
+
+	attributionStmt, err := tx.Prepare("INSERT INTO dhee_attributions (excerpt_rowid, scripture, hymn, hymn_sort, kind, name) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	defer attributionStmt.Close()
+
+	fuzzyWordStmt, err := tx.Prepare("INSERT INTO dhee_fuzzy_words (word, length) VALUES (?, ?)")
+	if err != nil {
+		return err
+	}
+	defer fuzzyWordStmt.Close()
+
+	trigramStmt, err := tx.Prepare("INSERT INTO dhee_fuzzy_trigrams (trigram, word_id) VALUES (?, ?)")
+	if err != nil {
+		return err
+	}
+	defer trigramStmt.Close()
+
+	postingStmt, err := tx.Prepare("INSERT INTO dhee_fuzzy_postings (word_id, excerpt_rowid) VALUES (?, ?)")
+	if err != nil {
+		return err
+	}
+	defer postingStmt.Close()
+
+	// fuzzyWordID returns the id of a word in the fuzzy vocabulary, adding it if needed.
+	fuzzyWordIDs := make(map[string]int64)
+	fuzzyWordID := func(word string) (int64, error) {
+		if id, ok := fuzzyWordIDs[word]; ok {
+			return id, nil
+		}
+		var id int64
+		err := tx.QueryRowContext(ctx, "SELECT id FROM dhee_fuzzy_words WHERE word = ?", word).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			res, err := fuzzyWordStmt.ExecContext(ctx, word, utf8.RuneCountInString(word))
+			if err != nil {
+				return 0, err
+			}
+			if id, err = res.LastInsertId(); err != nil {
+				return 0, err
+			}
+			for _, t := range trigrams(word) {
+				if _, err := trigramStmt.ExecContext(ctx, t, id); err != nil {
+					return 0, err
+				}
+			}
+		} else if err != nil {
+			return 0, err
+		}
+		fuzzyWordIDs[word] = id
+		return id, nil
+	}
+
 	for _, e := range es {
 		e.Scripture = scripture
 		if e.ReadableIndex == "" {
 			e.ReadableIndex = common.PathToString(e.Path)
 		}
 		id := fmt.Sprintf("%d:%s", s.conf.ScriptureNameToId(scripture), e.ReadableIndex)
+		if len(e.RomanText) > 0 {
+			scansion := prosody.Scan(e.RomanText, e.Meter)
+			e.Scansion = &scansion
+		}
 
 		entryJSON, _ := json.Marshal(e)
 
@@ -132,11 +371,76 @@ func (s *SQLiteExcerptStore) Add(ctx context.Context, scripture string, es []Exc
 		romanT := strings.Join(e.RomanText, "\n")
 		romanF := common.FoldAccents(normalizeRomanTextForKwStorage(e.RomanText))
 
-		_, err = stmt.ExecContext(ctx, id, scripture, sortIndex, e.ReadableIndex, romanT, romanF, entryJSON)
+		res, err := stmt.ExecContext(ctx, id, scripture, sortIndex, e.ReadableIndex, romanT, romanF, entryJSON)
+		if err != nil {
+			return err
+		}
+		rowid, err := res.LastInsertId()
 		if err != nil {
 			return err
 		}
 
+		for line := range e.Glossings {
+			for position := range e.Glossings[line] {
+				g := &e.Glossings[line][position]
+				glossingArgs := []any{rowid, line, position}
+				for _, f := range glossingColumnFeatures {
+					glossingArgs = append(glossingArgs, glossingFeature(g, f))
+				}
+				if _, err := glossingStmt.ExecContext(ctx, glossingArgs...); err != nil {
+					return err
+				}
+			}
+		}
+		for _, f := range excerptFacets(&e) {
+			if _, err := facetStmt.ExecContext(ctx, rowid, f.Name, f.Value); err != nil {
+				return err
+			}
+		}
+		for _, f := range e.Formulas {
+			if _, err := formulaStmt.ExecContext(ctx, scripture, f.ID, f.Basis, f.Text, f.Length, f.Occurrences); err != nil {
+				return err
+			}
+			if _, err := formulaOccStmt.ExecContext(ctx, scripture, f.ID, rowid, f.Start.Line, f.Start.Position); err != nil {
+				return err
+			}
+		}
+
+		if sc := e.Scansion; sc != nil {
+			_, err := scansionStmt.ExecContext(ctx, rowid, scripture, e.Meter, len(sc.Padas), sc.IrregularPadas(), sc.Irregular())
+			if err != nil {
+				return err
+			}
+			for line, p := range sc.Padas {
+				if _, err := padaStmt.ExecContext(ctx, rowid, scripture, e.Meter, line, len(p.Syllables), len(p.Issues) > 0); err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(e.Path) > 1 {
+			hymn := e.Path[:len(e.Path)-1]
+			a := excerptAttribution(&e)
+			for _, kind := range append(AttributionIndexKinds, AttributionChandas) {
+				for _, name := range a.names(kind) {
+					_, err := attributionStmt.ExecContext(ctx, rowid, scripture, common.PathToString(hymn), common.PathToSortString(hymn), kind, name)
+					if err != nil {
+						return err
+					}
+				}
+			}
+		}
+
+		for _, word := range excerptFuzzyWords(&e) {
+			wordID, err := fuzzyWordID(word)
+			if err != nil {
+				return err
+			}
+			if _, err := postingStmt.ExecContext(ctx, wordID, rowid); err != nil {
+				return err
+			}
+		}
+
 		sourceT := html.EscapeString(strings.Join(e.SourceText, "\n"))
 		var surfaces []string
 		var lemmas []string
@@ -227,6 +531,40 @@ func (s *SQLiteExcerptStore) Get(ctx context.Context, paths []QualifiedPath) ([]
 	return excerpts, rows.Err()
 }
 
+func (s *SQLiteExcerptStore) GetRange(ctx context.Context, scripture string, scope []PathRange, limit int) ([]Excerpt, error) {
+	query := "SELECT ex.e FROM dhee_excerpts ex WHERE ex.scripture = ?"
+	args := []any{scripture}
+	if cond, scopeArgs := scopeCondition(scope); cond != "" {
+		query += " AND " + cond
+		args = append(args, scopeArgs...)
+	}
+	query += " ORDER BY ex.sort_index"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var excerpts []Excerpt
+	for rows.Next() {
+		var excerptJSON []byte
+		if err := rows.Scan(&excerptJSON); err != nil {
+			return nil, err
+		}
+		var excerpt Excerpt
+		if err := json.Unmarshal(excerptJSON, &excerpt); err != nil {
+			return nil, err
+		}
+		excerpts = append(excerpts, excerpt)
+	}
+	return excerpts, rows.Err()
+}
+
 func (s *SQLiteExcerptStore) FindBeforeAndAfter(ctx context.Context, scripture string, idsBefore []string, idsAfter []string) (prev string, next string) {
 	allIds := make([]any, 0, len(idsBefore)+len(idsAfter))
 
@@ -278,45 +616,145 @@ func (s *SQLiteExcerptStore) FindBeforeAndAfter(ctx context.Context, scripture s
 	return before, after
 }
 
-func (s *SQLiteExcerptStore) Search(ctx context.Context, scriptures []string, params SearchParams) ([]HighlightedExcerpt, error) {
+// searchSQL holds the clauses of a scripture search. The FROM and WHERE clauses are shared
+// between the results query and the facet counts, so that facets are counted over all matches.
+type searchSQL struct {
+	selectCols string
+	selectArgs []any
+	from       string
+	where      []string
+	whereArgs  []any
+	groupBy    string
+	orderBy    string
+	orderArgs  []any
+	// limit is 0 when the matches are filtered after decoding, as in CQL searches.
+	limit  int
+	offset int
+}
+
+func (q *searchSQL) resultsQuery() (string, []any) {
+	query := "SELECT " + q.selectCols + " FROM " + q.from + " WHERE " + strings.Join(q.where, " AND ")
+	if q.groupBy != "" {
+		query += " GROUP BY " + q.groupBy
+	}
+	query += " ORDER BY " + q.orderBy
+	if q.limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d OFFSET %d", q.limit, q.offset)
+	}
+	args := make([]any, 0, len(q.selectArgs)+len(q.whereArgs)+len(q.orderArgs))
+	args = append(args, q.selectArgs...)
+	args = append(args, q.whereArgs...)
+	args = append(args, q.orderArgs...)
+	return query, args
+}
+
+func (q *searchSQL) countQuery() (string, []any) {
+	query := "SELECT count(DISTINCT ex.rowid) FROM " + q.from + " WHERE " + strings.Join(q.where, " AND ")
+	return query, q.whereArgs
+}
+
+func (q *searchSQL) facetsQuery() (string, []any) {
+	query := `
+		SELECT facet, value, count(*) FROM dhee_excerpt_facets
+		WHERE excerpt_rowid IN (SELECT ex.rowid FROM ` + q.from + ` WHERE ` + strings.Join(q.where, " AND ") + `)
+		GROUP BY facet, value`
+	return query, q.whereArgs
+}
+
+// buildSearchSQL builds the search query for the mode of params. Every mode selects the
+// excerpt as the first column, followed by mode specific highlight columns. Results are
+// ordered by rowid last, so that pages are stable.
+func (s *SQLiteExcerptStore) buildSearchSQL(scriptures []string, params SearchParams) (*searchSQL, error) {
 	q := params.Q
 	scripturePlaceholders := "?"
 	if len(scriptures) > 1 {
 		scripturePlaceholders += strings.Repeat(",?", len(scriptures)-1)
 	}
 
-	args := make([]any, 0, len(scriptures)+1)
+	qb := &searchSQL{
+		selectCols: "ex.e",
+		from:       "dhee_excerpts AS ex",
+		where:      []string{"ex.scripture IN (" + scripturePlaceholders + ")"},
+		orderBy:    "ex.sort_index",
+		limit:      common.SearchPageSize,
+		offset:     common.NewPagination(params.Page).Offset(),
+	}
 	for _, s := range scriptures {
-		args = append(args, s)
+		qb.whereArgs = append(qb.whereArgs, s)
 	}
 
-	var fullQuery string
 	switch params.Mode {
 	case common.SearchRegex:
-		query := `
-		SELECT e FROM (
-			SELECT e, (roman_t REGEXP ?) AS t_match, (roman_f REGEXP ?) AS f_match, sort_index
-			FROM dhee_excerpts
-			WHERE scripture IN (` + scripturePlaceholders + `) AND (t_match OR f_match)
-			ORDER by t_match desc, sort_index
-		)
-		`
-		newArgs := make([]any, 0, len(args)+2)
-		newArgs = append(newArgs, q, common.FoldAccents(q))
-		newArgs = append(newArgs, args...)
-		args = newArgs
-		fullQuery = query
+		qb.where = append(qb.where, "(ex.roman_t REGEXP ? OR ex.roman_f REGEXP ?)")
+		qb.whereArgs = append(qb.whereArgs, q, common.FoldAccents(q))
+		// matches in the accented text come first
+		qb.orderBy = "(ex.roman_t REGEXP ?) DESC, ex.sort_index"
+		qb.orderArgs = append(qb.orderArgs, q)
 	case common.SearchTranslations:
 		// Use highlight() on the translations FTS table
-		orderBy := "ORDER BY t_fts.rank, ex.sort_index"
-		query := `
-			SELECT ex.e, highlight(dhee_excerpts_translations_fts, 0, '<em>', '</em>') as translation_hl
-			FROM dhee_excerpts_translations_fts t_fts
-				JOIN dhee_excerpts AS ex ON t_fts.rowid = ex.rowid
-			WHERE ex.scripture IN (` + scripturePlaceholders + `) AND t_fts.translation MATCH ?
-			` + orderBy + ` LIMIT 100`
-		fullQuery = query
-		args = append(args, q)
+		qb.selectCols = "ex.e, highlight(dhee_excerpts_translations_fts, 0, '<em>', '</em>') AS translation_hl"
+		qb.from = "dhee_excerpts_translations_fts AS t_fts JOIN dhee_excerpts AS ex ON t_fts.rowid = ex.rowid"
+		qb.where = append(qb.where, "t_fts.translation MATCH ?")
+		qb.whereArgs = append(qb.whereArgs, q)
+		qb.orderBy = "t_fts.rank, ex.sort_index"
+	case common.SearchQuery:
+		if params.parsed == nil {
+			return nil, errors.New("advanced query was not parsed before search")
+		}
+		romanHl, translationHl := "NULL", "NULL"
+		if m := queryHighlightMatch(params.parsed, FieldDefault); m != "" {
+			romanHl = `(SELECT highlight(dhee_excerpts_fts, 1, '<em>', '</em>') FROM dhee_excerpts_fts WHERE dhee_excerpts_fts MATCH ? AND rowid = ex.rowid)`
+			qb.selectArgs = append(qb.selectArgs, m)
+		}
+		if m := queryHighlightMatch(params.parsed, FieldTranslation); m != "" {
+			translationHl = `(SELECT highlight(dhee_excerpts_translations_fts, 0, '<em>', '</em>') FROM dhee_excerpts_translations_fts WHERE dhee_excerpts_translations_fts MATCH ? AND rowid = ex.rowid)`
+			qb.selectArgs = append(qb.selectArgs, m)
+		}
+		qb.selectCols = "ex.e, " + romanHl + " AS roman_hl, " + translationHl + " AS translation_hl"
+		qb.where = append(qb.where, compileQueryNode(params.parsed, &qb.whereArgs))
+	case common.SearchCQL:
+		if params.cql == nil {
+			return nil, errors.New("CQL pattern was not parsed before search")
+		}
+		// The SQL only narrows down the candidates, which are matched against the pattern
+		// after decoding.
+		qb.where = append(qb.where, compileCQLPrefilter(params.cql, &qb.whereArgs))
+		qb.limit = 0
+	case common.SearchFuzzy:
+		if params.fuzzy == nil {
+			return nil, errors.New("fuzzy query was not expanded before search, see ExpandFuzzy")
+		}
+		// every query word must match one of its expansions
+		for _, ids := range params.fuzzy.expansions {
+			if len(ids) == 0 {
+				qb.where = append(qb.where, "0")
+				continue
+			}
+			qb.where = append(qb.where, "ex.rowid IN (SELECT excerpt_rowid FROM dhee_fuzzy_postings WHERE word_id IN (?"+strings.Repeat(",?", len(ids)-1)+"))")
+			for _, id := range ids {
+				qb.whereArgs = append(qb.whereArgs, id)
+			}
+		}
+		// excerpts with exact matches come first
+		if exact := params.fuzzy.exact; len(exact) > 0 {
+			qb.orderBy = "(ex.rowid IN (SELECT excerpt_rowid FROM dhee_fuzzy_postings WHERE word_id IN (?" + strings.Repeat(",?", len(exact)-1) + "))) DESC, ex.sort_index"
+			for _, id := range exact {
+				qb.orderArgs = append(qb.orderArgs, id)
+			}
+		}
+	case common.SearchMorph:
+		if params.morph == nil {
+			return nil, errors.New("morphological query was not parsed before search")
+		}
+		qb.selectCols = "ex.e, group_concat(g.line || ':' || g.position) AS tokens"
+		qb.from = "dhee_glossings AS g JOIN dhee_excerpts AS ex ON ex.rowid = g.excerpt_rowid"
+		qb.where = append(qb.where, compileMorphQuery(params.morph, &qb.whereArgs))
+		qb.groupBy = "ex.rowid"
+	case common.SearchAccent:
+		qb.selectCols = "ex.e, group_concat(g.line || ':' || g.position) AS tokens"
+		qb.from = "dhee_glossings AS g JOIN dhee_excerpts AS ex ON ex.rowid = g.excerpt_rowid"
+		qb.where = append(qb.where, compileAccentQuery(q, &qb.whereArgs))
+		qb.groupBy = "ex.rowid"
 	default:
 		var ftsQuery, ftsColumn string
 		switch params.Mode {
@@ -326,8 +764,6 @@ func (s *SQLiteExcerptStore) Search(ctx context.Context, scriptures []string, pa
 		case common.SearchPrefix:
 			ftsQuery = q + "*"
 			ftsColumn = "roman_t"
-		case common.SearchFuzzy:
-			return nil, errors.New("fuzzy search is not supported on excerpts with sqlite store")
 		default:
 			ftsQuery = q
 			ftsColumn = "roman_t"
@@ -335,59 +771,500 @@ func (s *SQLiteExcerptStore) Search(ctx context.Context, scriptures []string, pa
 
 		slog.Debug("Not using FTS column", "ftsColumn", ftsColumn)
 
-		orderBy := "ORDER BY ex_fts.rank, ex.sort_index"
-		query := `
-			SELECT ex.e, highlight(dhee_excerpts_fts, 1, '<em>', '</em>') as roman_hl
-			FROM dhee_excerpts_fts AS ex_fts JOIN dhee_excerpts AS ex ON ex_fts.rowid = ex.rowid
-			WHERE ex.scripture IN (` + scripturePlaceholders + `) AND dhee_excerpts_fts MATCH ?`
-		fullQuery = query + " " + orderBy + " LIMIT 100"
-		args = append(args, ftsQuery)
+		qb.selectCols = "ex.e, highlight(dhee_excerpts_fts, 1, '<em>', '</em>') AS roman_hl"
+		qb.from = "dhee_excerpts_fts AS ex_fts JOIN dhee_excerpts AS ex ON ex_fts.rowid = ex.rowid"
+		qb.where = append(qb.where, "dhee_excerpts_fts MATCH ?")
+		qb.whereArgs = append(qb.whereArgs, ftsQuery)
+		qb.orderBy = "ex_fts.rank, ex.sort_index"
+	}
+
+	if cond, args := scopeCondition(params.scope); cond != "" {
+		qb.where = append(qb.where, cond)
+		qb.whereArgs = append(qb.whereArgs, args...)
+	}
+	for _, f := range params.Facets {
+		qb.where = append(qb.where, "ex.rowid IN (SELECT excerpt_rowid FROM dhee_excerpt_facets WHERE facet = ? AND value = ?)")
+		qb.whereArgs = append(qb.whereArgs, f.Name, f.Value)
+	}
+	qb.orderBy += ", ex.rowid"
+	return qb, nil
+}
+
+func (s *SQLiteExcerptStore) Search(ctx context.Context, scriptures []string, params SearchParams) ([]HighlightedExcerpt, int, error) {
+	qb, err := s.buildSearchSQL(scriptures, params)
+	if err != nil {
+		return nil, 0, err
 	}
 
+	var total int
+	if params.Mode != common.SearchCQL {
+		countQuery, countArgs := qb.countQuery()
+		if err := s.db.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total); err != nil {
+			return nil, 0, fmt.Errorf("sqlite search count failed: %w", err)
+		}
+	}
+
+	fullQuery, args := qb.resultsQuery()
 	rows, err := s.db.QueryContext(ctx, fullQuery, args...)
 	if err != nil {
-		return nil, fmt.Errorf("sqlite search failed: %w", err)
+		return nil, 0, fmt.Errorf("sqlite search failed: %w", err)
 	}
 	defer rows.Close()
 
+	// CQL matches are counted and paged here, since the SQL only selects candidates
+	cqlSkip := common.NewPagination(params.Page).Offset()
+
 	var excerpts []HighlightedExcerpt
 
 	for rows.Next() {
+		// CQL candidates are matched here, which may take long over a whole scripture
+		if err := ctx.Err(); err != nil {
+			return nil, 0, err
+		}
 		var excerptJSON []byte
-		var translationHl, romanHl sql.NullString
+		var translationHl, romanHl, tokens sql.NullString
 
 		switch params.Mode {
-		case common.SearchRegex:
+		case common.SearchRegex, common.SearchCQL, common.SearchFuzzy:
 			if err := rows.Scan(&excerptJSON); err != nil {
-				return nil, err
+				return nil, 0, err
 			}
 		case common.SearchTranslations:
 			if err := rows.Scan(&excerptJSON, &translationHl); err != nil {
-				return nil, err
+				return nil, 0, err
+			}
+		case common.SearchQuery:
+			if err := rows.Scan(&excerptJSON, &romanHl, &translationHl); err != nil {
+				return nil, 0, err
+			}
+		case common.SearchMorph, common.SearchAccent:
+			if err := rows.Scan(&excerptJSON, &tokens); err != nil {
+				return nil, 0, err
 			}
 		default: // All other FTS modes
 			if err := rows.Scan(&excerptJSON, &romanHl); err != nil {
-				return nil, err
+				return nil, 0, err
 			}
 		}
 
 		var excerpt Excerpt
 		if err := json.Unmarshal(excerptJSON, &excerpt); err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		hlExcerpt := HighlightedExcerpt{Excerpt: excerpt}
+		if params.Mode == common.SearchCQL {
+			hlExcerpt.Tokens = params.cql.Match(&excerpt)
+			if len(hlExcerpt.Tokens) == 0 {
+				continue
+			}
+			total++
+			if cqlSkip > 0 {
+				cqlSkip--
+				continue
+			}
+			if len(excerpts) == common.SearchPageSize {
+				continue
+			}
+		}
 
+		if params.Mode == common.SearchFuzzy {
+			hlExcerpt.RomanHl = highlightFuzzy(&excerpt, params.fuzzy.matched)
+		}
 		if translationHl.Valid {
 			hlExcerpt.TranslationHl = translationHl.String
 		}
 		if romanHl.Valid {
 			hlExcerpt.RomanHl = romanHl.String
 		}
+		if tokens.Valid {
+			hlExcerpt.Tokens = parseTokenRefs(tokens.String)
+		}
 		excerpts = append(excerpts, hlExcerpt)
 	}
 
-	return excerpts, rows.Err()
+	return excerpts, total, rows.Err()
+}
+
+func (s *SQLiteExcerptStore) Facets(ctx context.Context, scriptures []string, params SearchParams) ([]Facet, error) {
+	qb, err := s.buildSearchSQL(scriptures, params)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[FacetFilter]int)
+	if params.Mode == common.SearchCQL {
+		// CQL matches are only known after decoding the candidates
+		query, args := qb.resultsQuery()
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("sqlite facet search failed: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			var excerptJSON []byte
+			if err := rows.Scan(&excerptJSON); err != nil {
+				return nil, err
+			}
+			var excerpt Excerpt
+			if err := json.Unmarshal(excerptJSON, &excerpt); err != nil {
+				return nil, err
+			}
+			if len(params.cql.Match(&excerpt)) == 0 {
+				continue
+			}
+			for _, f := range excerptFacets(&excerpt) {
+				counts[f]++
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return buildFacets(counts), nil
+	}
+
+	query, args := qb.facetsQuery()
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite facet search failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var f FacetFilter
+		var n int
+		if err := rows.Scan(&f.Name, &f.Value, &n); err != nil {
+			return nil, err
+		}
+		counts[f] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return buildFacets(counts), nil
+}
+
+func (s *SQLiteExcerptStore) Stats(ctx context.Context, scripture string, scope []PathRange) (*ScopeStats, error) {
+	where := "ex.scripture = ?"
+	args := []any{scripture}
+	if cond, scopeArgs := scopeCondition(scope); cond != "" {
+		where += " AND " + cond
+		args = append(args, scopeArgs...)
+	}
+
+	stats := &ScopeStats{}
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM dhee_excerpts ex WHERE "+where, args...).Scan(&stats.Excerpts)
+	if err != nil {
+		return nil, err
+	}
+	err = s.db.QueryRowContext(ctx, `SELECT COUNT(*), COUNT(DISTINCT NULLIF(g.lemma, ''))
+		FROM dhee_glossings g JOIN dhee_excerpts ex ON ex.rowid = g.excerpt_rowid
+		WHERE `+where, args...).Scan(&stats.Words, &stats.Lemmas)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *SQLiteExcerptStore) ListFormulas(ctx context.Context, scripture string, page int) ([]Formula, int, error) {
+	var total int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM dhee_formulas WHERE scripture = ?", scripture).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	p := common.NewPagination(page)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, basis, text, length, occurrences FROM dhee_formulas
+		WHERE scripture = ?
+		ORDER BY occurrences DESC, length DESC, text
+		LIMIT ? OFFSET ?`, scripture, p.PageSize, p.Offset())
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var formulas []Formula
+	for rows.Next() {
+		var f Formula
+		if err := rows.Scan(&f.ID, &f.Basis, &f.Text, &f.Length, &f.Occurrences); err != nil {
+			return nil, 0, err
+		}
+		formulas = append(formulas, f)
+	}
+	return formulas, total, rows.Err()
+}
+
+func (s *SQLiteExcerptStore) GetFormula(ctx context.Context, scripture string, id string, page int) (*Formula, []HighlightedExcerpt, int, error) {
+	f := &Formula{ID: id}
+	err := s.db.QueryRowContext(ctx,
+		"SELECT basis, text, length, occurrences FROM dhee_formulas WHERE scripture = ? AND id = ?", scripture, id,
+	).Scan(&f.Basis, &f.Text, &f.Length, &f.Occurrences)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, 0, common.NewUserVisibleError(http.StatusNotFound, "formula not found")
+	}
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	const from = `FROM dhee_formula_occurrences o JOIN dhee_excerpts ex ON ex.rowid = o.excerpt_rowid
+		WHERE o.scripture = ? AND o.formula_id = ?`
+	var total int
+	err = s.db.QueryRowContext(ctx, "SELECT COUNT(DISTINCT o.excerpt_rowid) "+from, scripture, id).Scan(&total)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	p := common.NewPagination(page)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ex.e, group_concat(o.line || ':' || o.position) `+from+`
+		GROUP BY ex.rowid
+		ORDER BY ex.sort_index
+		LIMIT ? OFFSET ?`, scripture, id, p.PageSize, p.Offset())
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer rows.Close()
+
+	var results []HighlightedExcerpt
+	for rows.Next() {
+		var excerptJSON []byte
+		var starts string
+		if err := rows.Scan(&excerptJSON, &starts); err != nil {
+			return nil, nil, 0, err
+		}
+		var he HighlightedExcerpt
+		if err := json.Unmarshal(excerptJSON, &he.Excerpt); err != nil {
+			return nil, nil, 0, err
+		}
+		for _, start := range parseTokenRefs(starts) {
+			he.Tokens = append(he.Tokens, formulaTokens(&he.Excerpt, start, f.Length)...)
+		}
+		results = append(results, he)
+	}
+	return f, results, total, rows.Err()
+}
+
+// meterStats reads the statistics of the meters of a scripture, or of one meter if meter is
+// not empty. Verses without a meter label are skipped.
+func (s *SQLiteExcerptStore) meterStats(ctx context.Context, scripture string, meter string) ([]MeterStats, error) {
+	filter := "scripture = ? AND meter != ''"
+	args := []any{scripture}
+	if meter != "" {
+		filter += " AND meter = ?"
+		args = append(args, meter)
+	}
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT meter, COUNT(*), SUM(irregular), SUM(padas), SUM(irregular_padas) FROM dhee_scansions
+		WHERE `+filter+`
+		GROUP BY meter
+		ORDER BY COUNT(*) DESC, meter`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var stats []MeterStats
+	byMeter := make(map[string]int)
+	for rows.Next() {
+		var m MeterStats
+		if err := rows.Scan(&m.Meter, &m.Verses, &m.IrregularVerses, &m.Padas, &m.IrregularPadas); err != nil {
+			return nil, err
+		}
+		byMeter[m.Meter] = len(stats)
+		stats = append(stats, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.db.QueryContext(ctx, `
+		SELECT meter, syllables, COUNT(*), SUM(irregular) FROM dhee_padas
+		WHERE `+filter+`
+		GROUP BY meter, syllables
+		ORDER BY meter, syllables`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m string
+		var c SyllableCount
+		if err := rows.Scan(&m, &c.Syllables, &c.Padas, &c.Irregular); err != nil {
+			return nil, err
+		}
+		if i, ok := byMeter[m]; ok {
+			stats[i].SyllableCounts = append(stats[i].SyllableCounts, c)
+		}
+	}
+	return stats, rows.Err()
+}
+
+func (s *SQLiteExcerptStore) ListMeters(ctx context.Context, scripture string) ([]MeterStats, error) {
+	return s.meterStats(ctx, scripture, "")
+}
+
+func (s *SQLiteExcerptStore) GetMeter(ctx context.Context, scripture string, meter string, page int) (*MeterStats, []Excerpt, int, error) {
+	stats, err := s.meterStats(ctx, scripture, meter)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if len(stats) == 0 {
+		return nil, nil, 0, common.NewUserVisibleError(http.StatusNotFound, "meter not found")
+	}
+
+	p := common.NewPagination(page)
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ex.e FROM dhee_scansions sc JOIN dhee_excerpts ex ON ex.rowid = sc.excerpt_rowid
+		WHERE sc.scripture = ? AND sc.meter = ? AND sc.irregular
+		ORDER BY ex.sort_index
+		LIMIT ? OFFSET ?`, scripture, meter, p.PageSize, p.Offset())
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	defer rows.Close()
+
+	var results []Excerpt
+	for rows.Next() {
+		var excerptJSON []byte
+		if err := rows.Scan(&excerptJSON); err != nil {
+			return nil, nil, 0, err
+		}
+		var e Excerpt
+		if err := json.Unmarshal(excerptJSON, &e); err != nil {
+			return nil, nil, 0, err
+		}
+		results = append(results, e)
+	}
+	return &stats[0], results, stats[0].IrregularVerses, rows.Err()
+}
+
+func (s *SQLiteExcerptStore) AccentForms(ctx context.Context, scripture string, lemma string) ([]AccentForm, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT g.accented, g.accent, coalesce(g.nominal_case, ''), coalesce(g.number, ''),
+			coalesce(g.gender, ''), coalesce(g.tense, ''), coalesce(g.mood, ''),
+			coalesce(g.voice, ''), coalesce(g.person, ''), count(*) AS n
+		FROM dhee_glossings g JOIN dhee_excerpts ex ON ex.rowid = g.excerpt_rowid
+		WHERE ex.scripture = ? AND g.lemma = ? AND g.accented != ''
+		GROUP BY 1, 2, 3, 4, 5, 6, 7, 8, 9
+		ORDER BY n DESC, g.accented`, scripture, common.NormalizeLemma(lemma))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var forms []AccentForm
+	for rows.Next() {
+		var f AccentForm
+		if err := rows.Scan(&f.Surface, &f.Placement, &f.Case, &f.Number, &f.Gender, &f.Tense, &f.Mood, &f.Voice, &f.Person, &f.Count); err != nil {
+			return nil, err
+		}
+		forms = append(forms, f)
+	}
+	return forms, rows.Err()
+}
+
+func (s *SQLiteExcerptStore) ListAttributions(ctx context.Context, scripture string, kind AttributionKind) ([]AttributionCount, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT name, COUNT(DISTINCT hymn) AS hymns, COUNT(DISTINCT excerpt_rowid) FROM dhee_attributions
+		WHERE scripture = ? AND kind = ?
+		GROUP BY name
+		ORDER BY hymns DESC, name`, scripture, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []AttributionCount
+	for rows.Next() {
+		var c AttributionCount
+		if err := rows.Scan(&c.Name, &c.Hymns, &c.Verses); err != nil {
+			return nil, err
+		}
+		names = append(names, c)
+	}
+	return names, rows.Err()
+}
+
+func (s *SQLiteExcerptStore) GetAttribution(ctx context.Context, scripture string, kind AttributionKind, name string) ([]AttributedHymn, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT hymn, COUNT(DISTINCT excerpt_rowid) FROM dhee_attributions
+		WHERE scripture = ? AND kind = ? AND name = ?
+		GROUP BY hymn
+		ORDER BY hymn_sort`, scripture, kind, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hymns []AttributedHymn
+	byHymn := make(map[string]int)
+	for rows.Next() {
+		var h AttributedHymn
+		if err := rows.Scan(&h.Hymn, &h.Verses); err != nil {
+			return nil, err
+		}
+		byHymn[h.Hymn] = len(hymns)
+		hymns = append(hymns, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(hymns) == 0 {
+		return nil, common.NewUserVisibleError(http.StatusNotFound, "no hymns attributed to "+name)
+	}
+
+	// the names of all kinds in these hymns, each name once in the order of its first verse
+	rows, err = s.db.QueryContext(ctx, `
+		WITH totals AS (
+			SELECT hymn, COUNT(DISTINCT excerpt_rowid) AS total FROM dhee_attributions
+			WHERE scripture = ? AND hymn IN (
+				SELECT hymn FROM dhee_attributions WHERE scripture = ? AND kind = ? AND name = ?
+			)
+			GROUP BY hymn
+		)
+		SELECT a.hymn, a.kind, a.name, t.total
+		FROM dhee_attributions a JOIN totals t ON t.hymn = a.hymn
+		WHERE a.scripture = ?
+		GROUP BY a.hymn, a.kind, a.name
+		ORDER BY a.hymn, MIN(a.rowid)`, scripture, scripture, kind, name, scripture)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chandas := make(map[string][]string)
+	families := make(map[string][]string)
+	for rows.Next() {
+		var hymn, name string
+		var kind AttributionKind
+		var total int
+		if err := rows.Scan(&hymn, &kind, &name, &total); err != nil {
+			return nil, err
+		}
+		h := &hymns[byHymn[hymn]]
+		h.TotalVerses = total
+		switch kind {
+		case AttributionSeers:
+			h.Attribution.Rsis = append(h.Attribution.Rsis, name)
+		case AttributionDeities:
+			h.Attribution.Devatas = append(h.Attribution.Devatas, name)
+		case AttributionFamilies:
+			families[hymn] = append(families[hymn], name)
+		case AttributionChandas:
+			chandas[hymn] = append(chandas[hymn], name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i := range hymns {
+		h := &hymns[i]
+		h.Attribution.Chandas = strings.Join(chandas[h.Hymn], ", ")
+		h.Attribution.Family = strings.Join(families[h.Hymn], ", ")
+	}
+	return hymns, nil
 }
 
 func (s *SQLiteExcerptStore) GetHier(ctx context.Context, scripture *config.ScriptureDefn, path []int) (*Hierarchy, error) {
@@ -453,3 +1330,337 @@ func (s *SQLiteExcerptStore) GetHier(ctx context.Context, scripture *config.Scri
 		IsLeaf:    len(lineage)+1 == len(scripture.Hierarchy),
 	}, nil
 }
+
+// queryFtsColumns maps query language fields to the columns of dhee_excerpts_fts.
+var queryFtsColumns = map[QueryField]string{
+	FieldDefault:   "roman_t",
+	FieldAddressee: "addressees",
+	FieldAuthor:    "authors",
+	FieldMeter:     "meter",
+	FieldLemma:     "lemmas",
+	FieldSurface:   "surfaces",
+}
+
+// ftsTermExpr formats a query term as a quoted FTS5 string, so that no user input is
+// interpreted as FTS5 syntax.
+func ftsTermExpr(n *QueryNode) string {
+	text := n.Text
+	if n.Field == FieldLemma {
+		text = common.NormalizeLemma(text)
+	} else if n.Field.IsSanskrit() {
+		text = common.NormalizeSurface(text)
+	}
+	expr := `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+	if n.Prefix {
+		expr += " *"
+	}
+	return expr
+}
+
+// compileQueryNode compiles the parsed query into a SQL condition over `dhee_excerpts AS ex`,
+// appending the bind parameters to args. Each term is a separate FTS lookup, which lets
+// one query combine the excerpt and translation FTS tables freely.
+func compileQueryNode(n *QueryNode, args *[]any) string {
+	switch n.Op {
+	case OpAnd, OpOr:
+		op := " AND "
+		if n.Op == OpOr {
+			op = " OR "
+		}
+		parts := make([]string, len(n.Children))
+		for i, c := range n.Children {
+			parts[i] = compileQueryNode(c, args)
+		}
+		return "(" + strings.Join(parts, op) + ")"
+	case OpNot:
+		return "(NOT " + compileQueryNode(n.Children[0], args) + ")"
+	}
+
+	switch n.Field {
+	case FieldPath:
+		// path terms are validated at parse time
+		path, _ := common.StringToPath(n.Text)
+		sortPrefix := common.PathToSortString(path)
+		*args = append(*args, sortPrefix, sortPrefix+".%")
+		return "(ex.sort_index = ? OR ex.sort_index LIKE ?)"
+	case FieldTranslation:
+		*args = append(*args, "translation : "+ftsTermExpr(n))
+		return "ex.rowid IN (SELECT rowid FROM dhee_excerpts_translations_fts WHERE dhee_excerpts_translations_fts MATCH ?)"
+	default:
+		*args = append(*args, queryFtsColumns[n.Field]+" : "+ftsTermExpr(n))
+		return "ex.rowid IN (SELECT rowid FROM dhee_excerpts_fts WHERE dhee_excerpts_fts MATCH ?)"
+	}
+}
+
+// queryHighlightMatch returns an FTS5 expression matching any of the non-negated terms
+// of the given field, or "" if there are none.
+func queryHighlightMatch(n *QueryNode, field QueryField) string {
+	var exprs []string
+	for _, t := range n.Terms(true) {
+		if t.Field == field {
+			exprs = append(exprs, ftsTermExpr(t))
+		}
+	}
+	if len(exprs) == 0 {
+		return ""
+	}
+	column := "translation"
+	if field != FieldTranslation {
+		column = queryFtsColumns[field]
+	}
+	return column + " : (" + strings.Join(exprs, " OR ") + ")"
+}
+
+// morphColumns maps morphological features to the columns of dhee_glossings.
+var morphColumns = map[MorphFeature]string{
+	MorphLemma:    "lemma",
+	MorphSurface:  "surface",
+	MorphRoot:     "root",
+	MorphGramm:    "gramm",
+	MorphCase:     "nominal_case",
+	MorphNumber:   "number",
+	MorphGender:   "gender",
+	MorphTense:    "tense",
+	MorphVoice:    "voice",
+	MorphPerson:   "person",
+	MorphMood:     "mood",
+	MorphAccented: "accented",
+	MorphAccent:   "accent",
+}
+
+// glossingColumnFeatures lists the features in the column order of dhee_glossings.
+var glossingColumnFeatures = []MorphFeature{
+	MorphSurface, MorphLemma, MorphRoot, MorphGramm, MorphCase, MorphNumber,
+	MorphGender, MorphTense, MorphVoice, MorphPerson, MorphMood, MorphAccented, MorphAccent,
+}
+
+// compileMorphQuery compiles the parsed morphological query into a SQL condition over
+// `dhee_glossings AS g` joined with `dhee_excerpts AS ex`, appending the bind parameters to args.
+// All constraints apply to the same token.
+func compileMorphQuery(mq *MorphQuery, args *[]any) string {
+	conds := make([]string, 0, len(mq.Constraints))
+	for _, c := range mq.Constraints {
+		alternatives := make([]string, 0, len(c.Values))
+		for _, v := range c.Values {
+			switch {
+			case c.Feature == MorphPath:
+				// paths are validated at parse time
+				path, _ := common.StringToPath(v)
+				sortPrefix := common.PathToSortString(path)
+				*args = append(*args, sortPrefix, sortPrefix+".%")
+				alternatives = append(alternatives, "ex.sort_index = ? OR ex.sort_index LIKE ?")
+			case c.Feature.IsSanskrit():
+				switch c.Feature {
+				case MorphSurface:
+					v = common.NormalizeSurface(v)
+				case MorphAccented:
+					v = accentedSurface(v)
+				default:
+					v = common.NormalizeLemma(v)
+				}
+				if c.Prefix {
+					*args = append(*args, escapeLike(v)+"%")
+					alternatives = append(alternatives, "g."+morphColumns[c.Feature]+` LIKE ? ESCAPE '\'`)
+				} else {
+					*args = append(*args, v)
+					alternatives = append(alternatives, "g."+morphColumns[c.Feature]+" = ?")
+				}
+			default:
+				// tags are upper cased at parse time
+				*args = append(*args, v)
+				alternatives = append(alternatives, "upper(g."+morphColumns[c.Feature]+") = ?")
+			}
+		}
+		conds = append(conds, "("+strings.Join(alternatives, " OR ")+")")
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// compileAccentQuery compiles the words of an accent search into a SQL condition over
+// `dhee_glossings AS g` joined with `dhee_excerpts AS ex`, appending the bind parameters to
+// args. Every word must be a token of the excerpt, and g is any token matching one of them.
+// A word ending with `*` matches the tokens with that prefix.
+func compileAccentQuery(q string, args *[]any) string {
+	var conds, alternatives []string
+	var alternativeArgs []any
+	for _, w := range strings.Fields(q) {
+		cond, arg := "accented = ?", accentedSurface(w)
+		if strings.HasSuffix(w, "*") {
+			cond, arg = `accented LIKE ? ESCAPE '\'`, escapeLike(accentedSurface(strings.TrimSuffix(w, "*")))+"%"
+		}
+		conds = append(conds, "ex.rowid IN (SELECT excerpt_rowid FROM dhee_glossings WHERE "+cond+")")
+		*args = append(*args, arg)
+		alternatives = append(alternatives, "g."+cond)
+		alternativeArgs = append(alternativeArgs, arg)
+	}
+	if len(conds) == 0 {
+		return "0"
+	}
+	*args = append(*args, alternativeArgs...)
+	return strings.Join(conds, " AND ") + " AND (" + strings.Join(alternatives, " OR ") + ")"
+}
+
+// compileCQLPrefilter compiles a SQL condition over `dhee_excerpts AS ex` which selects the
+// excerpts having a token for every mandatory token pattern. It does not check the order
+// of tokens, which is left to CQLPattern.Match.
+func compileCQLPrefilter(p *CQLPattern, args *[]any) string {
+	var conds []string
+	for _, it := range p.Items {
+		if it.Cond == nil || it.Min == 0 {
+			continue
+		}
+		conds = append(conds, "ex.rowid IN (SELECT g.excerpt_rowid FROM dhee_glossings AS g WHERE "+compileCQLCond(it.Cond, args)+")")
+	}
+	return strings.Join(conds, " AND ")
+}
+
+func compileCQLCond(c *CQLCond, args *[]any) string {
+	switch c.Op {
+	case CQLAnd, CQLOr:
+		op := " AND "
+		if c.Op == CQLOr {
+			op = " OR "
+		}
+		parts := make([]string, len(c.Children))
+		for i, ch := range c.Children {
+			parts[i] = compileCQLCond(ch, args)
+		}
+		return "(" + strings.Join(parts, op) + ")"
+	case CQLNot:
+		return "(NOT " + compileCQLCond(c.Children[0], args) + ")"
+	}
+
+	var expr string
+	column := "g." + morphColumns[c.Feature]
+	if c.isLiteral() {
+		value := c.Value
+		if c.Feature == MorphAccented {
+			value = accentedSurface(value)
+		} else if c.Feature.IsSanskrit() {
+			value = common.FoldAccents(value)
+		} else {
+			value = strings.ToUpper(value)
+			column = "upper(" + column + ")"
+		}
+		*args = append(*args, value)
+		expr = column + " = ?"
+	} else {
+		*args = append(*args, c.regexSource())
+		expr = column + " REGEXP ?"
+	}
+	if c.Negated {
+		return "(NOT " + expr + ")"
+	}
+	return expr
+}
+
+// ExpandFuzzy finds the indexed words within the configured edit distance of each word of q.
+// Candidates sharing enough trigrams with a query word are fetched, and then verified by
+// their edit distance, see fuzzyDistance.
+func (s *SQLiteExcerptStore) ExpandFuzzy(ctx context.Context, q string) (*FuzzyQuery, error) {
+	words := fuzzyWords(q)
+	if len(words) == 0 {
+		return nil, common.NewUserVisibleError(http.StatusBadRequest, "fuzzy search needs at least one word")
+	}
+	if len(words) > maxFuzzyWords {
+		return nil, common.NewUserVisibleError(http.StatusBadRequest,
+			fmt.Sprintf("fuzzy search supports at most %d words", maxFuzzyWords))
+	}
+
+	type candidate struct {
+		id       int64
+		word     string
+		distance int
+	}
+	fq := &FuzzyQuery{matched: make(map[string]bool)}
+	for _, word := range words {
+		runes := []rune(word)
+		distance := common.MaxEditDistance(s.conf.Fuzziness, len(runes))
+		args := []any{len(runes) - distance, len(runes) + distance}
+		query := "SELECT id, word FROM dhee_fuzzy_words WHERE length BETWEEN ? AND ?"
+		tris := trigrams(word)
+		if shared := minSharedTrigrams(len(tris), distance); shared > 0 {
+			query = `SELECT w.id, w.word FROM dhee_fuzzy_trigrams t JOIN dhee_fuzzy_words w ON w.id = t.word_id
+				WHERE w.length BETWEEN ? AND ? AND t.trigram IN (?` + strings.Repeat(",?", len(tris)-1) + `)
+				GROUP BY w.id HAVING count(*) >= ?`
+			for _, t := range tris {
+				args = append(args, t)
+			}
+			args = append(args, shared)
+		}
+
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, fmt.Errorf("fuzzy word lookup failed: %w", err)
+		}
+		var candidates []candidate
+		for rows.Next() {
+			var c candidate
+			if err := rows.Scan(&c.id, &c.word); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if c.distance = fuzzyDistance(runes, []rune(c.word), 2*distance); c.distance <= 2*distance {
+				candidates = append(candidates, c)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].distance != candidates[j].distance {
+				return candidates[i].distance < candidates[j].distance
+			}
+			return candidates[i].word < candidates[j].word
+		})
+		if len(candidates) > maxFuzzyExpansions {
+			candidates = candidates[:maxFuzzyExpansions]
+		}
+		ids := make([]int64, 0, len(candidates))
+		for _, c := range candidates {
+			ids = append(ids, c.id)
+			fq.matched[c.word] = true
+			if c.distance == 0 {
+				fq.exact = append(fq.exact, c.id)
+			}
+		}
+		fq.expansions = append(fq.expansions, ids)
+	}
+	return fq, nil
+}
+
+// scopeCondition returns a condition on ex.sort_index matching any of the ranges,
+// or an empty string if there are no ranges.
+func scopeCondition(scope []PathRange) (string, []any) {
+	if len(scope) == 0 {
+		return "", nil
+	}
+	var conds []string
+	var args []any
+	for _, r := range scope {
+		lower, upper := r.sortIndexBounds()
+		conds = append(conds, "(ex.sort_index >= ? AND ex.sort_index < ?)")
+		args = append(args, lower, upper)
+	}
+	return "(" + strings.Join(conds, " OR ") + ")", args
+}
+
+// escapeLike escapes the LIKE wildcards in s, for use with ESCAPE '\'.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// parseTokenRefs parses the comma separated `line:position` list produced by group_concat.
+func parseTokenRefs(s string) []TokenRef {
+	var refs []TokenRef
+	for part := range strings.SplitSeq(s, ",") {
+		var ref TokenRef
+		if _, err := fmt.Sscanf(part, "%d:%d", &ref.Line, &ref.Position); err != nil {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
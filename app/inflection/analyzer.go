@@ -0,0 +1,273 @@
+package inflection
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// AnalysisSource tells how an analysis was found.
+type AnalysisSource string
+
+const (
+	// AnalysisAttested analyses are glossings of tokens with the same surface.
+	AnalysisAttested AnalysisSource = "attested"
+	// AnalysisGenerated analyses are forms generated from the stems and roots of dictionary
+	// headwords.
+	AnalysisGenerated AnalysisSource = "generated"
+)
+
+// Analysis is a lemma an inflected form can be made from, with the glossing tags of the form.
+type Analysis struct {
+	// Lemma is the SLP1 headword of the stem or root.
+	Lemma     string `json:"lemma"`
+	LemmaIAST string `json:"lemma_iast"`
+	Case      string `json:"case,omitempty"`
+	Number    string `json:"number,omitempty"`
+	Gender    string `json:"gender,omitempty"`
+	Tense     string `json:"tense,omitempty"`
+	Mood      string `json:"mood,omitempty"`
+	Voice     string `json:"voice,omitempty"`
+	Person    string `json:"person,omitempty"`
+	// Formation is the paradigm or the stem formation of generated forms, eg: a-stem or
+	// class 1.
+	Formation string         `json:"formation,omitempty"`
+	Vedic     bool           `json:"vedic,omitempty"`
+	Source    AnalysisSource `json:"source"`
+	// Count is the number of occurrences of attested analyses.
+	Count int `json:"count,omitempty"`
+}
+
+// key identifies the lemma and tags of an analysis.
+func (a Analysis) key() string {
+	return strings.Join([]string{a.Lemma, a.Case, a.Number, a.Gender, a.Tense, a.Mood, a.Voice, a.Person}, "/")
+}
+
+// Inflectable has the nominal stems and verb roots a dictionary gives for a headword.
+type Inflectable struct {
+	Stems []NominalStem
+	Roots []VerbRoot
+}
+
+// Lexicon finds the headwords among candidate stems and roots.
+type Lexicon interface {
+	// Inflectables returns the stems and roots of the SLP1 words which are headwords, keyed
+	// by headword.
+	Inflectables(ctx context.Context, words []string) (map[string]Inflectable, error)
+}
+
+var (
+	// nominalEndings are the endings of every paradigm, without the Vedic markers.
+	nominalEndings = func() []string {
+		seen := make(map[string]bool)
+		var all []string
+		for _, e := range []endings{
+			aMasc, aNeut, aFem, iMasc, iFem, iNeut, uMasc, uFem, uNeut, longIFem, longUFem,
+			fAgent, fRelation, fRelationFem, fAgentFem, inMasc, inNeut, vatMasc, vatNeut,
+			asNeut, asMascFem, isNeut, usNeut,
+			anEndings("M", "n"), anEndings("M", "an"), anEndings("N", "n"), anEndings("N", "an"),
+		} {
+			for _, row := range e {
+				for _, cell := range row {
+					for _, ending := range strings.Fields(cell) {
+						ending = strings.TrimSuffix(ending, "*")
+						if !seen[ending] {
+							seen[ending] = true
+							all = append(all, ending)
+						}
+					}
+				}
+			}
+		}
+		return all
+	}()
+	// stemSuffixes are the suffixes paradigms replace with their endings.
+	stemSuffixes = []string{"a", "A", "i", "I", "u", "U", "f", "an", "in", "at", "as", "is", "us"}
+)
+
+// stemCandidates returns the stems an SLP1 form may be declined from, by replacing each
+// ending it has with each stem suffix. The form is also tried with the retroflex and
+// palatal n of internal sandhi made dental.
+func stemCandidates(form string) []string {
+	var stems []string
+	variants := []string{form}
+	if dental := strings.ReplaceAll(strings.ReplaceAll(form, "R", "n"), "jY", "jn"); dental != form {
+		variants = append(variants, dental)
+	}
+	for _, v := range variants {
+		for _, ending := range nominalEndings {
+			base, ok := strings.CutSuffix(v, ending)
+			if !ok || base == "" {
+				continue
+			}
+			for _, suffix := range stemSuffixes {
+				stems = append(stems, base+suffix)
+			}
+		}
+	}
+	return stems
+}
+
+// weakenings are the strengthened vowels of roots, with the vowels they are made from.
+var weakenings = []struct{ strong, weak string }{
+	{"Ay", "i I"}, {"Av", "u U"}, {"ay", "i I"}, {"av", "u U"},
+	{"Ar", "f"}, {"ar", "f"}, {"e", "i I"}, {"o", "u U"}, {"E", "i I"}, {"O", "u U"},
+	{"A", "a"},
+}
+
+// weakened returns the forms of s with its guna or vrddhi vowel, or a lengthened a, in the
+// weak grade.
+func weakened(s string) []string {
+	var forms []string
+	for _, w := range weakenings {
+		i := strings.LastIndex(s, w.strong)
+		if i < 0 {
+			continue
+		}
+		for _, weak := range strings.Fields(w.weak) {
+			forms = append(forms, s[:i]+weak+s[i+len(w.strong):])
+		}
+	}
+	return forms
+}
+
+// rootCandidates returns the roots an SLP1 verb form may be conjugated from: the
+// monosyllabic parts of the form after an augment or a reduplication, in the weak grade as
+// well, and the roots of the irregular stems found in the form.
+func rootCandidates(form string) []string {
+	var roots []string
+	for i := 0; i <= 3 && i < len(form); i++ {
+		for j := i + 1; j <= len(form) && j-i <= 6; j++ {
+			s := form[i:j]
+			switch countVowels(s) {
+			case 1:
+				roots = append(roots, s)
+				roots = append(roots, weakened(s)...)
+			case 0:
+				// the weak perfect stem drops the vowel of the root, eg: cakr
+				switch s[len(s)-1] {
+				case 'r':
+					roots = append(roots, s[:len(s)-1]+"f")
+				case 'y':
+					roots = append(roots, s[:len(s)-1]+"i", s[:len(s)-1]+"I")
+				case 'v':
+					roots = append(roots, s[:len(s)-1]+"u", s[:len(s)-1]+"U")
+				}
+			}
+		}
+	}
+	contains := func(stem string) bool {
+		return stem != "" && strings.Contains(form, stem)
+	}
+	for key, stem := range irregularPresents {
+		if contains(strings.TrimSuffix(stem, "a")) {
+			root, _, _ := strings.Cut(key, " ")
+			roots = append(roots, root)
+		}
+	}
+	for root, p := range irregularPerfects {
+		if contains(p.weak) {
+			roots = append(roots, root)
+		}
+	}
+	for root, stem := range irregularAorists {
+		if contains(stem) {
+			roots = append(roots, root)
+		}
+	}
+	for root, stem := range irregularFutures {
+		if contains(strings.TrimSuffix(stem, "a")) {
+			roots = append(roots, root)
+		}
+	}
+	return roots
+}
+
+// declineStems returns the declension tables of nominal stems in the genders given by their
+// lexical genders, with the feminine stem of adjectives in the feminine.
+func declineStems(stems []NominalStem) []DeclensionTable {
+	var tables []DeclensionTable
+	seen := make(map[string]bool)
+	for _, ns := range stems {
+		genders, feminineStem := ParseGenders(ns.LexicalGender, ns.Stem)
+		for _, g := range genders {
+			stem := ns.Stem
+			if g == "F" {
+				stem = feminineStem
+			}
+			if seen[stem+"/"+g] {
+				continue
+			}
+			seen[stem+"/"+g] = true
+			if t, ok := Decline(stem, g); ok {
+				tables = append(tables, t)
+			}
+		}
+	}
+	return tables
+}
+
+// generatedAnalyses returns the analyses of an SLP1 form as a form of the declension and
+// conjugation tables of the headwords.
+func generatedAnalyses(form string, headwords map[string]Inflectable) []Analysis {
+	words := make([]string, 0, len(headwords))
+	for w := range headwords {
+		words = append(words, w)
+	}
+	slices.Sort(words)
+
+	var analyses []Analysis
+	for _, w := range words {
+		inf := headwords[w]
+		for _, t := range declineStems(inf.Stems) {
+			for _, row := range t.Rows {
+				for _, cell := range row.Cells {
+					for _, f := range cell.Forms {
+						if f.SLP1 == form {
+							analyses = append(analyses, Analysis{
+								Lemma: w, Case: row.Case, Number: cell.Number, Gender: t.Gender,
+								Formation: t.Class, Vedic: f.Vedic, Source: AnalysisGenerated,
+							})
+						}
+					}
+				}
+			}
+		}
+		seen := make(map[VerbRoot]bool)
+		for _, r := range inf.Roots {
+			// the forms of prefixed verbs have the preverb, which is not in the tables
+			if r.Prefix != "" || seen[r] {
+				continue
+			}
+			seen[r] = true
+			for _, t := range Conjugate(r.Root, r.Class, r.Pada) {
+				for _, row := range t.Rows {
+					for _, cell := range row.Cells {
+						for _, f := range cell.Forms {
+							if f.SLP1 == form {
+								analyses = append(analyses, Analysis{
+									Lemma: w, Tense: t.Tense, Mood: t.Mood, Voice: t.Voice, Person: row.Person,
+									Number: cell.Number, Formation: t.Formation, Vedic: f.Vedic, Source: AnalysisGenerated,
+								})
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return analyses
+}
+
+// candidates returns the distinct stems and roots an SLP1 form may be made from.
+func candidates(form string) []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, w := range append(stemCandidates(form), rootCandidates(form)...) {
+		if !seen[w] {
+			seen[w] = true
+			words = append(words, w)
+		}
+	}
+	return words
+}
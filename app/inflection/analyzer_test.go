@@ -0,0 +1,71 @@
+package inflection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testLexicon = map[string]Inflectable{
+	"deva":  {Stems: []NominalStem{{Stem: "deva", LexicalGender: "m."}}},
+	"priya": {Stems: []NominalStem{{Stem: "priya", LexicalGender: "mf(A)n."}}},
+	"rAjan": {Stems: []NominalStem{{Stem: "rAjan", LexicalGender: "m."}}},
+	"rAma":  {Stems: []NominalStem{{Stem: "rAma", LexicalGender: "m."}}},
+	"gam":   {Roots: []VerbRoot{{Root: "gam", Class: 1, Pada: "P"}}},
+	"kf":    {Roots: []VerbRoot{{Root: "kf", Class: 8}}},
+	"BU":    {Roots: []VerbRoot{{Root: "BU", Class: 1, Pada: "P"}}},
+	"nI":    {Roots: []VerbRoot{{Root: "nI", Class: 1}}},
+}
+
+// analyze returns the generated analyses of a form against testLexicon, as the service does
+// with a dictionary.
+func analyze(form string) []Analysis {
+	headwords := make(map[string]Inflectable)
+	for _, w := range candidates(form) {
+		if inf, ok := testLexicon[w]; ok {
+			headwords[w] = inf
+		}
+	}
+	return generatedAnalyses(form, headwords)
+}
+
+func TestAnalyzeNominal(t *testing.T) {
+	assert.Equal(t, []Analysis{
+		{Lemma: "deva", Case: "INS", Number: "SG", Gender: "M", Formation: "a-stem", Source: AnalysisGenerated},
+	}, analyze("devena"))
+	assert.Equal(t, []Analysis{
+		{Lemma: "rAma", Case: "INS", Number: "SG", Gender: "M", Formation: "a-stem", Source: AnalysisGenerated},
+	}, analyze("rAmeRa"))
+	assert.Equal(t, []Analysis{
+		{Lemma: "rAjan", Case: "INS", Number: "SG", Gender: "M", Formation: "an-stem", Source: AnalysisGenerated},
+	}, analyze("rAjYA"))
+	assert.Equal(t, []Analysis{
+		{Lemma: "priya", Case: "ACC", Number: "SG", Gender: "F", Formation: "A-stem", Source: AnalysisGenerated},
+	}, analyze("priyAm"))
+
+	nom := analyze("devAsaH")
+	if assert.Len(t, nom, 2) {
+		assert.Equal(t, "NOM", nom[0].Case)
+		assert.Equal(t, "VOC", nom[1].Case)
+		assert.True(t, nom[0].Vedic)
+	}
+	assert.Empty(t, analyze("xyz"))
+}
+
+func TestAnalyzeVerbal(t *testing.T) {
+	assert.Equal(t, []Analysis{
+		{Lemma: "gam", Tense: "PRS", Mood: "IND", Voice: "ACT", Person: "3", Number: "SG", Formation: "class 1", Source: AnalysisGenerated},
+	}, analyze("gacCati"))
+	assert.Equal(t, []Analysis{
+		{Lemma: "gam", Tense: "PRF", Mood: "IND", Voice: "ACT", Person: "3", Number: "PL", Formation: "reduplicated", Source: AnalysisGenerated},
+	}, analyze("jagmuH"))
+
+	for form, lemma := range map[string]string{
+		"cakruH": "kf", "cakAra": "kf", "aBavat": "BU", "baBUva": "BU", "anayata": "nI", "nezyati": "nI",
+	} {
+		as := analyze(form)
+		if assert.NotEmpty(t, as, form) {
+			assert.Equal(t, lemma, as[0].Lemma, form)
+		}
+	}
+}
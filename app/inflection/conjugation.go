@@ -0,0 +1,548 @@
+package inflection
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Persons are the rows of conjugation tables, as glossing tags.
+var Persons = []string{"3", "2", "1"}
+
+// verbEndings are the endings of a tense by person and number, in the order of Persons and
+// Numbers, written like endings.
+type verbEndings [3][3]string
+
+// Voices maps the padas of the dictionary to the voices of the glossings.
+var Voices = map[string]string{"P": "ACT", "A": "MED"}
+
+const (
+	shortVowels = "aiufx"
+	stops       = "kKgGcCjJwWqQtTdDpPbB"
+	// ruki are the letters after which s becomes z
+	ruki = "iIuUfFeEoOkr"
+)
+
+// Thematic endings, applied to a stem without its final a.
+var (
+	presentP = verbEndings{
+		{"ati", "ataH", "anti"},
+		{"asi", "aTaH", "aTa"},
+		{"Ami", "AvaH", "AmaH Amasi*"},
+	}
+	presentA = verbEndings{
+		{"ate", "ete", "ante"},
+		{"ase", "eTe", "aDve"},
+		{"e", "Avahe", "Amahe"},
+	}
+	imperfectP = verbEndings{
+		{"at", "atAm", "an"},
+		{"aH", "atam", "ata"},
+		{"am", "Ava", "Ama"},
+	}
+	imperfectA = verbEndings{
+		{"ata", "etAm", "anta"},
+		{"aTAH", "eTAm", "aDvam"},
+		{"e", "Avahi", "Amahi"},
+	}
+	optativeP = verbEndings{
+		{"et", "etAm", "eyuH"},
+		{"eH", "etam", "eta"},
+		{"eyam", "eva", "ema"},
+	}
+	optativeA = verbEndings{
+		{"eta", "eyAtAm", "eran"},
+		{"eTAH", "eyATAm", "eDvam"},
+		{"eya", "evahi", "emahi"},
+	}
+	imperativeP = verbEndings{
+		{"atu", "atAm", "antu"},
+		{"a atAt*", "atam", "ata atana*"},
+		{"Ani", "Ava", "Ama"},
+	}
+	imperativeA = verbEndings{
+		{"atAm", "etAm", "antAm"},
+		{"asva", "eTAm", "aDvam"},
+		{"E", "AvahE", "AmahE"},
+	}
+)
+
+// Perfect endings, the singular of the active is added to the strong stems.
+var (
+	perfectP = verbEndings{
+		{"a", "atuH", "uH"},
+		{"iTa", "aTuH", "a"},
+		{"a", "iva", "ima"},
+	}
+	perfectA = verbEndings{
+		{"e", "Ate", "ire re*"},
+		{"ize", "ATe", "iDve"},
+		{"e", "ivahe", "imahe"},
+	}
+)
+
+// Aorist endings, added to the augmented stem.
+var (
+	rootAoristP = verbEndings{
+		{"t", "tAm", "uH"},
+		{"H", "tam", "ta"},
+		{"m", "va", "ma"},
+	}
+	sAoristP = verbEndings{
+		{"sIt", "stAm", "suH"},
+		{"sIH", "stam", "sta"},
+		{"sam", "sva", "sma"},
+	}
+	sAoristA = verbEndings{
+		{"sta", "sAtAm", "sata"},
+		{"sTAH", "sATAm", "Dvam"},
+		{"si", "svahi", "smahi"},
+	}
+	izAoristP = verbEndings{
+		{"It", "izwAm", "izuH"},
+		{"IH", "izwam", "izwa"},
+		{"izam", "izva", "izma"},
+	}
+	izAoristA = verbEndings{
+		{"izwa", "izAtAm", "izata"},
+		{"izWAH", "izATAm", "iQvam"},
+		{"izi", "izvahi", "izmahi"},
+	}
+)
+
+// irregularPresents are the present stems which are not formed regularly from the root,
+// keyed by root and class.
+var irregularPresents = map[string]string{
+	"gam 1": "gacCa", "yam 1": "yacCa", "sTA 1": "tizWa", "pA 1": "piba", "GrA 1": "jiGra",
+	"dfS 1": "paSya", "sad 1": "sIda", "f 1": "fcCa",
+	"div 4": "dIvya", "jan 4": "jAya", "mad 4": "mAdya", "Sam 4": "SAmya", "Bram 4": "BrAmya",
+	"iz 6": "icCa", "praC 6": "pfcCa", "muc 6": "muYca", "sic 6": "siYca", "vid 6": "vinda",
+	"lup 6": "lumpa", "kft 6": "kfnta", "mf 6": "mriya",
+}
+
+// perfectStem is an irregularly reduplicated perfect. If uniform is true, weak is used in
+// the singular as well.
+type perfectStem struct {
+	redup   string
+	weak    string
+	uniform bool
+}
+
+var irregularPerfects = map[string]perfectStem{
+	"BU":   {redup: "ba", weak: "baBUv", uniform: true},
+	"gam":  {redup: "ja", weak: "jagm"},
+	"han":  {redup: "ja", weak: "jaGn"},
+	"jan":  {redup: "ja", weak: "jajY"},
+	"vac":  {redup: "u", weak: "Uc"},
+	"vad":  {redup: "u", weak: "Ud"},
+	"vas":  {redup: "u", weak: "Uz"},
+	"vah":  {redup: "u", weak: "Uh"},
+	"yaj":  {redup: "i", weak: "Ij"},
+	"svap": {redup: "su", weak: "suzup"},
+}
+
+// anitPerfects are the roots taking the perfect endings without a connecting i.
+var anitPerfects = map[string]bool{
+	"kf": true, "sf": true, "Bf": true, "vf": true, "stu": true, "dru": true, "sru": true, "Sru": true,
+}
+
+// irregularAorists are the roots with a thematic a-aorist, mapped to its stem, or to "" for
+// a root aorist.
+var irregularAorists = map[string]string{
+	"gam": "gam", "vac": "voc", "dfS": "darS", "ruh": "ruh", "Sak": "Sak", "sic": "sic",
+	"vid": "vid", "Ap": "Ap", "muc": "muc", "BU": "",
+}
+
+// irregularFutures are the future stems of roots whose final consonant combines with the s
+// of sya, or which take it without a connecting i.
+var irregularFutures = map[string]string{
+	"dfS": "drakzya", "praC": "prakzya", "vac": "vakzya", "sfj": "srakzya", "yaj": "yakzya",
+	"vah": "vakzya", "dah": "Dakzya", "Sru": "Srozya", "stu": "stozya", "muc": "mokzya",
+}
+
+// VerbRoot is a root of a dictionary entry with its present class and pada, which is P, A
+// or empty if unknown.
+type VerbRoot struct {
+	Root   string `json:"root"`
+	Prefix string `json:"prefix,omitempty"`
+	Class  int    `json:"class,omitempty"`
+	Pada   string `json:"pada,omitempty"`
+}
+
+func lastVowel(root string) int {
+	for i := len(root) - 1; i >= 0; i-- {
+		if isVowel(root[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+var (
+	gunaOf   = map[byte]string{'i': "e", 'I': "e", 'u': "o", 'U': "o", 'f': "ar", 'F': "ar", 'x': "al"}
+	vrddhiOf = map[byte]string{'a': "A", 'i': "E", 'I': "E", 'u': "O", 'U': "O", 'f': "Ar", 'F': "Ar", 'x': "Al"}
+)
+
+// strengthen returns the root with a final vowel in the grade of grade, or a short vowel
+// followed by one consonant in guna. A medial a in such a position is lengthened if
+// lengthenA is true.
+func strengthen(root string, grade map[byte]string, lengthenA bool) string {
+	i := lastVowel(root)
+	if i < 0 {
+		return root
+	}
+	v := root[i]
+	light := i == len(root)-2 && strings.IndexByte(shortVowels, v) >= 0
+	switch {
+	case i == len(root)-1:
+		if g, ok := grade[v]; ok {
+			return root[:i] + g
+		}
+	case light && v == 'a':
+		if lengthenA {
+			return root[:i] + "A" + root[i+1:]
+		}
+	case light:
+		if g, ok := gunaOf[v]; ok {
+			return root[:i] + g + root[i+1:]
+		}
+	}
+	return root
+}
+
+func guna(root string) string {
+	return strengthen(root, gunaOf, false)
+}
+
+func vrddhi(root string) string {
+	return strengthen(root, vrddhiOf, true)
+}
+
+// join appends a suffix to a stem with the sandhi at the junction: final vowels before a
+// vowel become semivowels or are dropped if a or A, and s becomes z after ruki letters,
+// making a following t or T retroflex.
+func join(stem, suffix string) string {
+	if stem == "" || suffix == "" {
+		return stem + suffix
+	}
+	n := len(stem)
+	last := stem[n-1]
+	switch {
+	case isVowel(suffix[0]):
+		switch last {
+		case 'a', 'A':
+			stem = stem[:n-1]
+		case 'e':
+			stem = stem[:n-1] + "ay"
+		case 'o':
+			stem = stem[:n-1] + "av"
+		case 'E':
+			stem = stem[:n-1] + "Ay"
+		case 'O':
+			stem = stem[:n-1] + "Av"
+		case 'f', 'F':
+			stem = stem[:n-1] + "r"
+		case 'u', 'U':
+			stem = stem[:n-1] + "uv"
+		case 'i', 'I':
+			// after a single consonant the vowel becomes y, eg: ninyuH but cikriyuH
+			if n >= 3 && !isVowel(stem[n-2]) && isVowel(stem[n-3]) {
+				stem = stem[:n-1] + "y"
+			} else {
+				stem = stem[:n-1] + "iy"
+			}
+		}
+	case suffix[0] == 's' && strings.IndexByte(ruki, last) >= 0:
+		suffix = "z" + suffix[1:]
+		if len(suffix) > 1 && suffix[1] == 't' {
+			suffix = "zw" + suffix[2:]
+		} else if len(suffix) > 1 && suffix[1] == 'T' {
+			suffix = "zW" + suffix[2:]
+		}
+	}
+	return stem + suffix
+}
+
+// augment returns a past stem with the augment a, which makes vrddhi with an initial vowel.
+func augment(stem string) string {
+	switch stem[0] {
+	case 'a', 'A':
+		return "A" + stem[1:]
+	case 'i', 'I', 'e':
+		return "E" + stem[1:]
+	case 'u', 'U', 'o':
+		return "O" + stem[1:]
+	case 'f', 'F':
+		return "Ar" + stem[1:]
+	}
+	return "a" + stem
+}
+
+// presentStem returns the thematic present stem of a root in a class. It returns false for
+// the athematic classes.
+func presentStem(root string, class int) (string, bool) {
+	if s, ok := irregularPresents[fmt.Sprintf("%s %d", root, class)]; ok {
+		return s, true
+	}
+	n := len(root)
+	switch class {
+	case 1:
+		return join(guna(root), "a"), true
+	case 4:
+		return root + "ya", true
+	case 6:
+		switch root[n-1] {
+		case 'i', 'I', 'f':
+			return root[:n-1] + "iya", true
+		case 'u', 'U':
+			return root[:n-1] + "uva", true
+		}
+		return root + "a", true
+	case 10:
+		return join(vrddhi(root), "aya"), true
+	}
+	return "", false
+}
+
+var reduplicatedConsonants = map[byte]byte{
+	'k': 'c', 'K': 'c', 'g': 'j', 'G': 'j', 'h': 'j',
+	'C': 'c', 'J': 'j', 'W': 'w', 'Q': 'q', 'T': 't', 'D': 'd', 'P': 'p', 'B': 'b',
+}
+
+// reduplication returns the reduplicating syllable of the perfect of a root beginning
+// with a consonant.
+func reduplication(root string) (string, bool) {
+	if p, ok := irregularPerfects[root]; ok {
+		return p.redup, true
+	}
+	v := strings.IndexAny(root, vowels)
+	if v <= 0 {
+		return "", false
+	}
+	c := root[0]
+	if v > 1 && strings.IndexByte("sSz", c) >= 0 && strings.IndexByte(stops, root[1]) >= 0 {
+		// the stop of an initial sibilant and stop is repeated, eg: tasTO
+		c = root[1]
+	}
+	if r, ok := reduplicatedConsonants[c]; ok {
+		c = r
+	}
+	switch root[v] {
+	case 'i', 'I', 'e', 'E':
+		return string(c) + "i", true
+	case 'u', 'U', 'o', 'O':
+		return string(c) + "u", true
+	}
+	return string(c) + "a", true
+}
+
+// weakPerfect returns the weak perfect stem of a root with its reduplication.
+func weakPerfect(root, redup string) string {
+	if p, ok := irregularPerfects[root]; ok {
+		return p.weak
+	}
+	// roots like pat, with a between single consonants which are kept in the reduplication,
+	// have weak stems like pet
+	if len(root) == 3 && root[1] == 'a' && !isVowel(root[0]) && !isVowel(root[2]) &&
+		redup[0] == root[0] && strings.IndexByte("vy", root[0]) < 0 {
+		return root[:1] + "e" + root[2:]
+	}
+	return redup + root
+}
+
+// Formation and tense descriptions of conjugation tables.
+type tenseDefn struct {
+	tense, mood, label string
+}
+
+var (
+	presentIndicative = tenseDefn{"PRS", "IND", "present"}
+	imperfect         = tenseDefn{"IPRF", "IND", "imperfect"}
+	optative          = tenseDefn{"PRS", "OPT", "optative"}
+	imperative        = tenseDefn{"PRS", "IMP", "imperative"}
+	perfect           = tenseDefn{"PRF", "IND", "perfect"}
+	aorist            = tenseDefn{"AOR", "IND", "aorist"}
+	future            = tenseDefn{"FUT", "IND", "future"}
+)
+
+// conjugate returns a table of forms made with stem, or stems if given by person and
+// number, and the endings.
+func conjugate(root string, td tenseDefn, pada, formation string, stem func(p, n int) string, e verbEndings) ConjugationTable {
+	t := ConjugationTable{
+		Root:      root,
+		Tense:     td.tense,
+		Mood:      td.mood,
+		Voice:     Voices[pada],
+		Label:     td.label,
+		Formation: formation,
+	}
+	for i, p := range Persons {
+		row := ConjugationRow{Person: p}
+		for j, n := range Numbers {
+			cell := FormCell{Number: n, Forms: []Form{}}
+			seen := make(map[string]bool)
+			for _, ending := range strings.Fields(e[i][j]) {
+				vedic := strings.HasSuffix(ending, "*")
+				form := internalSandhi(join(stem(i, j), strings.TrimSuffix(ending, "*")))
+				if seen[form] {
+					continue
+				}
+				seen[form] = true
+				cell.Forms = append(cell.Forms, Form{SLP1: form, Vedic: vedic})
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+func fixed(stem string) func(int, int) string {
+	return func(int, int) string { return stem }
+}
+
+// pick returns the P or A variant of endings.
+func pick(pada string, p, a verbEndings) verbEndings {
+	if pada == "A" {
+		return a
+	}
+	return p
+}
+
+func presentTables(root string, class int, pada string) []ConjugationTable {
+	stem, ok := presentStem(root, class)
+	if !ok {
+		return nil
+	}
+	formation := fmt.Sprintf("class %d", class)
+	base := strings.TrimSuffix(stem, "a")
+	return []ConjugationTable{
+		conjugate(root, presentIndicative, pada, formation, fixed(base), pick(pada, presentP, presentA)),
+		conjugate(root, imperfect, pada, formation, fixed(augment(base)), pick(pada, imperfectP, imperfectA)),
+		conjugate(root, optative, pada, formation, fixed(base), pick(pada, optativeP, optativeA)),
+		conjugate(root, imperative, pada, formation, fixed(base), pick(pada, imperativeP, imperativeA)),
+	}
+}
+
+func perfectTable(root, pada string) (ConjugationTable, bool) {
+	redup, ok := reduplication(root)
+	if !ok {
+		return ConjugationTable{}, false
+	}
+	weak := weakPerfect(root, redup)
+	e := pick(pada, perfectP, perfectA)
+	if anitPerfects[root] {
+		for i := 1; i < len(e); i++ {
+			for j := range e[i] {
+				if e[i][j] != "iTa" {
+					e[i][j] = strings.TrimPrefix(e[i][j], "i")
+				}
+			}
+		}
+	}
+	uniform := irregularPerfects[root].uniform
+	if isVowel(root[len(root)-1]) && !uniform {
+		e[1][0] = strings.Replace(e[1][0], "iTa", "Ta", 1)
+	}
+	aFinal := strings.HasSuffix(root, "A")
+	if aFinal && pada != "A" {
+		// dadO
+		e[0][0], e[2][0] = "O", "O"
+	}
+	stem := func(p, n int) string {
+		switch {
+		case pada == "A" || n > 0 || uniform:
+			return weak
+		case aFinal && p != 1:
+			return redup + root[:len(root)-1]
+		case p == 0:
+			return redup + vrddhi(root)
+		}
+		return redup + guna(root)
+	}
+	return conjugate(root, perfect, pada, "reduplicated", stem, e), true
+}
+
+func aoristTable(root, pada string) (ConjugationTable, bool) {
+	last := root[len(root)-1]
+	if stem, ok := irregularAorists[root]; ok && stem != "" {
+		base := augment(stem)
+		return conjugate(root, aorist, pada, "a-aorist", fixed(base), pick(pada, imperfectP, imperfectA)), true
+	} else if ok || last == 'A' {
+		if pada == "A" {
+			return ConjugationTable{}, false
+		}
+		e := rootAoristP
+		if last == 'U' {
+			// aBUvam, aBUvan
+			e[0][2], e[2][0] = "van", "vam"
+		}
+		return conjugate(root, aorist, pada, "root aorist", fixed(augment(root)), e), true
+	}
+	if !isVowel(last) {
+		return conjugate(root, aorist, pada, "iṣ-aorist", fixed(augment(guna(root))), pick(pada, izAoristP, izAoristA)), true
+	}
+	if pada != "A" {
+		return conjugate(root, aorist, pada, "s-aorist", fixed(augment(vrddhi(root))), sAoristP), true
+	}
+	stem := augment(guna(root))
+	e := sAoristA
+	if strings.IndexByte(shortVowels, last) >= 0 {
+		// s is lost between a short vowel and a dental, eg: akfta
+		stem = augment(root)
+		e[0][0], e[1][0] = "ta", "TAH"
+	}
+	if strings.IndexByte(ruki, stem[len(stem)-1]) >= 0 {
+		e[1][2] = "Qvam"
+	}
+	return conjugate(root, aorist, pada, "s-aorist", fixed(stem), e), true
+}
+
+// futureStem returns the sya future stem of a root. Roots ending in A, i, I and
+// diphthongs take sya directly, others with a connecting i.
+func futureStem(root string) string {
+	if s, ok := irregularFutures[root]; ok {
+		return s
+	}
+	g := guna(root)
+	if strings.IndexByte("AiIeEoO", root[len(root)-1]) >= 0 {
+		return join(g, "sya")
+	}
+	return join(join(g, "i"), "sya")
+}
+
+func futureTable(root, pada, stem string) ConjugationTable {
+	base := strings.TrimSuffix(stem, "a")
+	return conjugate(root, future, pada, "sya future", fixed(base), pick(pada, presentP, presentA))
+}
+
+// Conjugate returns the conjugation tables of an SLP1 root in a present class and pada,
+// for the present system of the thematic classes, the perfect, the aorist and the future.
+// If the pada is empty, both are conjugated.
+func Conjugate(root string, class int, pada string) []ConjugationTable {
+	if root == "" {
+		return nil
+	}
+	padas := []string{"P", "A"}
+	if pada != "" {
+		padas = []string{pada}
+	}
+	var tables []ConjugationTable
+	for _, p := range padas {
+		tables = append(tables, presentTables(root, class, p)...)
+		if class == 10 {
+			// the perfect is periphrastic and the aorist reduplicated, the future is made
+			// from the present stem
+			stem, _ := presentStem(root, class)
+			tables = append(tables, futureTable(root, p, strings.TrimSuffix(stem, "a")+"izya"))
+			continue
+		}
+		if t, ok := perfectTable(root, p); ok {
+			tables = append(tables, t)
+		}
+		if t, ok := aoristTable(root, p); ok {
+			tables = append(tables, t)
+		}
+		tables = append(tables, futureTable(root, p, futureStem(root)))
+	}
+	return tables
+}
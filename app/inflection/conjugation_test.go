@@ -0,0 +1,108 @@
+package inflection
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tableForms returns the SLP1 forms of a conjugation table by person and number, with
+// alternatives separated by /.
+func tableForms(t ConjugationTable) [][]string {
+	var rows [][]string
+	for _, row := range t.Rows {
+		var cells []string
+		for _, cell := range row.Cells {
+			var forms []string
+			for _, f := range cell.Forms {
+				forms = append(forms, f.SLP1)
+			}
+			cells = append(cells, strings.Join(forms, "/"))
+		}
+		rows = append(rows, cells)
+	}
+	return rows
+}
+
+func findTable(tables []ConjugationTable, tense, mood, voice string) (ConjugationTable, bool) {
+	for _, t := range tables {
+		if t.Tense == tense && t.Mood == mood && t.Voice == voice {
+			return t, true
+		}
+	}
+	return ConjugationTable{}, false
+}
+
+func TestConjugate(t *testing.T) {
+	for _, tc := range []struct {
+		root               string
+		class              int
+		pada               string
+		tense, mood, voice string
+		want               [][]string
+	}{
+		{"BU", 1, "P", "PRS", "IND", "ACT", [][]string{
+			{"Bavati", "BavataH", "Bavanti"},
+			{"Bavasi", "BavaTaH", "BavaTa"},
+			{"BavAmi", "BavAvaH", "BavAmaH/BavAmasi"},
+		}},
+		{"nI", 1, "A", "IPRF", "IND", "MED", [][]string{
+			{"anayata", "anayetAm", "anayanta"},
+			{"anayaTAH", "anayeTAm", "anayaDvam"},
+			{"anaye", "anayAvahi", "anayAmahi"},
+		}},
+		{"gam", 1, "P", "PRF", "IND", "ACT", [][]string{
+			{"jagAma", "jagmatuH", "jagmuH"},
+			{"jagamiTa", "jagmaTuH", "jagma"},
+			{"jagama", "jagmiva", "jagmima"},
+		}},
+		{"kf", 8, "P", "PRF", "IND", "ACT", [][]string{
+			{"cakAra", "cakratuH", "cakruH"},
+			{"cakarTa", "cakraTuH", "cakra"},
+			{"cakara", "cakfva", "cakfma"},
+		}},
+		{"dA", 3, "P", "AOR", "IND", "ACT", [][]string{
+			{"adAt", "adAtAm", "aduH"},
+			{"adAH", "adAtam", "adAta"},
+			{"adAm", "adAva", "adAma"},
+		}},
+		{"nI", 1, "P", "AOR", "IND", "ACT", [][]string{
+			{"anEzIt", "anEzwAm", "anEzuH"},
+			{"anEzIH", "anEzwam", "anEzwa"},
+			{"anEzam", "anEzva", "anEzma"},
+		}},
+		{"buD", 1, "P", "FUT", "IND", "ACT", [][]string{
+			{"boDizyati", "boDizyataH", "boDizyanti"},
+			{"boDizyasi", "boDizyaTaH", "boDizyaTa"},
+			{"boDizyAmi", "boDizyAvaH", "boDizyAmaH/boDizyAmasi"},
+		}},
+	} {
+		table, ok := findTable(Conjugate(tc.root, tc.class, tc.pada), tc.tense, tc.mood, tc.voice)
+		if assert.True(t, ok, "%s %s %s", tc.root, tc.tense, tc.voice) {
+			assert.Equal(t, tc.want, tableForms(table), "%s %s %s", tc.root, tc.tense, tc.voice)
+		}
+	}
+}
+
+func TestConjugateStems(t *testing.T) {
+	forms := func(root string, class int, tense, mood string) string {
+		table, _ := findTable(Conjugate(root, class, "P"), tense, mood, "ACT")
+		return table.Rows[0].Cells[0].Forms[0].SLP1
+	}
+	assert.Equal(t, "gacCati", forms("gam", 1, "PRS", "IND"))
+	assert.Equal(t, "tudet", forms("tud", 6, "PRS", "OPT"))
+	assert.Equal(t, "corayatu", forms("cur", 10, "PRS", "IMP"))
+	assert.Equal(t, "corayizyati", forms("cur", 10, "FUT", "IND"))
+	pat, _ := findTable(Conjugate("pat", 1, "P"), "PRF", "IND", "ACT")
+	assert.Equal(t, "petuH", pat.Rows[0].Cells[2].Forms[0].SLP1)
+	dA, _ := findTable(Conjugate("dA", 3, "P"), "PRF", "IND", "ACT")
+	assert.Equal(t, "dadO", dA.Rows[0].Cells[0].Forms[0].SLP1)
+
+	// athematic classes have no present system
+	_, ok := findTable(Conjugate("dA", 3, "P"), "PRS", "IND", "ACT")
+	assert.False(t, ok)
+	_, ok = findTable(Conjugate("cur", 10, "P"), "PRF", "IND", "ACT")
+	assert.False(t, ok)
+	assert.Len(t, Conjugate("BU", 1, ""), 13)
+}
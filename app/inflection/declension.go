@@ -0,0 +1,436 @@
+package inflection
+
+import (
+	"regexp"
+	"strings"
+)
+
+const vowels = "aAiIuUfFxXeEoO"
+
+// endings are the endings of a paradigm by case and number, in the order of Cases and
+// Numbers. Alternative endings are separated by spaces, Vedic endings are marked with a
+// trailing *.
+type endings [8][3]string
+
+// paradigm declines the stems ending with suffix, by replacing suffix with the endings.
+type paradigm struct {
+	class   string
+	suffix  string
+	endings endings
+}
+
+// withRows returns a copy of e with some rows replaced, for paradigms differing only in the
+// direct cases.
+func (e endings) withRows(rows map[string][3]string) endings {
+	for i, c := range Cases {
+		if r, ok := rows[c]; ok {
+			e[i] = r
+		}
+	}
+	return e
+}
+
+var (
+	aMasc = endings{
+		{"aH", "O A*", "AH AsaH*"},
+		{"am", "O A*", "An"},
+		{"ena A*", "AByAm", "EH eBiH*"},
+		{"Aya", "AByAm", "eByaH"},
+		{"At", "AByAm", "eByaH"},
+		{"asya", "ayoH", "AnAm"},
+		{"e", "ayoH", "ezu"},
+		{"a", "O A*", "AH AsaH*"},
+	}
+	aNeut = aMasc.withRows(map[string][3]string{
+		"NOM": {"am", "e", "Ani A*"},
+		"ACC": {"am", "e", "Ani A*"},
+		"VOC": {"a", "e", "Ani A*"},
+	})
+	aFem = endings{
+		{"A", "e", "AH"},
+		{"Am", "e", "AH"},
+		{"ayA A*", "AByAm", "ABiH"},
+		{"AyE", "AByAm", "AByaH"},
+		{"AyAH", "AByAm", "AByaH"},
+		{"AyAH", "ayoH", "AnAm"},
+		{"AyAm", "ayoH", "Asu"},
+		{"e", "e", "AH"},
+	}
+	iMasc = endings{
+		{"iH", "I", "ayaH"},
+		{"im", "I", "In"},
+		{"inA", "iByAm", "iBiH"},
+		{"aye", "iByAm", "iByaH"},
+		{"eH", "iByAm", "iByaH"},
+		{"eH", "yoH", "InAm"},
+		{"O A*", "yoH", "izu"},
+		{"e", "I", "ayaH"},
+	}
+	iFem = iMasc.withRows(map[string][3]string{
+		"ACC": {"im", "I", "IH"},
+		"INS": {"yA I*", "iByAm", "iBiH"},
+		"DAT": {"aye yE", "iByAm", "iByaH"},
+		"ABL": {"eH yAH", "iByAm", "iByaH"},
+		"GEN": {"eH yAH", "yoH", "InAm"},
+		"LOC": {"O yAm A*", "yoH", "izu"},
+	})
+	iNeut = endings{
+		{"i", "inI", "Ini I*"},
+		{"i", "inI", "Ini I*"},
+		{"inA", "iByAm", "iBiH"},
+		{"ine", "iByAm", "iByaH"},
+		{"inaH", "iByAm", "iByaH"},
+		{"inaH", "inoH", "InAm"},
+		{"ini", "inoH", "izu"},
+		{"i e", "inI", "Ini I*"},
+	}
+	uMasc = endings{
+		{"uH", "U", "avaH"},
+		{"um", "U", "Un"},
+		{"unA vA*", "uByAm", "uBiH"},
+		{"ave", "uByAm", "uByaH"},
+		{"oH", "uByAm", "uByaH"},
+		{"oH", "voH", "UnAm"},
+		{"O", "voH", "uzu"},
+		{"o", "U", "avaH"},
+	}
+	uFem = uMasc.withRows(map[string][3]string{
+		"ACC": {"um", "U", "UH"},
+		"INS": {"vA", "uByAm", "uBiH"},
+		"DAT": {"ave vE", "uByAm", "uByaH"},
+		"ABL": {"oH vAH", "uByAm", "uByaH"},
+		"GEN": {"oH vAH", "voH", "UnAm"},
+		"LOC": {"O vAm", "voH", "uzu"},
+	})
+	uNeut = endings{
+		{"u", "unI", "Uni U*"},
+		{"u", "unI", "Uni U*"},
+		{"unA vA*", "uByAm", "uBiH"},
+		{"une", "uByAm", "uByaH"},
+		{"unaH vaH*", "uByAm", "uByaH"},
+		{"unaH vaH*", "unoH", "UnAm"},
+		{"uni", "unoH", "uzu"},
+		{"u o", "unI", "Uni U*"},
+	}
+	longIFem = endings{
+		{"I", "yO I*", "yaH IH*"},
+		{"Im", "yO I*", "IH"},
+		{"yA", "IByAm", "IBiH"},
+		{"yE", "IByAm", "IByaH"},
+		{"yAH", "IByAm", "IByaH"},
+		{"yAH", "yoH", "InAm"},
+		{"yAm", "yoH", "Izu"},
+		{"i", "yO I*", "yaH IH*"},
+	}
+	longUFem = endings{
+		{"UH", "vO", "vaH"},
+		{"Um", "vO", "UH"},
+		{"vA", "UByAm", "UBiH"},
+		{"vE", "UByAm", "UByaH"},
+		{"vAH", "UByAm", "UByaH"},
+		{"vAH", "voH", "UnAm"},
+		{"vAm", "voH", "Uzu"},
+		{"u", "vO", "vaH"},
+	}
+	// agent nouns like dAtf have a strong stem in Ar
+	fAgent = endings{
+		{"A", "ArO ArA*", "AraH"},
+		{"Aram", "ArO ArA*", "Fn"},
+		{"rA", "fByAm", "fBiH"},
+		{"re", "fByAm", "fByaH"},
+		{"uH", "fByAm", "fByaH"},
+		{"uH", "roH", "FnAm"},
+		{"ari", "roH", "fzu"},
+		{"aH", "ArO ArA*", "AraH"},
+	}
+	// nouns of relationship like pitf have a strong stem in ar
+	fRelation = fAgent.withRows(map[string][3]string{
+		"NOM": {"A", "arO arA*", "araH"},
+		"ACC": {"aram", "arO arA*", "Fn"},
+		"VOC": {"aH", "arO arA*", "araH"},
+	})
+	fRelationFem = fRelation.withRows(map[string][3]string{
+		"ACC": {"aram", "arO arA*", "FH"},
+	})
+	fAgentFem = fAgent.withRows(map[string][3]string{
+		"ACC": {"Aram", "ArO ArA*", "FH"},
+	})
+	inMasc = endings{
+		{"I", "inO", "inaH"},
+		{"inam", "inO", "inaH"},
+		{"inA", "iByAm", "iBiH"},
+		{"ine", "iByAm", "iByaH"},
+		{"inaH", "iByAm", "iByaH"},
+		{"inaH", "inoH", "inAm"},
+		{"ini", "inoH", "izu"},
+		{"in", "inO", "inaH"},
+	}
+	inNeut = inMasc.withRows(map[string][3]string{
+		"NOM": {"i", "inI", "Ini"},
+		"ACC": {"i", "inI", "Ini"},
+		"VOC": {"i in", "inI", "Ini"},
+	})
+	// possessives in vat and mat, the suffix is at
+	vatMasc = endings{
+		{"An", "antO antA*", "antaH"},
+		{"antam", "antO antA*", "ataH"},
+		{"atA", "adByAm", "adBiH"},
+		{"ate", "adByAm", "adByaH"},
+		{"ataH", "adByAm", "adByaH"},
+		{"ataH", "atoH", "atAm"},
+		{"ati", "atoH", "atsu"},
+		{"an aH*", "antO antA*", "antaH"},
+	}
+	vatNeut = vatMasc.withRows(map[string][3]string{
+		"NOM": {"at", "atI", "anti"},
+		"ACC": {"at", "atI", "anti"},
+		"VOC": {"at", "atI", "anti"},
+	})
+	asNeut = endings{
+		{"aH", "asI", "AMsi"},
+		{"aH", "asI", "AMsi"},
+		{"asA", "oByAm", "oBiH"},
+		{"ase", "oByAm", "oByaH"},
+		{"asaH", "oByAm", "oByaH"},
+		{"asaH", "asoH", "asAm"},
+		{"asi", "asoH", "aHsu"},
+		{"aH", "asI", "AMsi"},
+	}
+	asMascFem = asNeut.withRows(map[string][3]string{
+		"NOM": {"AH", "asO asA*", "asaH"},
+		"ACC": {"asam", "asO asA*", "asaH"},
+		"VOC": {"aH", "asO asA*", "asaH"},
+	})
+	isNeut = endings{
+		{"iH", "izI", "IMzi"},
+		{"iH", "izI", "IMzi"},
+		{"izA", "irByAm", "irBiH"},
+		{"ize", "irByAm", "irByaH"},
+		{"izaH", "irByAm", "irByaH"},
+		{"izaH", "izoH", "izAm"},
+		{"izi", "izoH", "iHzu"},
+		{"iH", "izI", "IMzi"},
+	}
+	usNeut = endings{
+		{"uH", "uzI", "UMzi"},
+		{"uH", "uzI", "UMzi"},
+		{"uzA", "urByAm", "urBiH"},
+		{"uze", "urByAm", "urByaH"},
+		{"uzaH", "urByAm", "urByaH"},
+		{"uzaH", "uzoH", "uzAm"},
+		{"uzi", "uzoH", "uHzu"},
+		{"uH", "uzI", "UMzi"},
+	}
+)
+
+// anEndings returns the endings of stems in an, whose weak stem ends with weak. The weak
+// stem keeps the a after a consonant cluster ending in m or v, eg: Atman.
+func anEndings(gender, weak string) endings {
+	e := endings{
+		{"A", "AnO AnA*", "AnaH"},
+		{"Anam", "AnO AnA*", weak + "aH"},
+		{weak + "A", "aByAm", "aBiH"},
+		{weak + "e", "aByAm", "aByaH"},
+		{weak + "aH", "aByAm", "aByaH"},
+		{weak + "aH", weak + "oH", weak + "Am"},
+		{weak + "i an*", weak + "oH", "asu"},
+		{"an", "AnO AnA*", "AnaH"},
+	}
+	if weak == "n" {
+		e[6][0] = "ni ani an*"
+	}
+	if gender == "N" {
+		e = e.withRows(map[string][3]string{
+			"NOM": {"a", weak + "I anI", "Ani A*"},
+			"ACC": {"a", weak + "I anI", "Ani A*"},
+			"VOC": {"a an", weak + "I anI", "Ani A*"},
+		})
+	}
+	return e
+}
+
+// relationNouns are the f stems declined with a strong stem in ar.
+var relationNouns = map[string]bool{
+	"pitf": true, "mAtf": true, "BrAtf": true, "duhitf": true, "jAmAtf": true,
+	"devf": true, "yAtf": true, "nanAndf": true, "nf": true,
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte(vowels, c) >= 0
+}
+
+func countVowels(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if isVowel(s[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+// paradigmFor returns the paradigm of an SLP1 stem in a gender.
+func paradigmFor(stem, gender string) (paradigm, bool) {
+	has := strings.HasSuffix
+	switch {
+	case (has(stem, "vat") || has(stem, "mat")) && gender != "F":
+		if gender == "N" {
+			return paradigm{"vat-stem", "at", vatNeut}, true
+		}
+		return paradigm{"vat-stem", "at", vatMasc}, true
+	case has(stem, "an") && len(stem) > 3 && gender != "F":
+		base := stem[:len(stem)-2]
+		weak := "n"
+		if n := len(base); n >= 2 && strings.IndexByte("mv", base[n-1]) >= 0 && !isVowel(base[n-2]) {
+			weak = "an"
+		}
+		return paradigm{"an-stem", "an", anEndings(gender, weak)}, true
+	case has(stem, "in") && len(stem) > 3 && gender != "F":
+		if gender == "N" {
+			return paradigm{"in-stem", "in", inNeut}, true
+		}
+		return paradigm{"in-stem", "in", inMasc}, true
+	case has(stem, "as") && len(stem) > 3:
+		if gender == "N" {
+			return paradigm{"as-stem", "as", asNeut}, true
+		}
+		return paradigm{"as-stem", "as", asMascFem}, true
+	case has(stem, "is") && gender == "N":
+		return paradigm{"is-stem", "is", isNeut}, true
+	case has(stem, "us") && gender == "N":
+		return paradigm{"us-stem", "us", usNeut}, true
+	}
+	if countVowels(stem) < 2 && !relationNouns[stem] {
+		// monosyllables like DI have their own paradigms
+		return paradigm{}, false
+	}
+	switch stem[len(stem)-1:] + gender {
+	case "aM":
+		return paradigm{"a-stem", "a", aMasc}, true
+	case "aN":
+		return paradigm{"a-stem", "a", aNeut}, true
+	case "AF":
+		return paradigm{"A-stem", "A", aFem}, true
+	case "iM":
+		return paradigm{"i-stem", "i", iMasc}, true
+	case "iF":
+		return paradigm{"i-stem", "i", iFem}, true
+	case "iN":
+		return paradigm{"i-stem", "i", iNeut}, true
+	case "uM":
+		return paradigm{"u-stem", "u", uMasc}, true
+	case "uF":
+		return paradigm{"u-stem", "u", uFem}, true
+	case "uN":
+		return paradigm{"u-stem", "u", uNeut}, true
+	case "IF":
+		return paradigm{"I-stem", "I", longIFem}, true
+	case "UF":
+		return paradigm{"U-stem", "U", longUFem}, true
+	case "fM":
+		if relationNouns[stem] {
+			return paradigm{"f-stem", "f", fRelation}, true
+		}
+		return paradigm{"f-stem", "f", fAgent}, true
+	case "fF":
+		if relationNouns[stem] {
+			return paradigm{"f-stem", "f", fRelationFem}, true
+		}
+		return paradigm{"f-stem", "f", fAgentFem}, true
+	}
+	return paradigm{}, false
+}
+
+// Decline returns the declension table of an SLP1 stem in a gender, which is one of M, F
+// or N. It returns false for stems of an unsupported class.
+func Decline(stem, gender string) (DeclensionTable, bool) {
+	p, ok := paradigmFor(stem, gender)
+	if !ok {
+		return DeclensionTable{}, false
+	}
+	base := strings.TrimSuffix(stem, p.suffix)
+	t := DeclensionTable{Stem: stem, Gender: gender, Class: p.class}
+	for i, c := range Cases {
+		row := DeclensionRow{Case: c}
+		for j, n := range Numbers {
+			cell := FormCell{Number: n, Forms: []Form{}}
+			seen := make(map[string]bool)
+			for _, ending := range strings.Fields(p.endings[i][j]) {
+				vedic := strings.HasSuffix(ending, "*")
+				form := internalSandhi(base + strings.TrimSuffix(ending, "*"))
+				if seen[form] {
+					continue
+				}
+				seen[form] = true
+				cell.Forms = append(cell.Forms, Form{SLP1: form, Vedic: vedic})
+			}
+			row.Cells = append(row.Cells, cell)
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t, true
+}
+
+// internalSandhi applies the sandhi rules between a stem and its endings: jn becomes jY,
+// and n becomes R after r, f, F or z unless a palatal, retroflex or dental intervenes.
+func internalSandhi(word string) string {
+	b := []byte(strings.ReplaceAll(word, "jn", "jY"))
+	retroflex := false
+	for i, c := range b {
+		switch {
+		case strings.IndexByte("rfFz", c) >= 0:
+			retroflex = true
+		case c == 'n':
+			if retroflex && i+1 < len(b) && (isVowel(b[i+1]) || strings.IndexByte("nmyv", b[i+1]) >= 0) {
+				b[i] = 'R'
+			}
+			retroflex = false
+		case strings.IndexByte("cCjJYwWqQRtTdDlSs", c) >= 0:
+			retroflex = false
+		}
+	}
+	return string(b)
+}
+
+var (
+	parenthesized = regexp.MustCompile(`\([^)]*\)`)
+	femStem       = regexp.MustCompile(`f\.?\s*\(([^)]*)\)`)
+)
+
+// ParseGenders returns the genders (M, F or N) of a lexical gender like m., mfn. or
+// mf(I)n., and the feminine stem of an adjective with a masculine stem. It returns no
+// genders for other grammatical categories like ind.
+func ParseGenders(lexicalGender string, stem string) (genders []string, feminineStem string) {
+	s := parenthesized.ReplaceAllString(strings.ToLower(lexicalGender), "")
+	s = strings.NewReplacer(".", "", " ", "", ",", "").Replace(s)
+	if s == "" || strings.Trim(s, "mfn") != "" {
+		return nil, ""
+	}
+	for _, g := range []string{"m", "f", "n"} {
+		if strings.Contains(s, g) {
+			genders = append(genders, strings.ToUpper(g))
+		}
+	}
+	feminineStem = stem
+	if !strings.Contains(s, "m") || len(genders) == 1 {
+		return genders, feminineStem
+	}
+	// adjectives, which decline the masculine stem as a feminine one
+	marker := ""
+	if m := femStem.FindStringSubmatch(lexicalGender); m != nil {
+		marker = m[1]
+	}
+	longI := strings.ContainsAny(marker, "Iī")
+	has := strings.HasSuffix
+	switch {
+	case has(stem, "a") && longI:
+		feminineStem = stem[:len(stem)-1] + "I"
+	case has(stem, "a"):
+		feminineStem = stem[:len(stem)-1] + "A"
+	case has(stem, "vat"), has(stem, "mat"), has(stem, "in"):
+		feminineStem = stem + "I"
+	case has(stem, "f"):
+		feminineStem = stem[:len(stem)-1] + "rI"
+	}
+	return genders, feminineStem
+}
@@ -0,0 +1,83 @@
+package inflection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cellForms returns the SLP1 forms of a case and number of a table.
+func cellForms(t DeclensionTable, c, n string) []string {
+	for _, row := range t.Rows {
+		if row.Case != c {
+			continue
+		}
+		for _, cell := range row.Cells {
+			if cell.Number == n {
+				var forms []string
+				for _, f := range cell.Forms {
+					forms = append(forms, f.SLP1)
+				}
+				return forms
+			}
+		}
+	}
+	return nil
+}
+
+func TestDecline(t *testing.T) {
+	for _, tc := range []struct {
+		stem, gender, c, n string
+		want               []string
+	}{
+		{"deva", "M", "NOM", "SG", []string{"devaH"}},
+		{"deva", "M", "NOM", "PL", []string{"devAH", "devAsaH"}},
+		{"deva", "M", "INS", "PL", []string{"devEH", "deveBiH"}},
+		{"rAma", "M", "INS", "SG", []string{"rAmeRa", "rAmA"}},
+		{"yajYa", "N", "NOM", "PL", []string{"yajYAni", "yajYA"}},
+		{"agni", "M", "DAT", "SG", []string{"agnaye"}},
+		{"senA", "F", "INS", "SG", []string{"senayA", "senA"}},
+		{"devI", "F", "NOM", "PL", []string{"devyaH", "devIH"}},
+		{"pitf", "M", "ACC", "SG", []string{"pitaram"}},
+		{"dAtf", "M", "NOM", "DU", []string{"dAtArO", "dAtArA"}},
+		{"rAjan", "M", "INS", "SG", []string{"rAjYA"}},
+		{"Atman", "M", "INS", "SG", []string{"AtmanA"}},
+		{"manas", "N", "INS", "PL", []string{"manoBiH"}},
+		{"Dana", "N", "GEN", "PL", []string{"DanAnAm"}},
+	} {
+		table, ok := Decline(tc.stem, tc.gender)
+		if !assert.True(t, ok, tc.stem) {
+			continue
+		}
+		assert.Equal(t, tc.want, cellForms(table, tc.c, tc.n), "%s %s %s %s", tc.stem, tc.gender, tc.c, tc.n)
+	}
+
+	_, ok := Decline("DI", "F")
+	assert.False(t, ok)
+}
+
+func TestDeclineVedic(t *testing.T) {
+	table, _ := Decline("deva", "M")
+	forms := table.Rows[0].Cells[2].Forms
+	assert.Equal(t, []Form{{SLP1: "devAH"}, {SLP1: "devAsaH", Vedic: true}}, forms)
+}
+
+func TestParseGenders(t *testing.T) {
+	for _, tc := range []struct {
+		lexicalGender, stem string
+		genders             []string
+		feminineStem        string
+	}{
+		{"m.", "deva", []string{"M"}, "deva"},
+		{"mf(A)n.", "priya", []string{"M", "F", "N"}, "priyA"},
+		{"mf(I)n.", "gOra", []string{"M", "F", "N"}, "gOrI"},
+		{"mfn.", "Dana", []string{"M", "F", "N"}, "DanA"},
+		{"mfn.", "balin", []string{"M", "F", "N"}, "balinI"},
+		{"f.", "senA", []string{"F"}, "senA"},
+		{"ind.", "ca", nil, ""},
+	} {
+		genders, feminineStem := ParseGenders(tc.lexicalGender, tc.stem)
+		assert.Equal(t, tc.genders, genders, tc.lexicalGender)
+		assert.Equal(t, tc.feminineStem, feminineStem, tc.lexicalGender)
+	}
+}
@@ -0,0 +1,32 @@
+package inflection
+
+import "context"
+
+// AttestedForm counts the glossed tokens of a lemma with the same surface and tags.
+type AttestedForm struct {
+	Scripture string
+	// Lemma is the normalized IAST lemma, only read by SurfaceForms.
+	Lemma string
+	// Surface is in IAST with accents folded.
+	Surface string
+	Case    string
+	Number  string
+	Gender  string
+	Tense   string
+	Mood    string
+	Voice   string
+	Person  string
+	Count   int
+}
+
+// FormStore reads the inflected forms attested in the glossings of the scriptures.
+type FormStore interface {
+	// LemmaForms returns the attested forms of the tokens glossed with a normalized IAST lemma.
+	LemmaForms(ctx context.Context, lemma string) ([]AttestedForm, error)
+	// RootForms returns the attested finite forms of the tokens glossed with a normalized IAST
+	// root.
+	RootForms(ctx context.Context, root string) ([]AttestedForm, error)
+	// SurfaceForms returns the lemmas and tags of the tokens with an IAST surface, with
+	// accents folded, in all scriptures.
+	SurfaceForms(ctx context.Context, surface string) ([]AttestedForm, error)
+}
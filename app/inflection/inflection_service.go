@@ -0,0 +1,237 @@
+package inflection
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/mahesh-hegde/dhee/app/sandhi"
+	"github.com/mahesh-hegde/dhee/app/transliteration"
+)
+
+type InflectionService struct {
+	store          FormStore
+	conf           *config.DheeConfig
+	transliterator *transliteration.Transliterator
+}
+
+func NewInflectionService(store FormStore, conf *config.DheeConfig, transliterator *transliteration.Transliterator) *InflectionService {
+	return &InflectionService{
+		store:          store,
+		conf:           conf,
+		transliterator: transliterator,
+	}
+}
+
+// attestation is an attested form with its surface in SLP1 pausa form.
+type attestation struct {
+	AttestedForm
+	slp1 string
+}
+
+// tagsMatch reports whether the form can be glossed with the case, number and gender. Empty
+// tags match anything.
+func (a *attestation) tagsMatch(c, n, g string) bool {
+	return (a.Case == "" || a.Case == c) && (a.Number == "" || a.Number == n) && (a.Gender == "" || a.Gender == g)
+}
+
+func (s *InflectionService) toIAST(slp1 string) string {
+	iast, err := s.transliterator.Convert(slp1, common.TlSLP1, common.TlIAST)
+	if err != nil {
+		return slp1
+	}
+	return iast
+}
+
+// attestations converts the surfaces of attested forms to SLP1 pausa forms.
+func (s *InflectionService) attestations(forms []AttestedForm) []attestation {
+	as := make([]attestation, 0, len(forms))
+	for _, f := range forms {
+		slp1, err := s.transliterator.Convert(f.Surface, common.TlIAST, common.TlSLP1)
+		if err != nil {
+			slog.Debug("could not transliterate surface to slp1", "surface", f.Surface)
+			continue
+		}
+		as = append(as, attestation{AttestedForm: f, slp1: sandhi.PausaForm(slp1)})
+	}
+	return as
+}
+
+// Declensions returns the declension tables of the nominal stems of an SLP1 headword, in
+// every gender of each stem, with the forms attested in the glossings of the headword.
+func (s *InflectionService) Declensions(ctx context.Context, word string, stems []NominalStem) ([]DeclensionTable, error) {
+	tables := declineStems(stems)
+	if len(tables) == 0 {
+		return nil, nil
+	}
+
+	lemma := common.FoldAccents(s.toIAST(word))
+	forms, err := s.store.LemmaForms(ctx, lemma)
+	if err != nil {
+		return nil, err
+	}
+	as := s.attestations(forms)
+	for i := range tables {
+		s.markAttested(&tables[i], lemma, as)
+	}
+	return tables, nil
+}
+
+// markAttested fills in the IAST forms of a table and marks the attested ones.
+func (s *InflectionService) markAttested(t *DeclensionTable, lemma string, as []attestation) {
+	t.StemIAST = s.toIAST(t.Stem)
+	t.Lemma = lemma
+	for _, a := range as {
+		if (a.Gender == "" || a.Gender == t.Gender) && !slices.Contains(t.Scriptures, a.Scripture) {
+			t.Scriptures = append(t.Scriptures, a.Scripture)
+		}
+	}
+	for i := range t.Rows {
+		row := &t.Rows[i]
+		for j := range row.Cells {
+			cell := &row.Cells[j]
+			s.markCell(cell, as, func(a *attestation) (bool, bool) {
+				matches := a.tagsMatch(row.Case, cell.Number, t.Gender)
+				return matches, matches && a.Case == row.Case && a.Number == cell.Number
+			})
+		}
+	}
+}
+
+// markCell fills in the IAST forms of a cell and counts the attestations of each form whose
+// tags match the cell. Attestations having exactly the tags of the cell, whose surfaces are
+// not among the generated forms, are listed as other attested forms.
+func (s *InflectionService) markCell(cell *FormCell, as []attestation, match func(a *attestation) (matches, exact bool)) {
+	generated := make(map[string]bool)
+	for k := range cell.Forms {
+		f := &cell.Forms[k]
+		f.IAST = s.toIAST(f.SLP1)
+		generated[f.SLP1] = true
+		for i := range as {
+			if matches, _ := match(&as[i]); matches && as[i].slp1 == f.SLP1 {
+				f.Attested += as[i].Count
+			}
+		}
+	}
+	others := make(map[string]int)
+	for i := range as {
+		a := &as[i]
+		if _, exact := match(a); exact && !generated[a.slp1] {
+			if _, ok := others[a.slp1]; !ok {
+				cell.OtherAttested = append(cell.OtherAttested, Form{SLP1: a.slp1, IAST: a.Surface})
+			}
+			others[a.slp1] += a.Count
+		}
+	}
+	for k := range cell.OtherAttested {
+		cell.OtherAttested[k].Attested = others[cell.OtherAttested[k].SLP1]
+	}
+}
+
+// verbTagsMatch reports whether the form can be glossed with the tags of a conjugation table
+// and a person and number. Empty tags match anything.
+func (a *attestation) verbTagsMatch(t *ConjugationTable, person, number string) bool {
+	eq := func(tag, want string) bool { return tag == "" || tag == want }
+	return eq(a.Tense, t.Tense) && eq(a.Mood, t.Mood) && eq(a.Voice, t.Voice) && eq(a.Person, person) && eq(a.Number, number)
+}
+
+// Conjugations returns the conjugation tables of verb roots, with the forms attested in the
+// glossings of each root.
+func (s *InflectionService) Conjugations(ctx context.Context, roots []VerbRoot) ([]ConjugationTable, error) {
+	var tables []ConjugationTable
+	seen := make(map[VerbRoot]bool)
+	attested := make(map[string][]attestation)
+	for _, r := range roots {
+		if seen[r] {
+			continue
+		}
+		seen[r] = true
+		ts := Conjugate(r.Root, r.Class, r.Pada)
+		if len(ts) == 0 {
+			continue
+		}
+		root := common.NormalizeLemma(s.toIAST(r.Root))
+		as, ok := attested[root]
+		if !ok {
+			forms, err := s.store.RootForms(ctx, root)
+			if err != nil {
+				return nil, err
+			}
+			as = s.attestations(forms)
+			attested[root] = as
+		}
+		for i := range ts {
+			ts[i].RootIAST = root
+			if r.Prefix != "" {
+				ts[i].Prefix = s.toIAST(r.Prefix)
+			}
+			s.markConjugation(&ts[i], as)
+		}
+		tables = append(tables, ts...)
+	}
+	return tables, nil
+}
+
+// markConjugation fills in the IAST forms of a table and marks the attested ones.
+func (s *InflectionService) markConjugation(t *ConjugationTable, as []attestation) {
+	for _, a := range as {
+		if a.Tense == t.Tense && a.Voice == t.Voice && !slices.Contains(t.Scriptures, a.Scripture) {
+			t.Scriptures = append(t.Scriptures, a.Scripture)
+		}
+	}
+	for i := range t.Rows {
+		row := &t.Rows[i]
+		for j := range row.Cells {
+			cell := &row.Cells[j]
+			s.markCell(cell, as, func(a *attestation) (bool, bool) {
+				matches := a.verbTagsMatch(t, row.Person, cell.Number)
+				return matches, matches && a.Tense == t.Tense && a.Mood == t.Mood && a.Voice == t.Voice &&
+					a.Person == row.Person && a.Number == cell.Number
+			})
+		}
+	}
+}
+
+// Analyze returns the lemmas and tags an SLP1 form can have: first the glossings of the
+// tokens with the same surface, most frequent first, then the forms it matches in the
+// declension and conjugation tables of the headwords of lex it may be made from.
+func (s *InflectionService) Analyze(ctx context.Context, form string, lex Lexicon) ([]Analysis, error) {
+	form = sandhi.PausaForm(form)
+	forms, err := s.store.SurfaceForms(ctx, common.NormalizeSurface(s.toIAST(form)))
+	if err != nil {
+		return nil, err
+	}
+	analyses := []Analysis{}
+	seen := make(map[string]bool)
+	for _, f := range forms {
+		lemma := common.NormalizeLemma(f.Lemma)
+		slp1, err := s.transliterator.Convert(lemma, common.TlIAST, common.TlSLP1)
+		if err != nil {
+			slog.Debug("could not transliterate lemma to slp1", "lemma", lemma)
+			continue
+		}
+		a := Analysis{
+			Lemma: slp1, LemmaIAST: lemma, Case: f.Case, Number: f.Number, Gender: f.Gender,
+			Tense: f.Tense, Mood: f.Mood, Voice: f.Voice, Person: f.Person,
+			Source: AnalysisAttested, Count: f.Count,
+		}
+		seen[a.key()] = true
+		analyses = append(analyses, a)
+	}
+
+	headwords, err := lex.Inflectables(ctx, candidates(form))
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range generatedAnalyses(form, headwords) {
+		if seen[a.key()] {
+			continue
+		}
+		seen[a.key()] = true
+		a.LemmaIAST = s.toIAST(a.Lemma)
+		analyses = append(analyses, a)
+	}
+	return analyses, nil
+}
@@ -0,0 +1,79 @@
+package inflection
+
+// Cases and Numbers are the rows and columns of declension tables, as glossing tags.
+var (
+	Cases   = []string{"NOM", "ACC", "INS", "DAT", "ABL", "GEN", "LOC", "VOC"}
+	Numbers = []string{"SG", "DU", "PL"}
+)
+
+// NominalStem is a nominal stem of a dictionary entry, with the lexical gender given by the
+// dictionary, eg: m. or mf(A)n.
+type NominalStem struct {
+	Stem          string `json:"stem"`
+	LexicalGender string `json:"lexical_gender"`
+}
+
+// Form is a generated inflected form.
+type Form struct {
+	SLP1 string `json:"slp1"`
+	IAST string `json:"iast"`
+	// Vedic is true for forms with endings only found in the Vedic language.
+	Vedic bool `json:"vedic,omitempty"`
+	// Attested is the number of occurrences of the form in the loaded scriptures.
+	Attested int `json:"attested,omitempty"`
+}
+
+// FormCell has the forms of a number in a row of a declension or conjugation table.
+type FormCell struct {
+	Number string `json:"number"`
+	Forms  []Form `json:"forms"`
+	// OtherAttested are attested surfaces glossed with this case and number, which are not
+	// among the generated forms.
+	OtherAttested []Form `json:"other_attested,omitempty"`
+}
+
+type DeclensionRow struct {
+	Case  string     `json:"case"`
+	Cells []FormCell `json:"cells"`
+}
+
+// DeclensionTable is the paradigm of a nominal stem in one gender.
+type DeclensionTable struct {
+	// Stem is the SLP1 stem, which is the feminine stem for feminine adjectives.
+	Stem     string `json:"stem"`
+	StemIAST string `json:"stem_iast"`
+	// Lemma is the normalized IAST lemma the attested forms are glossed with.
+	Lemma  string `json:"lemma"`
+	Gender string `json:"gender"`
+	// Class describes the paradigm, eg: a-stem.
+	Class string          `json:"class"`
+	Rows  []DeclensionRow `json:"rows"`
+	// Scriptures has the scriptures in which forms of the lemma are attested.
+	Scriptures []string `json:"scriptures,omitempty"`
+}
+
+// ConjugationRow has the forms of a person.
+type ConjugationRow struct {
+	Person string     `json:"person"`
+	Cells  []FormCell `json:"cells"`
+}
+
+// ConjugationTable has the forms of a root in a tense or mood and a voice.
+type ConjugationTable struct {
+	// Root is the SLP1 root.
+	Root     string `json:"root"`
+	RootIAST string `json:"root_iast"`
+	// Prefix is the IAST preverb of a prefixed verb, which is not part of the forms.
+	Prefix string `json:"prefix,omitempty"`
+	// Tense, Mood and Voice are glossing tags, eg: PRS, OPT and MED.
+	Tense string `json:"tense"`
+	Mood  string `json:"mood"`
+	Voice string `json:"voice"`
+	// Label names the tense or mood, eg: imperfect.
+	Label string `json:"label"`
+	// Formation describes how the stem is made, eg: class 1 or s-aorist.
+	Formation string           `json:"formation"`
+	Rows      []ConjugationRow `json:"rows"`
+	// Scriptures has the scriptures in which forms of the root are attested.
+	Scriptures []string `json:"scriptures,omitempty"`
+}
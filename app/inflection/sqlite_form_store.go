@@ -0,0 +1,89 @@
+package inflection
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mahesh-hegde/dhee/app/config"
+)
+
+// SQLiteFormStore reads the glossings index maintained by the excerpts store.
+type SQLiteFormStore struct {
+	db   *sql.DB
+	conf *config.DheeConfig
+}
+
+func NewSQLiteFormStore(db *sql.DB, conf *config.DheeConfig) *SQLiteFormStore {
+	return &SQLiteFormStore{db: db, conf: conf}
+}
+
+var _ FormStore = &SQLiteFormStore{}
+
+func (s *SQLiteFormStore) LemmaForms(ctx context.Context, lemma string) ([]AttestedForm, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ex.scripture, g.surface, COALESCE(g.nominal_case, ''), COALESCE(g.number, ''),
+			COALESCE(g.gender, ''), COUNT(*)
+		FROM dhee_glossings AS g JOIN dhee_excerpts AS ex ON ex.rowid = g.excerpt_rowid
+		WHERE g.lemma = ? AND g.surface != ''
+		GROUP BY 1, 2, 3, 4, 5`, lemma)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attested forms: %w", err)
+	}
+	defer rows.Close()
+	var forms []AttestedForm
+	for rows.Next() {
+		var f AttestedForm
+		if err := rows.Scan(&f.Scripture, &f.Surface, &f.Case, &f.Number, &f.Gender, &f.Count); err != nil {
+			return nil, err
+		}
+		forms = append(forms, f)
+	}
+	return forms, rows.Err()
+}
+
+func (s *SQLiteFormStore) RootForms(ctx context.Context, root string) ([]AttestedForm, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT ex.scripture, g.surface, COALESCE(g.tense, ''), COALESCE(g.mood, ''),
+			COALESCE(g.voice, ''), g.person, COALESCE(g.number, ''), COUNT(*)
+		FROM dhee_glossings AS g JOIN dhee_excerpts AS ex ON ex.rowid = g.excerpt_rowid
+		WHERE g.root = ? AND g.surface != '' AND g.person != ''
+		GROUP BY 1, 2, 3, 4, 5, 6, 7`, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attested forms: %w", err)
+	}
+	defer rows.Close()
+	var forms []AttestedForm
+	for rows.Next() {
+		var f AttestedForm
+		if err := rows.Scan(&f.Scripture, &f.Surface, &f.Tense, &f.Mood, &f.Voice, &f.Person, &f.Number, &f.Count); err != nil {
+			return nil, err
+		}
+		forms = append(forms, f)
+	}
+	return forms, rows.Err()
+}
+
+func (s *SQLiteFormStore) SurfaceForms(ctx context.Context, surface string) ([]AttestedForm, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT g.lemma, COALESCE(g.nominal_case, ''), COALESCE(g.number, ''), COALESCE(g.gender, ''),
+			COALESCE(g.tense, ''), COALESCE(g.mood, ''), COALESCE(g.voice, ''), COALESCE(g.person, ''),
+			COUNT(*)
+		FROM dhee_glossings AS g
+		WHERE g.surface = ? AND g.lemma != ''
+		GROUP BY 1, 2, 3, 4, 5, 6, 7, 8
+		ORDER BY 9 DESC`, surface)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read glossings of surface: %w", err)
+	}
+	defer rows.Close()
+	var forms []AttestedForm
+	for rows.Next() {
+		f := AttestedForm{Surface: surface}
+		if err := rows.Scan(&f.Lemma, &f.Case, &f.Number, &f.Gender, &f.Tense, &f.Mood, &f.Voice, &f.Person, &f.Count); err != nil {
+			return nil, err
+		}
+		forms = append(forms, f)
+	}
+	return forms, rows.Err()
+}
@@ -0,0 +1,77 @@
+package prosody
+
+// Accent is the Vedic accent of a syllable, as marked in IAST.
+type Accent string
+
+const (
+	Unaccented Accent = ""
+	// Udatta is the raised pitch, marked with an acute.
+	Udatta Accent = "udatta"
+	// Svarita is the independent svarita, marked with a grave.
+	Svarita Accent = "svarita"
+)
+
+// Placement is the syllable bearing the accent of a word, counted from its end.
+type Placement string
+
+const (
+	PlacementUnaccented      Placement = "unaccented"
+	PlacementFinal           Placement = "final"
+	PlacementPenultimate     Placement = "penultimate"
+	PlacementAntepenultimate Placement = "antepenultimate"
+	// PlacementInitial is the first syllable of a word of four or more syllables.
+	PlacementInitial Placement = "initial"
+	PlacementMedial  Placement = "medial"
+)
+
+// Placements lists the placements from the end of the word to its beginning.
+var Placements = []Placement{
+	PlacementFinal, PlacementPenultimate, PlacementAntepenultimate, PlacementMedial,
+	PlacementInitial, PlacementUnaccented,
+}
+
+// WordAccent is the accentuation of a word.
+type WordAccent struct {
+	Syllables int `json:"syllables"`
+	// Position is the 1-based syllable bearing the first accent, or 0 if the word is unaccented.
+	Position int    `json:"position"`
+	Accent   Accent `json:"accent,omitempty"`
+	// Accents counts the accented syllables, which is more than one in some compounds.
+	Accents int `json:"accents"`
+}
+
+// AccentOf finds the accented syllables of an IAST word.
+func AccentOf(word string) WordAccent {
+	syllables := Syllabify(word)
+	wa := WordAccent{Syllables: len(syllables)}
+	for i, s := range syllables {
+		if s.Accent == Unaccented {
+			continue
+		}
+		wa.Accents++
+		if wa.Position == 0 {
+			wa.Position = i + 1
+			wa.Accent = s.Accent
+		}
+	}
+	return wa
+}
+
+// Placement returns where the first accent of the word is.
+func (a WordAccent) Placement() Placement {
+	if a.Position == 0 {
+		return PlacementUnaccented
+	}
+	switch a.Syllables - a.Position {
+	case 0:
+		return PlacementFinal
+	case 1:
+		return PlacementPenultimate
+	case 2:
+		return PlacementAntepenultimate
+	}
+	if a.Position == 1 {
+		return PlacementInitial
+	}
+	return PlacementMedial
+}
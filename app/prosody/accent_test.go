@@ -0,0 +1,36 @@
+package prosody
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccentOf(t *testing.T) {
+	for _, tc := range []struct {
+		word      string
+		position  int
+		accent    Accent
+		placement Placement
+	}{
+		{"agním", 2, Udatta, PlacementFinal},
+		// as converted from SLP1 agni/m
+		{"agni\u0301m", 2, Udatta, PlacementFinal},
+		{"ágne", 1, Udatta, PlacementPenultimate},
+		{"puróhitam", 2, Udatta, PlacementAntepenultimate},
+		{"vīryā̀ṇi", 2, Svarita, PlacementPenultimate},
+		{"ŕ̥ṣibhiḥ", 1, Udatta, PlacementAntepenultimate},
+		{"vaíśvānara", 1, Udatta, PlacementInitial},
+		{"bhavati", 0, Unaccented, PlacementUnaccented},
+	} {
+		a := AccentOf(tc.word)
+		assert.Equal(t, tc.position, a.Position, tc.word)
+		assert.Equal(t, tc.accent, a.Accent, tc.word)
+		assert.Equal(t, tc.placement, a.Placement(), tc.word)
+	}
+
+	a := AccentOf("bṛ́haspátiḥ")
+	assert.Equal(t, 4, a.Syllables)
+	assert.Equal(t, 2, a.Accents)
+	assert.Equal(t, PlacementInitial, a.Placement())
+}
@@ -0,0 +1,58 @@
+package prosody
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Meter is a meter with a fixed number of padas and syllables per pada.
+type Meter struct {
+	Name string `json:"name"`
+	// Padas has the number of syllables of each pada.
+	Padas []int `json:"padas"`
+}
+
+// meters are the regular meters, keyed by their names folded to lowercase ASCII letters.
+var meters = map[string]Meter{
+	"gayatri":    {"Gāyatrī", []int{8, 8, 8}},
+	"anustubh":   {"Anuṣṭubh", []int{8, 8, 8, 8}},
+	"usnih":      {"Uṣṇih", []int{8, 8, 12}},
+	"kakubh":     {"Kakubh", []int{8, 12, 8}},
+	"brhati":     {"Bṛhatī", []int{8, 8, 12, 8}},
+	"satobrhati": {"Satobṛhatī", []int{12, 8, 12, 8}},
+	"pankti":     {"Paṅkti", []int{8, 8, 8, 8, 8}},
+	"tristubh":   {"Triṣṭubh", []int{11, 11, 11, 11}},
+	"jagati":     {"Jagatī", []int{12, 12, 12, 12}},
+}
+
+// cadences are the quantities of the last syllables of a pada by its length, - for heavy, u
+// for light and x for either. Only the syllables which rarely vary are fixed.
+var cadences = map[int]string{
+	8:  "x-ux",
+	11: "-u-x",
+	12: "-u-ux",
+}
+
+var asciiFold = strings.NewReplacer(
+	"ā", "a", "ī", "i", "ū", "u", "ṛ", "r", "ṝ", "r", "ṣ", "s", "ś", "s", "ṭ", "t", "ḍ", "d",
+	"ṇ", "n", "ṅ", "n", "ñ", "n", "ṃ", "m", "ṁ", "m", "ḥ", "h", "r̥", "r",
+)
+
+// meterKey folds a meter label to lowercase ASCII letters, eg: Trishtubh with diacritics to
+// tristubh.
+func meterKey(label string) string {
+	s := asciiFold.Replace(strings.ToLower(label))
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && unicode.IsLetter(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// LookupMeter returns the meter of a label like those of the Rigveda dedications, if it is
+// one of the regular meters.
+func LookupMeter(label string) (Meter, bool) {
+	m, ok := meters[meterKey(label)]
+	return m, ok
+}
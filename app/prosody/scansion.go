@@ -0,0 +1,103 @@
+package prosody
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pada is a scanned line of a verse.
+type Pada struct {
+	Text      string   `json:"text"`
+	Syllables []string `json:"syllables"`
+	// Pattern has the quantity of each syllable, - for heavy and u for light.
+	Pattern string `json:"pattern"`
+	// Expected is the number of syllables of the pada in the meter, or 0 if unknown.
+	Expected int `json:"expected,omitempty"`
+	// Cadence is the expected quantities of the last syllables, see Pattern. x matches
+	// either quantity.
+	Cadence string `json:"cadence,omitempty"`
+	// Issues describe how the pada deviates from the meter.
+	Issues []string `json:"issues,omitempty"`
+	// Resolutions counts the y and v after a consonant of a pada missing syllables, which
+	// may be read as iy and uv to restore them.
+	Resolutions int `json:"resolutions,omitempty"`
+}
+
+// Scansion is the metrical analysis of a verse.
+type Scansion struct {
+	// Meter is the meter label of the verse.
+	Meter string `json:"meter,omitempty"`
+	// Checked is true if the label names a regular meter the padas were compared with.
+	Checked bool   `json:"checked"`
+	Padas   []Pada `json:"padas"`
+	// Issues describe deviations of the whole verse, eg: a missing pada.
+	Issues []string `json:"issues,omitempty"`
+}
+
+// IrregularPadas counts the padas deviating from the meter.
+func (s *Scansion) IrregularPadas() int {
+	n := 0
+	for _, p := range s.Padas {
+		if len(p.Issues) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// Irregular reports whether the verse deviates from its meter.
+func (s *Scansion) Irregular() bool {
+	return len(s.Issues) > 0 || s.IrregularPadas() > 0
+}
+
+// matchesCadence reports whether the end of a pattern has the quantities of a cadence.
+func matchesCadence(pattern, cadence string) bool {
+	if len(pattern) < len(cadence) {
+		return false
+	}
+	tail := pattern[len(pattern)-len(cadence):]
+	for i := range cadence {
+		if cadence[i] != 'x' && cadence[i] != tail[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Scan syllabifies the IAST lines of a verse, taking each line as a pada, and compares them
+// with the meter named by label if it is a regular meter. The last syllable of a pada is
+// free, so only the syllable count and the cadence are checked.
+func Scan(lines []string, label string) Scansion {
+	s := Scansion{Meter: label}
+	m, ok := LookupMeter(label)
+	s.Checked = ok
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		syllables := Syllabify(line)
+		p := Pada{Text: line, Pattern: Pattern(syllables), Syllables: make([]string, len(syllables))}
+		for i, syl := range syllables {
+			p.Syllables[i] = syl.Text
+		}
+		if ok && len(s.Padas) < len(m.Padas) {
+			p.Expected = m.Padas[len(s.Padas)]
+			p.Cadence = cadences[p.Expected]
+			n := len(syllables)
+			switch {
+			case n != p.Expected:
+				p.Issues = append(p.Issues, fmt.Sprintf("%d syllables, expected %d", n, p.Expected))
+				if n < p.Expected {
+					p.Resolutions = resolutions(line)
+				}
+			case !matchesCadence(p.Pattern, p.Cadence):
+				p.Issues = append(p.Issues, fmt.Sprintf("cadence %s, expected %s", p.Pattern[n-len(p.Cadence):], p.Cadence))
+			}
+		}
+		s.Padas = append(s.Padas, p)
+	}
+	if ok && len(s.Padas) != len(m.Padas) {
+		s.Issues = append(s.Issues, fmt.Sprintf("%d padas, expected %d", len(s.Padas), len(m.Padas)))
+	}
+	return s
+}
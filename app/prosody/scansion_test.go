@@ -0,0 +1,72 @@
+package prosody
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyllabify(t *testing.T) {
+	for _, tc := range []struct {
+		pada      string
+		syllables []string
+		pattern   string
+	}{
+		{"agním īḷe puróhitaṁ", []string{"a", "gni", "mī", "ḷe", "pu", "ro", "hi", "taṃ"}, "-u--u-u-"},
+		{"yajñásya devám r̥tvíjam", []string{"ya", "jña", "sya", "de", "va", "mṛ", "tvi", "jam"}, "--u-u-u-"},
+		{"hótāraṁ ratnadhā́tamam", []string{"ho", "tā", "raṃ", "ra", "tna", "dhā", "ta", "mam"}, "----u-u-"},
+		{"índrasya nú vīríyāṇi prá vocam", []string{"i", "ndra", "sya", "nu", "vī", "ri", "yā", "ṇi", "pra", "vo", "cam"}, "--uu-u--u--"},
+		{"praüga", []string{"pra", "ü", "ga"}, "uuu"},
+		{"devaiḥ", []string{"de", "vaiḥ"}, "--"},
+		{"sá devā́m̐ éhá vakṣati", []string{"sa", "de", "vāṃ", "e", "ha", "va", "kṣa", "ti"}, "u---u-uu"},
+	} {
+		syllables := Syllabify(tc.pada)
+		var texts []string
+		for _, s := range syllables {
+			texts = append(texts, s.Text)
+		}
+		assert.Equal(t, tc.syllables, texts, tc.pada)
+		assert.Equal(t, tc.pattern, Pattern(syllables), tc.pada)
+	}
+}
+
+func TestLookupMeter(t *testing.T) {
+	for _, label := range []string{"Gāyatrī", "Gayatri", "gāyatrī "} {
+		m, ok := LookupMeter(label)
+		assert.True(t, ok, label)
+		assert.Equal(t, []int{8, 8, 8}, m.Padas, label)
+	}
+	m, ok := LookupMeter("Triṣṭubh")
+	assert.True(t, ok)
+	assert.Equal(t, "Triṣṭubh", m.Name)
+	_, ok = LookupMeter("Dvipadā Virāj")
+	assert.False(t, ok)
+}
+
+func TestScan(t *testing.T) {
+	s := Scan([]string{"agním īḷe puróhitaṁ", "yajñásya devám r̥tvíjam", "hótāraṁ ratnadhā́tamam"}, "Gāyatrī")
+	assert.True(t, s.Checked)
+	assert.False(t, s.Irregular())
+	assert.Equal(t, 8, s.Padas[0].Expected)
+	assert.Equal(t, "x-ux", s.Padas[0].Cadence)
+
+	// a syllable short, restored by reading viriyani
+	s = Scan([]string{"índrasya nú vīryā̀ṇi prá vocam", "yā́ni cakā́ra prathamā́ni vajrī́"}, "Triṣṭubh")
+	assert.Equal(t, []string{"10 syllables, expected 11"}, s.Padas[0].Issues)
+	assert.Equal(t, 2, s.Padas[0].Resolutions)
+	assert.Empty(t, s.Padas[1].Issues)
+	assert.Equal(t, []string{"2 padas, expected 4"}, s.Issues)
+	assert.Equal(t, 1, s.IrregularPadas())
+
+	s = Scan([]string{"agním īḷe puróhitam agním"}, "Gāyatrī")
+	assert.Equal(t, []string{"10 syllables, expected 8"}, s.Padas[0].Issues)
+	assert.Zero(t, s.Padas[0].Resolutions)
+
+	s = Scan([]string{"agním īḷe purohītam"}, "Gāyatrī")
+	assert.Equal(t, []string{"cadence u---, expected x-ux"}, s.Padas[0].Issues)
+
+	s = Scan([]string{"agním īḷe puróhitaṁ"}, "Virāj")
+	assert.False(t, s.Checked)
+	assert.False(t, s.Irregular())
+	assert.Zero(t, s.Padas[0].Expected)
+}
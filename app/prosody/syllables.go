@@ -0,0 +1,172 @@
+package prosody
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+)
+
+// phoneme is a vowel or consonant of IAST text. Word boundaries are not phonemes, since
+// syllables run across words within a pada.
+type phoneme struct {
+	text   string
+	vowel  bool
+	long   bool
+	accent Accent
+}
+
+var (
+	longVowels  = map[string]bool{"ā": true, "ī": true, "ū": true, "ṝ": true, "e": true, "o": true, "ai": true, "au": true}
+	shortVowels = map[string]bool{"a": true, "i": true, "u": true, "ṛ": true}
+	// aspirated are the consonants written with a following h.
+	aspirated = "kgcjṭḍtdpbḷ"
+)
+
+const (
+	ringBelow = '\u0325'
+	// diaeresis marks a vowel in hiatus
+	diaeresisMark = '\u0308'
+	// candrabindu after m marks a nasalized vowel, which is taken like anusvara
+	candrabindu = '\u0310'
+	acute       = '\u0301'
+	grave       = '\u0300'
+)
+
+// phonemes splits an IAST line into phonemes. Accents are set on the vowels they mark, r and
+// l with a ring below are vowels and l with a dot below is the Vedic consonant. Other
+// characters like punctuation are dropped.
+func phonemes(line string) []phoneme {
+	// accents are taken out first, so that they do not split diphthongs
+	var rs []rune
+	var accents []Accent
+	for _, r := range common.NormalizeAccents(line) {
+		switch {
+		case (r == acute || r == grave) && len(rs) > 0:
+			accents[len(rs)-1] = Udatta
+			if r == grave {
+				accents[len(rs)-1] = Svarita
+			}
+		default:
+			rs = append(rs, r)
+			accents = append(accents, Unaccented)
+		}
+	}
+	var ps []phoneme
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		next := func() rune {
+			if i+1 < len(rs) {
+				return rs[i+1]
+			}
+			return 0
+		}
+		switch {
+		case (r == 'r' || r == 'l') && next() == ringBelow:
+			i++
+			ps = append(ps, phoneme{text: string(r) + string(ringBelow), vowel: true, accent: accents[i-1]})
+		case r == 'a' && (next() == 'i' || next() == 'u'):
+			i++
+			accent := accents[i-1]
+			if accent == Unaccented {
+				accent = accents[i]
+			}
+			ps = append(ps, phoneme{text: "a" + string(rs[i]), vowel: true, long: true, accent: accent})
+		case r == '\u00ef' || r == '\u00fc':
+			ps = append(ps, phoneme{text: string(r), vowel: true, accent: accents[i]})
+		case longVowels[string(r)]:
+			ps = append(ps, phoneme{text: string(r), vowel: true, long: true, accent: accents[i]})
+		case shortVowels[string(r)]:
+			ps = append(ps, phoneme{text: string(r), vowel: true, accent: accents[i]})
+		case r == 'm' && next() == candrabindu:
+			i++
+			ps = append(ps, phoneme{text: "ṃ"})
+		case strings.ContainsRune(aspirated, r) && next() == 'h':
+			i++
+			ps = append(ps, phoneme{text: string(r) + "h"})
+		case r == diaeresisMark && len(ps) > 0:
+			// a decomposed diaeresis marks the vowel before it as not part of a diphthong
+			if p := ps[len(ps)-1]; len(p.text) == 2 && p.long && strings.HasPrefix(p.text, "a") {
+				ps[len(ps)-1] = phoneme{text: "a", vowel: true, accent: p.accent}
+				ps = append(ps, phoneme{text: p.text[1:], vowel: true})
+			}
+		case unicode.Is(unicode.Mn, r):
+			// other diacritics
+		case unicode.IsLetter(r):
+			ps = append(ps, phoneme{text: string(r)})
+		}
+	}
+	return ps
+}
+
+// Syllable is a syllable of a pada with its quantity.
+type Syllable struct {
+	Text   string
+	Heavy  bool
+	Accent Accent
+}
+
+// Syllabify splits an IAST pada into syllables, which begin with the consonants before their
+// vowel. A syllable is heavy if its vowel is long or
+// it is followed by two or more consonants, counting anusvara and visarga, across word
+// boundaries. The last syllable is heavy if it ends with a consonant.
+func Syllabify(pada string) []Syllable {
+	ps := phonemes(pada)
+	var vowels []int
+	for i, p := range ps {
+		if p.vowel {
+			vowels = append(vowels, i)
+		}
+	}
+	syllables := make([]Syllable, len(vowels))
+	start := 0
+	for k, v := range vowels {
+		last := k+1 == len(vowels)
+		// the consonants after the vowel begin the next syllable, except anusvara and visarga
+		end := len(ps)
+		if !last {
+			end = v + 1
+			for end < vowels[k+1] && (ps[end].text == "ṃ" || ps[end].text == "ḥ") {
+				end++
+			}
+		}
+		following := len(ps) - v - 1
+		if !last {
+			following = vowels[k+1] - v - 1
+		}
+		var sb strings.Builder
+		for _, p := range ps[start:end] {
+			sb.WriteString(p.text)
+		}
+		heavy := ps[v].long || following >= 2 || (last && following > 0)
+		syllables[k] = Syllable{Text: sb.String(), Heavy: heavy, Accent: ps[v].accent}
+		start = end
+	}
+	return syllables
+}
+
+// Pattern returns the quantities of syllables, - for heavy and u for light.
+func Pattern(syllables []Syllable) string {
+	var sb strings.Builder
+	for _, s := range syllables {
+		if s.Heavy {
+			sb.WriteByte('-')
+		} else {
+			sb.WriteByte('u')
+		}
+	}
+	return sb.String()
+}
+
+// resolutions counts the y and v following a consonant, which may be read as the vowels iy
+// and uv, adding a syllable, eg: viriyani for viryani.
+func resolutions(pada string) int {
+	ps := phonemes(pada)
+	n := 0
+	for i := 1; i < len(ps); i++ {
+		if (ps[i].text == "y" || ps[i].text == "v") && !ps[i-1].vowel && i+1 < len(ps) && ps[i+1].vowel {
+			n++
+		}
+	}
+	return n
+}
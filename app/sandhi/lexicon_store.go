@@ -0,0 +1,12 @@
+package sandhi
+
+import "context"
+
+// LexiconStore reads the words known to the splitter.
+type LexiconStore interface {
+	// Headwords returns the distinct headwords of all the dictionaries, in SLP1.
+	Headwords(ctx context.Context) ([]string, error)
+	// Surfaces returns the distinct glossing surfaces of all the scriptures, in IAST with
+	// accents folded.
+	Surfaces(ctx context.Context) ([]string, error)
+}
@@ -0,0 +1,145 @@
+package sandhi
+
+import "strings"
+
+// Classes of SLP1 letters used as the contexts of rules.
+const (
+	vowels     = "aAiIuUfFxXeEoO"
+	voicedCons = "gGjJqQdDbBNYRnmyrlvh"
+	voiced     = vowels + voicedCons
+)
+
+// rule undoes a sandhi at a word boundary: surface in the text is left at the end of a word
+// followed by right at the start of the next word. The text after surface continues the next
+// word. If next is not empty, the letter following surface must be one of next.
+type rule struct {
+	surface string
+	left    string
+	right   string
+	next    string
+	cost    float64
+}
+
+// merging reports whether the surface of the rule is shared by both words, in which case
+// the words can not be separated by a space in the text.
+func (r rule) merging() bool {
+	return r.right != ""
+}
+
+const (
+	mergingRuleCost = 0.3
+	changeRuleCost  = 0.2
+)
+
+// vowelRules returns the rules of vowels coalescing into surface, for all pairs of left and
+// right vowels.
+func vowelRules(surface string, lefts, rights string) []rule {
+	var rs []rule
+	for _, l := range lefts {
+		for _, r := range rights {
+			rs = append(rs, rule{surface: surface, left: string(l), right: string(r), cost: mergingRuleCost})
+		}
+	}
+	return rs
+}
+
+// changeRules returns the rules of the final letters of lefts changing to surface before one
+// of next.
+func changeRules(surface string, lefts []string, next string) []rule {
+	var rs []rule
+	for _, l := range lefts {
+		rs = append(rs, rule{surface: surface, left: l, next: next, cost: changeRuleCost})
+	}
+	return rs
+}
+
+// rules are the reverse sandhi rules, for external sandhi of vowels, visarga and final
+// consonants. Word final s and r are written as visarga H, as in pausa.
+var rules = func() []rule {
+	var rs []rule
+	// vowels
+	rs = append(rs, vowelRules("A", "aA", "aA")...)
+	rs = append(rs, vowelRules("I", "iI", "iI")...)
+	rs = append(rs, vowelRules("U", "uU", "uU")...)
+	rs = append(rs, vowelRules("F", "fF", "fF")...)
+	rs = append(rs, vowelRules("e", "aA", "iI")...)
+	rs = append(rs, vowelRules("o", "aA", "uU")...)
+	rs = append(rs, vowelRules("E", "aA", "eE")...)
+	rs = append(rs, vowelRules("O", "aA", "oO")...)
+	rs = append(rs, vowelRules("ar", "aA", "f")...)
+	rs = append(rs, changeRules("y", []string{"i", "I"}, vowels)...)
+	rs = append(rs, changeRules("v", []string{"u", "U"}, vowels)...)
+	rs = append(rs, changeRules("r", []string{"f"}, vowels)...)
+	rs = append(rs, changeRules("ay", []string{"e"}, vowels)...)
+	rs = append(rs, changeRules("Ay", []string{"E"}, vowels)...)
+	rs = append(rs, changeRules("av", []string{"o"}, vowels)...)
+	rs = append(rs, changeRules("Av", []string{"O"}, vowels)...)
+	// y and v between vowels are often dropped, eg: agna A for agne A
+	rs = append(rs, changeRules("a", []string{"e"}, vowels)...)
+	rs = append(rs, changeRules("A", []string{"E"}, vowels)...)
+	// avagraha
+	rs = append(rs,
+		rule{surface: "e'", left: "e", right: "a", cost: mergingRuleCost},
+		rule{surface: "o'", left: "aH", right: "a", cost: mergingRuleCost})
+
+	// visarga
+	rs = append(rs, changeRules("o", []string{"aH"}, voicedCons)...)
+	rs = append(rs, changeRules("a", []string{"aH"}, "AiIuUfeEoO")...)
+	rs = append(rs, changeRules("A", []string{"AH"}, voiced)...)
+	rs = append(rs, changeRules("r", []string{"H"}, voiced)...)
+	rs = append(rs, changeRules("s", []string{"H"}, "tT")...)
+	rs = append(rs, changeRules("S", []string{"H"}, "cC")...)
+	rs = append(rs, changeRules("z", []string{"H"}, "wW")...)
+
+	// final consonants
+	rs = append(rs, changeRules("d", []string{"t"}, voiced)...)
+	rs = append(rs, changeRules("g", []string{"k"}, voiced)...)
+	rs = append(rs, changeRules("q", []string{"w"}, voiced)...)
+	rs = append(rs, changeRules("b", []string{"p"}, voiced)...)
+	rs = append(rs, changeRules("j", []string{"t"}, "jJ")...)
+	rs = append(rs, changeRules("c", []string{"t"}, "cC")...)
+	rs = append(rs, changeRules("l", []string{"t"}, "l")...)
+	rs = append(rs, changeRules("n", []string{"t"}, "nm")...)
+	rs = append(rs, changeRules("N", []string{"k"}, "nm")...)
+	rs = append(rs, changeRules("R", []string{"w"}, "nm")...)
+	rs = append(rs, changeRules("m", []string{"p"}, "nm")...)
+	rs = append(rs, changeRules("Y", []string{"n"}, "cCjJS")...)
+	rs = append(rs, changeRules("Ms", []string{"n"}, "tT")...)
+	rs = append(rs, changeRules("MS", []string{"n"}, "cC")...)
+	rs = append(rs, changeRules("nn", []string{"n"}, vowels)...)
+	rs = append(rs, changeRules("M", []string{"m"}, "kKgGcCjJwWqQtTdDpPbBnmyrlvSzsh")...)
+	rs = append(rs,
+		rule{surface: "cC", left: "t", right: "S", cost: mergingRuleCost},
+		rule{surface: "dD", left: "t", right: "h", cost: mergingRuleCost},
+		rule{surface: "gG", left: "k", right: "h", cost: mergingRuleCost},
+		rule{surface: "bB", left: "p", right: "h", cost: mergingRuleCost})
+	return rs
+}()
+
+// rulesByFirst indexes the rules by the first letter of their surface.
+var rulesByFirst = func() map[byte][]rule {
+	m := make(map[byte][]rule)
+	for _, r := range rules {
+		m[r.surface[0]] = append(m[r.surface[0]], r)
+	}
+	return m
+}()
+
+// PausaForm returns an SLP1 word as it is written at the end of a sentence, with final s and
+// r as visarga and final anusvara as m. Words are looked up in the lexicon by this form.
+func PausaForm(word string) string {
+	if strings.HasPrefix(word, "'") {
+		// avagraha for an elided a at the start of a word
+		word = "a" + word[1:]
+	}
+	if word == "" {
+		return word
+	}
+	switch word[len(word)-1] {
+	case 's', 'r':
+		return word[:len(word)-1] + "H"
+	case 'M':
+		return word[:len(word)-1] + "m"
+	}
+	return word
+}
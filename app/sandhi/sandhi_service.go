@@ -0,0 +1,130 @@
+package sandhi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/mahesh-hegde/dhee/app/transliteration"
+)
+
+const (
+	// MaxTextLength bounds the characters of the text to split.
+	MaxTextLength     = 200
+	defaultSplitLimit = 5
+	maxSplitLimit     = 20
+)
+
+type SandhiService struct {
+	store          LexiconStore
+	conf           *config.DheeConfig
+	transliterator *transliteration.Transliterator
+
+	mu       sync.Mutex
+	splitter *Splitter
+}
+
+func NewSandhiService(store LexiconStore, conf *config.DheeConfig, transliterator *transliteration.Transliterator) *SandhiService {
+	return &SandhiService{
+		store:          store,
+		conf:           conf,
+		transliterator: transliterator,
+	}
+}
+
+// Init builds the lexicon of the splitter from the dictionary headwords and the attested
+// glossing surfaces, if not already built.
+func (s *SandhiService) Init(ctx context.Context) error {
+	_, err := s.getSplitter(ctx)
+	return err
+}
+
+func (s *SandhiService) getSplitter(ctx context.Context) (*Splitter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.splitter != nil {
+		return s.splitter, nil
+	}
+
+	start := time.Now()
+	splitter := NewSplitter()
+	headwords, err := s.store.Headwords(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range headwords {
+		splitter.Add(w, SourceHeadword)
+	}
+	surfaces, err := s.store.Surfaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, surface := range surfaces {
+		slp1, err := s.transliterator.Convert(surface, common.TlIAST, common.TlSLP1)
+		if err != nil {
+			slog.Debug("could not transliterate surface to slp1", "surface", surface)
+			continue
+		}
+		chars, _ := letters(slp1)
+		splitter.Add(string(chars), SourceAttested)
+	}
+	slog.Info("built sandhi splitter lexicon", "words", splitter.Len(), "duration", time.Since(start))
+	s.splitter = splitter
+	return splitter, nil
+}
+
+// Split proposes segmentations of the text into words, best first.
+func (s *SandhiService) Split(ctx context.Context, params SplitParams) (*SplitResults, error) {
+	text := strings.TrimSpace(params.Text)
+	if text == "" {
+		return nil, common.NewUserVisibleError(http.StatusBadRequest, "text is required")
+	}
+	if utf8.RuneCountInString(text) > MaxTextLength {
+		return nil, common.NewUserVisibleError(http.StatusBadRequest,
+			fmt.Sprintf("text can have at most %d characters", MaxTextLength))
+	}
+	if params.Limit <= 0 {
+		params.Limit = defaultSplitLimit
+	}
+	params.Limit = min(params.Limit, maxSplitLimit)
+
+	switch params.Tl {
+	case common.TlIAST:
+		text = common.FoldAccents(strings.ToLower(text))
+	case common.TlNagari:
+		text = s.transliterator.FoldDevanagariAccents(text)
+	}
+	slp1 := text
+	if params.Tl != common.TlSLP1 {
+		var err error
+		slp1, err = s.transliterator.Convert(text, params.Tl, common.TlSLP1)
+		if err != nil {
+			return nil, common.NewUserVisibleError(http.StatusBadRequest, "text could not be transliterated")
+		}
+	}
+
+	splitter, err := s.getSplitter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build sandhi splitter: %w", err)
+	}
+	segs := splitter.Split(slp1, params.Limit)
+	if segs == nil {
+		segs = []Segmentation{}
+	}
+	for i := range segs {
+		for j := range segs[i].Words {
+			w := &segs[i].Words[j]
+			if iast, err := s.transliterator.Convert(w.SLP1, common.TlSLP1, common.TlIAST); err == nil {
+				w.IAST = iast
+			}
+		}
+	}
+	return &SplitResults{Params: params, Segmentations: segs}, nil
+}
@@ -0,0 +1,42 @@
+package sandhi
+
+import "github.com/mahesh-hegde/dhee/app/common"
+
+// WordSource tells how a word of a segmentation was validated.
+type WordSource string
+
+const (
+	// SourceAttested words are surfaces of the glossings of a scripture.
+	SourceAttested WordSource = "attested"
+	// SourceHeadword words are dictionary headwords.
+	SourceHeadword WordSource = "headword"
+	// SourceUnknown words are in neither, and are likely wrong splits.
+	SourceUnknown WordSource = "unknown"
+)
+
+type SplitParams struct {
+	Text string                 `json:"text"`
+	Tl   common.Transliteration `json:"tl"`
+	// Limit is the maximum number of segmentations.
+	Limit int `json:"limit"`
+}
+
+// SplitWord is a word of a segmentation, in pausa form.
+type SplitWord struct {
+	SLP1   string     `json:"slp1"`
+	IAST   string     `json:"iast"`
+	Source WordSource `json:"source"`
+}
+
+// Segmentation is a proposed split of the text into words.
+type Segmentation struct {
+	Words []SplitWord `json:"words"`
+	// Cost ranks the segmentations, lower is better. Unknown words, more words and applied
+	// sandhi rules cost more.
+	Cost float64 `json:"cost"`
+}
+
+type SplitResults struct {
+	Params        SplitParams    `json:"params"`
+	Segmentations []Segmentation `json:"segmentations"`
+}
@@ -0,0 +1,211 @@
+package sandhi
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Costs of the words of a segmentation.
+const (
+	attestedCost = 1.0
+	headwordCost = 1.3
+	// shortWordCost is added for single letter words, which are mostly spurious splits.
+	shortWordCost     = 1.0
+	unknownCost       = 3.0
+	unknownLetterCost = 0.5
+)
+
+// maxWordLength bounds the length of the words of a segmentation.
+const maxWordLength = 40
+
+// Splitter splits continuous SLP1 text into words by undoing sandhi, preferring the known
+// words of its lexicon.
+type Splitter struct {
+	lexicon map[string]WordSource
+}
+
+func NewSplitter() *Splitter {
+	return &Splitter{lexicon: make(map[string]WordSource)}
+}
+
+// Add adds an SLP1 word to the lexicon. Attested words take precedence over headwords.
+func (s *Splitter) Add(word string, source WordSource) {
+	word = PausaForm(word)
+	if word == "" || s.lexicon[word] == SourceAttested {
+		return
+	}
+	s.lexicon[word] = source
+}
+
+// Len returns the number of words in the lexicon.
+func (s *Splitter) Len() int {
+	return len(s.lexicon)
+}
+
+// wordCost returns the cost and the source of a word in pausa form.
+func (s *Splitter) wordCost(word string) (float64, WordSource) {
+	source, ok := s.lexicon[word]
+	if !ok {
+		return unknownCost + unknownLetterCost*float64(len(word)), SourceUnknown
+	}
+	cost := headwordCost
+	if source == SourceAttested {
+		cost = attestedCost
+	}
+	if len(word) == 1 {
+		cost += shortWordCost
+	}
+	return cost, source
+}
+
+// path is a partial segmentation, as a list of words from the last one.
+type path struct {
+	cost   float64
+	word   string
+	source WordSource
+	prev   *path
+}
+
+// kBest holds the cheapest paths reaching a state, in order of cost.
+type kBest []*path
+
+func (b *kBest) add(p *path, k int) {
+	if len(*b) == k && (*b)[k-1].cost <= p.cost {
+		return
+	}
+	i := sort.Search(len(*b), func(i int) bool { return (*b)[i].cost > p.cost })
+	*b = slices.Insert(*b, i, p)
+	if len(*b) > k {
+		*b = (*b)[:k]
+	}
+}
+
+// letters returns the SLP1 letters of text, and whether a word break precedes each letter.
+// Spaces and punctuation are word breaks, other characters like accents are dropped.
+func letters(text string) ([]byte, []bool) {
+	var s []byte
+	breaks := []bool{false}
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '\'':
+			s = append(s, c)
+			breaks = append(breaks, false)
+		case strings.IndexByte(" \t\r\n|,.;:!?-/()[]", c) >= 0:
+			breaks[len(s)] = len(s) > 0
+		}
+	}
+	return s, breaks
+}
+
+// Split returns up to limit segmentations of SLP1 text into words, cheapest first. Words
+// separated by spaces or punctuation in the text are never joined.
+func (s *Splitter) Split(text string, limit int) []Segmentation {
+	chars, breaks := letters(text)
+	n := len(chars)
+	if n == 0 || limit <= 0 {
+		return nil
+	}
+	k := 2 * limit
+
+	// states[pos][carry] are the paths reaching pos, where carry is the start of the next
+	// word produced by undoing a sandhi, eg: i for e from a + i.
+	states := make([]map[string]*kBest, n+1)
+	states[0] = map[string]*kBest{"": {&path{}}}
+	var finals kBest
+
+	extend := func(paths kBest, word string, ruleCost float64, target *kBest) {
+		word = PausaForm(word)
+		cost, source := s.wordCost(word)
+		for _, p := range paths {
+			target.add(&path{cost: p.cost + cost + ruleCost, word: word, source: source, prev: p}, k)
+		}
+	}
+	stateAt := func(pos int, carry string) *kBest {
+		if states[pos] == nil {
+			states[pos] = make(map[string]*kBest)
+		}
+		if states[pos][carry] == nil {
+			states[pos][carry] = &kBest{}
+		}
+		return states[pos][carry]
+	}
+
+	splitFrom := func(pos int, carry string) {
+		paths := *states[pos][carry]
+		for e := pos; e <= n && e-pos <= maxWordLength; e++ {
+			if e-1 > pos && breaks[e-1] {
+				break
+			}
+			base := carry + string(chars[pos:e])
+			if base == "" {
+				continue
+			}
+			if e == n {
+				extend(paths, base, 0, &finals)
+				break
+			}
+			extend(paths, base, 0, stateAt(e, ""))
+			if breaks[e] && e > pos {
+				// the sandhi would be in the next word
+				continue
+			}
+			for _, r := range rulesByFirst[chars[e]] {
+				end := e + len(r.surface)
+				if end > n || string(chars[e:end]) != r.surface || slices.Contains(breaks[e+1:end], true) {
+					continue
+				}
+				if r.next != "" && (end == n || strings.IndexByte(r.next, chars[end]) < 0) {
+					continue
+				}
+				if r.merging() && end < n && breaks[end] {
+					continue
+				}
+				extend(paths, base+r.left, r.cost, stateAt(end, r.right))
+			}
+		}
+	}
+
+	for pos := 0; pos <= n; pos++ {
+		var carries []string
+		for carry := range states[pos] {
+			if carry != "" {
+				carries = append(carries, carry)
+			}
+		}
+		slices.Sort(carries)
+		for _, carry := range carries {
+			splitFrom(pos, carry)
+		}
+		// after the paths with a carry, which can end a word at pos
+		if states[pos][""] != nil {
+			splitFrom(pos, "")
+		}
+		states[pos] = nil
+	}
+
+	var segs []Segmentation
+	seen := make(map[string]bool)
+	for _, p := range finals {
+		var words []SplitWord
+		for q := p; q.prev != nil; q = q.prev {
+			words = append(words, SplitWord{SLP1: q.word, Source: q.source})
+		}
+		slices.Reverse(words)
+		var key strings.Builder
+		for _, w := range words {
+			key.WriteString(w.SLP1)
+			key.WriteByte(' ')
+		}
+		if seen[key.String()] {
+			continue
+		}
+		seen[key.String()] = true
+		segs = append(segs, Segmentation{Words: words, Cost: p.cost})
+		if len(segs) == limit {
+			break
+		}
+	}
+	return segs
+}
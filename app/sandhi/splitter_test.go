@@ -0,0 +1,72 @@
+package sandhi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSplitter() *Splitter {
+	s := NewSplitter()
+	for _, w := range []string{"agnim", "Ixe", "purohitam", "agne", "yAhi", "saH", "agniH", "indraH", "ca", "iti", "tat", "satyam", "asi", "tvam", "indra", "Agacca", "deva"} {
+		s.Add(w, SourceAttested)
+	}
+	for _, w := range []string{"a", "A", "i", "gam", "agni", "sat"} {
+		s.Add(w, SourceHeadword)
+	}
+	return s
+}
+
+func bestSplit(s *Splitter, text string) string {
+	segs := s.Split(text, 3)
+	if len(segs) == 0 {
+		return ""
+	}
+	var words []string
+	for _, w := range segs[0].Words {
+		words = append(words, w.SLP1)
+	}
+	return strings.Join(words, " ")
+}
+
+func TestSplit(t *testing.T) {
+	s := newTestSplitter()
+	for text, want := range map[string]string{
+		"agnimIxe purohitaM": "agnim Ixe purohitam",
+		"agna A yAhi":        "agne A yAhi",
+		"so'gniH":            "saH agniH",
+		"indraSca":           "indraH ca",
+		"tattvamasi":         "tat tvam asi",
+		"indrAgacca":         "indra Agacca",
+		"itIndraH":           "iti indraH",
+		"devendra":           "deva indra",
+	} {
+		assert.Equal(t, want, bestSplit(s, text), text)
+	}
+}
+
+func TestSplitRanking(t *testing.T) {
+	s := newTestSplitter()
+	segs := s.Split("tatsatyam", 3)
+	if !assert.Len(t, segs, 3) {
+		return
+	}
+	assert.Equal(t, []SplitWord{{SLP1: "tat", Source: SourceAttested}, {SLP1: "satyam", Source: SourceAttested}}, segs[0].Words)
+	for i := 1; i < len(segs); i++ {
+		assert.LessOrEqual(t, segs[i-1].Cost, segs[i].Cost)
+	}
+	assert.Empty(t, s.Split("  ", 3))
+}
+
+func TestSplitKeepsBreaks(t *testing.T) {
+	s := newTestSplitter()
+	// e from a + i can not be split across a space
+	segs := s.Split("deve ndra", 5)
+	assert.NotEmpty(t, segs)
+	for _, seg := range segs {
+		for _, w := range seg.Words {
+			assert.NotEqual(t, "indra", w.SLP1)
+		}
+	}
+}
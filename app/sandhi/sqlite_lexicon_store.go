@@ -0,0 +1,47 @@
+package sandhi
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mahesh-hegde/dhee/app/config"
+)
+
+// SQLiteLexiconStore reads the dictionary entries and the glossings index maintained by the
+// dictionary and excerpts stores.
+type SQLiteLexiconStore struct {
+	db   *sql.DB
+	conf *config.DheeConfig
+}
+
+func NewSQLiteLexiconStore(db *sql.DB, conf *config.DheeConfig) *SQLiteLexiconStore {
+	return &SQLiteLexiconStore{db: db, conf: conf}
+}
+
+var _ LexiconStore = &SQLiteLexiconStore{}
+
+func (s *SQLiteLexiconStore) Headwords(ctx context.Context) ([]string, error) {
+	return s.readWords(ctx, "SELECT DISTINCT word FROM dhee_dictionary_entries")
+}
+
+func (s *SQLiteLexiconStore) Surfaces(ctx context.Context) ([]string, error) {
+	return s.readWords(ctx, "SELECT DISTINCT surface FROM dhee_glossings WHERE surface != ''")
+}
+
+func (s *SQLiteLexiconStore) readWords(ctx context.Context, query string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read words: %w", err)
+	}
+	defer rows.Close()
+	var words []string
+	for rows.Next() {
+		var word string
+		if err := rows.Scan(&word); err != nil {
+			return nil, err
+		}
+		words = append(words, word)
+	}
+	return words, rows.Err()
+}
@@ -0,0 +1,54 @@
+package server
+
+import (
+	_ "embed"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIPrefix is the path prefix of the versioned JSON API. The API serves the same data as
+// the HTML pages, see openapi.json for the response schemas.
+const APIPrefix = "/api/v1"
+
+//go:embed openapi.json
+var openAPIDoc []byte
+
+// APIError is the body of JSON error responses.
+type APIError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// isAPIRequest reports whether the response should be JSON, either because the request is
+// under APIPrefix, or because it accepts JSON but not HTML.
+func isAPIRequest(ctx echo.Context) bool {
+	if strings.HasPrefix(ctx.Request().URL.Path, APIPrefix+"/") {
+		return true
+	}
+	accept := ctx.Request().Header.Get(echo.HeaderAccept)
+	return strings.Contains(accept, echo.MIMEApplicationJSON) && !strings.Contains(accept, echo.MIMETextHTML)
+}
+
+// render writes the data as JSON for API requests, else renders the named template.
+// Template name modifiers such as ".preview" do not change the JSON response.
+func (c *DheeController) render(ctx echo.Context, code int, name string, data any) error {
+	if isAPIRequest(ctx) {
+		return ctx.JSON(code, data)
+	}
+	return ctx.Render(code, name, data)
+}
+
+// routeName returns the name of the API route for API requests, so that redirects stay
+// within the API.
+func routeName(ctx echo.Context, name string) string {
+	if strings.HasPrefix(ctx.Request().URL.Path, APIPrefix+"/") {
+		return "api." + name
+	}
+	return name
+}
+
+func (c *DheeController) GetOpenAPI(ctx echo.Context) error {
+	return ctx.Blob(http.StatusOK, echo.MIMEApplicationJSON, openAPIDoc)
+}
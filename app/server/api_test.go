@@ -0,0 +1,168 @@
+package server
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/mahesh-hegde/dhee/app/dictionary"
+	"github.com/mahesh-hegde/dhee/app/excerpts"
+	"github.com/mahesh-hegde/dhee/app/inflection"
+	"github.com/mahesh-hegde/dhee/app/prosody"
+	"github.com/mahesh-hegde/dhee/app/sandhi"
+	"github.com/mahesh-hegde/dhee/app/visualizer"
+	"github.com/stretchr/testify/assert"
+)
+
+type openAPISchema struct {
+	Ref        string                    `json:"$ref"`
+	Properties map[string]*openAPISchema `json:"properties"`
+	AllOf      []*openAPISchema          `json:"allOf"`
+}
+
+type openAPI struct {
+	Paths      map[string]any `json:"paths"`
+	Components struct {
+		Schemas map[string]*openAPISchema `json:"schemas"`
+	} `json:"components"`
+}
+
+func TestOpenAPIPaths(t *testing.T) {
+	var doc openAPI
+	assert.NoError(t, json.Unmarshal(openAPIDoc, &doc))
+
+	e := echo.New()
+	registerRoutes(e, &DheeController{})
+	param := regexp.MustCompile(`:(\w+)`)
+	var routes []string
+	for _, r := range e.Routes() {
+		if path, ok := strings.CutPrefix(r.Path, APIPrefix); ok {
+			routes = append(routes, param.ReplaceAllString(path, "{$1}"))
+		}
+	}
+	var documented []string
+	for path := range doc.Paths {
+		documented = append(documented, path)
+	}
+	sort.Strings(routes)
+	sort.Strings(documented)
+	assert.Equal(t, routes, documented)
+}
+
+// TestOpenAPISchemas checks that the documented properties are the JSON fields of the response types.
+func TestOpenAPISchemas(t *testing.T) {
+	var doc openAPI
+	assert.NoError(t, json.Unmarshal(openAPIDoc, &doc))
+
+	types := map[string]any{
+		"Pagination":             common.Pagination{},
+		"ScriptureDefn":          config.ScriptureDefn{},
+		"DictDefn":               config.DictDefn{},
+		"Excerpt":                excerpts.Excerpt{},
+		"WordGlossing":           excerpts.WordGlossing{},
+		"FormulaOccurrence":      excerpts.FormulaOccurrence{},
+		"ExcerptWithWords":       excerpts.ExcerptWithWords{},
+		"ExcerptTemplateData":    excerpts.ExcerptTemplateData{},
+		"PadaElement":            excerpts.PadaElement{},
+		"SourcedMeaning":         excerpts.SourcedMeaning{},
+		"GrammaticalTagStyle":    common.GrammaticalTagStyle{},
+		"Hierarchy":              excerpts.Hierarchy{},
+		"HierParent":             excerpts.HierParent{},
+		"SearchParams":           excerpts.SearchParams{},
+		"HighlightedExcerpt":     excerpts.HighlightedExcerpt{},
+		"ExcerptSearchData":      excerpts.ExcerptSearchData{},
+		"Facet":                  excerpts.Facet{},
+		"KWICData":               excerpts.KWICData{},
+		"FormulaIndexData":       excerpts.FormulaIndexData{},
+		"FormulaData":            excerpts.FormulaData{},
+		"ScannedPada":            prosody.Pada{},
+		"Scansion":               prosody.Scansion{},
+		"SyllableCount":          excerpts.SyllableCount{},
+		"MeterStats":             excerpts.MeterStats{},
+		"MeterIndexData":         excerpts.MeterIndexData{},
+		"MeterData":              excerpts.MeterData{},
+		"AccentForm":             excerpts.AccentForm{},
+		"PlacementCount":         excerpts.PlacementCount{},
+		"AccentCategory":         excerpts.AccentCategory{},
+		"AccentData":             excerpts.AccentData{},
+		"Attribution":            excerpts.Attribution{},
+		"AttributionCount":       excerpts.AttributionCount{},
+		"AttributionIndexData":   excerpts.AttributionIndexData{},
+		"AttributedHymn":         excerpts.AttributedHymn{},
+		"AttributionData":        excerpts.AttributionData{},
+		"DictionaryEntry":        dictionary.DictionaryEntry{},
+		"Meaning":                dictionary.Meaning{},
+		"DictionaryWordResponse": dictionary.DictionaryWordResponse{},
+		"DictionarySearchParams": dictionary.SearchParams{},
+		"SearchResults":          dictionary.SearchResults{},
+		"Suggestions":            dictionary.Suggestions{},
+		"Form":                   inflection.Form{},
+		"FormCell":               inflection.FormCell{},
+		"DeclensionRow":          inflection.DeclensionRow{},
+		"DeclensionTable":        inflection.DeclensionTable{},
+		"ConjugationRow":         inflection.ConjugationRow{},
+		"ConjugationTable":       inflection.ConjugationTable{},
+		"Analysis":               inflection.Analysis{},
+		"AnalysisParams":         dictionary.AnalysisParams{},
+		"AnalysisResults":        dictionary.AnalysisResults{},
+		"VisualizationResponse":  visualizer.VisualizationResponse{},
+		"Point":                  visualizer.Point{},
+		"Series":                 visualizer.Series{},
+		"SplitParams":            sandhi.SplitParams{},
+		"SplitWord":              sandhi.SplitWord{},
+		"Segmentation":           sandhi.Segmentation{},
+		"SplitResults":           sandhi.SplitResults{},
+	}
+	for name, v := range types {
+		schema, ok := doc.Components.Schemas[name]
+		if !assert.True(t, ok, "schema %s is not documented", name) {
+			continue
+		}
+		assert.Equal(t, jsonFields(reflect.TypeOf(v)), schemaProperties(doc, schema), "schema %s", name)
+	}
+}
+
+func jsonFields(t reflect.Type) []string {
+	var fields []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous && name == "" {
+			fields = append(fields, jsonFields(f.Type)...)
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, name)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func schemaProperties(doc openAPI, s *openAPISchema) []string {
+	if ref, ok := strings.CutPrefix(s.Ref, "#/components/schemas/"); ok {
+		return schemaProperties(doc, doc.Components.Schemas[ref])
+	}
+	var props []string
+	for p := range s.Properties {
+		props = append(props, p)
+	}
+	for _, part := range s.AllOf {
+		props = append(props, schemaProperties(doc, part)...)
+	}
+	sort.Strings(props)
+	return props
+}
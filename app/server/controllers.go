@@ -1,6 +1,7 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -12,7 +13,10 @@ import (
 	"github.com/mahesh-hegde/dhee/app/config"
 	"github.com/mahesh-hegde/dhee/app/dictionary"
 	excerpts "github.com/mahesh-hegde/dhee/app/excerpts"
+	"github.com/mahesh-hegde/dhee/app/inflection"
+	"github.com/mahesh-hegde/dhee/app/sandhi"
 	"github.com/mahesh-hegde/dhee/app/transliteration"
+	"github.com/mahesh-hegde/dhee/app/visualizer"
 )
 
 const MAX_CONCURRENT_REGEX_SEARCHES = 20
@@ -21,6 +25,8 @@ const MAX_CONCURRENT_REGEX_SEARCHES = 20
 type DheeController struct {
 	ds            *dictionary.DictionaryService
 	es            *excerpts.ExcerptService
+	vs            *visualizer.VisualizerService
+	ss            *sandhi.SandhiService
 	conf          *config.DheeConfig
 	sconf         *config.ServerRuntimeConfig
 	regexLimiter  chan struct{}
@@ -28,10 +34,12 @@ type DheeController struct {
 }
 
 // NewDheeController creates a new controller instance and initializes the regex limiter.
-func NewDheeController(dictStore dictionary.DictStore, excerptStore excerpts.ExcerptStore, conf *config.DheeConfig, sconf *config.ServerRuntimeConfig, transliterator *transliteration.Transliterator) *DheeController {
+func NewDheeController(dictStore dictionary.DictStore, excerptStore excerpts.ExcerptStore, visualizerStore visualizer.VisualizerStore, lexiconStore sandhi.LexiconStore, formStore inflection.FormStore, conf *config.DheeConfig, sconf *config.ServerRuntimeConfig, transliterator *transliteration.Transliterator) *DheeController {
 	controller := &DheeController{
-		ds:           dictionary.NewDictionaryService(dictStore, conf, transliterator),
+		ds:           dictionary.NewDictionaryService(dictStore, formStore, conf, transliterator),
 		es:           excerpts.NewExcerptService(dictStore, excerptStore, conf, transliterator),
+		vs:           visualizer.NewVisualizerService(visualizerStore, conf, transliterator),
+		ss:           sandhi.NewSandhiService(lexiconStore, conf, transliterator),
 		conf:         conf,
 		sconf:        sconf,
 		regexLimiter: make(chan struct{}, MAX_CONCURRENT_REGEX_SEARCHES), // limit to 20 concurrent regex searches
@@ -42,6 +50,12 @@ func NewDheeController(dictStore dictionary.DictStore, excerptStore excerpts.Exc
 	for i := 0; i < sconf.GlobalRateLimit; i++ {
 		controller.globalLimiter <- struct{}{}
 	}
+	// build the sandhi lexicon before the first request needs it
+	go func() {
+		if err := controller.ss.Init(context.Background()); err != nil {
+			slog.Error("error while building sandhi splitter lexicon", "err", err)
+		}
+	}()
 	return controller
 }
 
@@ -70,7 +84,7 @@ func (c *DheeController) GetExcerpts(ctx echo.Context) error {
 	if pathStr == "" {
 		pathStr = ctx.QueryParam("path")
 		if pathStr != "" {
-			return ctx.Redirect(307, ctx.Echo().Reverse("excerpts", scriptureName, pathStr))
+			return ctx.Redirect(307, ctx.Echo().Reverse(routeName(ctx, "excerpts"), scriptureName, pathStr))
 		}
 	}
 
@@ -85,7 +99,7 @@ func (c *DheeController) GetExcerpts(ctx echo.Context) error {
 	ctx.Set("pageTitle", scri.ReadableName+" "+pathStr)
 
 	if len(parts) < len(scri.Hierarchy) {
-		return ctx.Redirect(307, ctx.Echo().Reverse("hierarchy", scriptureName, pathStr))
+		return ctx.Redirect(307, ctx.Echo().Reverse(routeName(ctx, "hierarchy"), scriptureName, pathStr))
 	}
 
 	lastPart := parts[len(parts)-1]
@@ -139,7 +153,7 @@ func (c *DheeController) GetExcerpts(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "Failed to get excerpts. Cross check the excerpt number.")
 	}
 
-	return ctx.Render(http.StatusOK, "excerpts", excerpts)
+	return c.render(ctx, http.StatusOK, "excerpts", excerpts)
 }
 
 func (c *DheeController) GetHierarchy(ctx echo.Context) error {
@@ -168,9 +182,131 @@ func (c *DheeController) GetHierarchy(ctx echo.Context) error {
 		title = title + " " + pathStr
 	}
 	ctx.Set("pageTitle", title)
-	return ctx.Render(http.StatusOK, "hierarchy", hier)
+	return c.render(ctx, http.StatusOK, "hierarchy", hier)
+}
+
+func (c *DheeController) ListFormulas(ctx echo.Context) error {
+	scriptureName := ctx.Param("scriptureName")
+	page, err := parsePageParam(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := c.es.ListFormulas(ctx.Request().Context(), scriptureName, page)
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to list formulas")
+	}
+
+	ctx.Set("pageTitle", "Formulas in "+data.Scripture.ReadableName)
+	return c.render(ctx, http.StatusOK, "formula_index", data)
+}
+
+func (c *DheeController) GetFormula(ctx echo.Context) error {
+	scriptureName := ctx.Param("scriptureName")
+	page, err := parsePageParam(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := c.es.GetFormula(ctx.Request().Context(), scriptureName, ctx.Param("id"), page)
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to get formula")
+	}
+
+	ctx.Set("pageTitle", strconv.Quote(data.Formula.Text)+" in "+data.Scripture.ReadableName)
+	return c.render(ctx, http.StatusOK, "formula", data)
+}
+
+func (c *DheeController) ListMeters(ctx echo.Context) error {
+	data, err := c.es.ListMeters(ctx.Request().Context(), ctx.Param("scriptureName"))
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to list meters")
+	}
+
+	ctx.Set("pageTitle", "Meters in "+data.Scripture.ReadableName)
+	return c.render(ctx, http.StatusOK, "meter_index", data)
+}
+
+func (c *DheeController) GetMeter(ctx echo.Context) error {
+	scriptureName := ctx.Param("scriptureName")
+	page, err := parsePageParam(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := c.es.GetMeter(ctx.Request().Context(), scriptureName, ctx.Param("meter"), page)
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to get meter")
+	}
+
+	ctx.Set("pageTitle", data.Stats.Meter+" in "+data.Scripture.ReadableName)
+	return c.render(ctx, http.StatusOK, "meter", data)
+}
+
+func (c *DheeController) GetAccents(ctx echo.Context) error {
+	data, err := c.es.GetAccents(ctx.Request().Context(), ctx.Param("scriptureName"), ctx.QueryParam("lemma"), ctx.QueryParam("tl"))
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to get accents")
+	}
+
+	title := "Accents in " + data.Scripture.ReadableName
+	if data.Lemma != "" {
+		title = "Accents of " + data.Lemma + " in " + data.Scripture.ReadableName
+	}
+	ctx.Set("pageTitle", title)
+	return c.render(ctx, http.StatusOK, "accents", data)
+}
+
+func (c *DheeController) ListAttributions(ctx echo.Context) error {
+	kind := excerpts.AttributionKind(ctx.Param("kind"))
+	data, err := c.es.ListAttributions(ctx.Request().Context(), ctx.Param("scriptureName"), kind)
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to list attributions")
+	}
+
+	ctx.Set("pageTitle", kind.Title()+" of "+data.Scripture.ReadableName)
+	return c.render(ctx, http.StatusOK, "attribution_index", data)
+}
+
+func (c *DheeController) GetAttribution(ctx echo.Context) error {
+	kind := excerpts.AttributionKind(ctx.Param("kind"))
+	data, err := c.es.GetAttribution(ctx.Request().Context(), ctx.Param("scriptureName"), kind, ctx.Param("name"))
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to get attribution")
+	}
+
+	ctx.Set("pageTitle", "Hymns of "+data.Name+" in "+data.Scripture.ReadableName)
+	return c.render(ctx, http.StatusOK, "attribution", data)
+}
+
+// ExportExcerpts downloads the excerpts of a path, range or verse set as a file.
+func (c *DheeController) ExportExcerpts(ctx echo.Context) error {
+	scriptureName := ctx.Param("scriptureName")
+	scri := c.conf.GetScriptureByName(scriptureName)
+	if scri == nil {
+		return echo.NewHTTPError(http.StatusNotFound, "invalid text name")
+	}
+	opts, err := excerpts.ParseExportOptions(ctx.QueryParam("format"), ctx.QueryParam("fields"), scri)
+	if err != nil {
+		return err
+	}
+
+	path := ctx.QueryParam("path")
+	_, es, err := c.es.Export(ctx.Request().Context(), scriptureName, path, excerpts.MaxExportExcerpts)
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to export excerpts")
+	}
+
+	filename := exportFilenameReplacer.Replace(scriptureName+"_"+path) + "." + opts.Format.FileExtension()
+	ctx.Response().Header().Set(echo.HeaderContentType, opts.Format.ContentType())
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+	ctx.Response().WriteHeader(http.StatusOK)
+	return excerpts.WriteExport(ctx.Response(), scri, es, opts)
 }
 
+// exportFilenameReplacer removes characters of paths which are awkward in file names.
+var exportFilenameReplacer = strings.NewReplacer(",", "_", " ", "", `"`, "", "/", "_", `\`, "_")
+
 func (c *DheeController) SearchScripture(ctx echo.Context) error {
 	query := ctx.QueryParam("query")
 	if query == "" {
@@ -189,16 +325,33 @@ func (c *DheeController) SearchScripture(ctx echo.Context) error {
 
 	scriptures := ctx.QueryParam("scriptures")
 
+	page, err := parsePageParam(ctx)
+	if err != nil {
+		return err
+	}
+
+	var facets []excerpts.FacetFilter
+	for _, f := range ctx.QueryParams()["facet"] {
+		facet, err := excerpts.ParseFacetFilter(f)
+		if err != nil {
+			return err
+		}
+		facets = append(facets, facet)
+	}
+
 	params := excerpts.SearchParams{
 		Q:          query,
 		OriginalQ:  query,
 		Tl:         tl,
 		Mode:       common.SearchMode(modeStr),
 		Scriptures: strings.Split(scriptures, ","),
+		Facets:     facets,
+		Page:       page,
+		Scope:      strings.TrimSpace(ctx.QueryParam("scope")),
 	}
 
-	// Apply rate limiting only for regex mode.
-	if params.Mode == "regex" {
+	// Apply rate limiting only for modes which evaluate user supplied regexes.
+	if params.Mode == "regex" || params.Mode == common.SearchCQL {
 		select {
 		case c.regexLimiter <- struct{}{}:
 			defer func() { <-c.regexLimiter }()
@@ -207,14 +360,48 @@ func (c *DheeController) SearchScripture(ctx echo.Context) error {
 		}
 	}
 
+	if ctx.QueryParam("view") == "kwic" {
+		return c.searchScriptureKWIC(ctx, params)
+	}
+
 	excerpts, err := c.es.Search(ctx.Request().Context(), params)
 	if err != nil {
 		slog.Error("error in scripture search", "err", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search scripture")
+		return common.WrapErrorForResponse(err, "Failed to search scripture")
 	}
 
 	ctx.Set("pageTitle", "Search results for: "+strconv.Quote(query))
-	return ctx.Render(http.StatusOK, "scripture_search", excerpts)
+	return c.render(ctx, http.StatusOK, "scripture_search", excerpts)
+}
+
+// searchScriptureKWIC renders a page of search results as a concordance, or with format=tsv,
+// downloads the concordance of all results.
+func (c *DheeController) searchScriptureKWIC(ctx echo.Context, params excerpts.SearchParams) error {
+	opts, err := excerpts.ParseKWICOptions(ctx.QueryParam("context"), ctx.QueryParam("sort"))
+	if err != nil {
+		return err
+	}
+
+	if ctx.QueryParam("format") == "tsv" {
+		lines, err := c.es.ExportKWIC(ctx.Request().Context(), params, opts)
+		if err != nil {
+			slog.Error("error in concordance export", "err", err)
+			return common.WrapErrorForResponse(err, "Failed to export concordance")
+		}
+		ctx.Response().Header().Set(echo.HeaderContentType, "text/tab-separated-values; charset=utf-8")
+		ctx.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="kwic.tsv"`)
+		ctx.Response().WriteHeader(http.StatusOK)
+		return excerpts.WriteKWICTSV(ctx.Response(), lines)
+	}
+
+	data, err := c.es.KWIC(ctx.Request().Context(), params, opts)
+	if err != nil {
+		slog.Error("error in scripture search", "err", err)
+		return common.WrapErrorForResponse(err, "Failed to search scripture")
+	}
+
+	ctx.Set("pageTitle", "Concordance for: "+strconv.Quote(params.Q))
+	return c.render(ctx, http.StatusOK, "scripture_kwic", data)
 }
 
 func (c *DheeController) GetDictionaryWord(ctx echo.Context) error {
@@ -227,7 +414,7 @@ func (c *DheeController) GetDictionaryWord(ctx echo.Context) error {
 	}
 
 	ctx.Set("pageTitle", strconv.Quote(word)+" in "+entries.Dictionary.ReadableName)
-	return ctx.Render(http.StatusOK, "dictionary_word", entries)
+	return c.render(ctx, http.StatusOK, "dictionary_word", entries)
 }
 
 func (c *DheeController) SearchDictionary(ctx echo.Context) error {
@@ -268,12 +455,18 @@ func (c *DheeController) SearchDictionary(ctx echo.Context) error {
 		modeStr = "prefix"
 	}
 
+	page, err := parsePageParam(ctx)
+	if err != nil {
+		return err
+	}
+
 	params := dictionary.SearchParams{
 		Query:         query,
 		OriginalQuery: query,
 		TextQuery:     textQuery,
 		Mode:          common.SearchMode(modeStr),
 		Tl:            tl,
+		Page:          page,
 	}
 
 	// Apply rate limiting only for regex mode.
@@ -300,7 +493,7 @@ func (c *DheeController) SearchDictionary(ctx echo.Context) error {
 	if preview == "true" {
 		templateName = "dictionary_search.preview"
 	}
-	return ctx.Render(http.StatusOK, templateName, results)
+	return c.render(ctx, http.StatusOK, templateName, results)
 }
 
 func (c *DheeController) SuggestDictionary(ctx echo.Context) error {
@@ -336,3 +529,130 @@ func (c *DheeController) SuggestDictionary(ctx echo.Context) error {
 
 	return ctx.JSON(http.StatusOK, suggestions)
 }
+
+// AnalyzeWord returns the lemmas of the dictionary an inflected form can be made from as
+// JSON.
+func (c *DheeController) AnalyzeWord(ctx echo.Context) error {
+	params := dictionary.AnalysisParams{
+		Word: ctx.QueryParam("word"),
+		Tl:   common.Transliteration(ctx.QueryParam("tl")),
+	}
+	if params.Word == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "word is required")
+	}
+	switch params.Tl {
+	case "":
+		params.Tl = common.TlSLP1
+	case common.TlIAST, common.TlHK, common.TlNagari, common.TlSLP1:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid tl value")
+	}
+
+	results, err := c.ds.Analyze(ctx.Request().Context(), ctx.Param("dictionaryName"), params)
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to analyze word")
+	}
+	return ctx.JSON(http.StatusOK, results)
+}
+
+// SplitSandhi returns the proposed segmentations of the text into words as JSON.
+func (c *DheeController) SplitSandhi(ctx echo.Context) error {
+	params := sandhi.SplitParams{
+		Text: ctx.QueryParam("text"),
+		Tl:   common.Transliteration(ctx.QueryParam("tl")),
+	}
+	switch params.Tl {
+	case "":
+		params.Tl = common.TlSLP1
+	case common.TlIAST, common.TlHK, common.TlNagari, common.TlSLP1:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid tl value")
+	}
+	if limitStr := ctx.QueryParam("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			return common.NewUserVisibleError(http.StatusBadRequest, "limit must be a positive number")
+		}
+		params.Limit = limit
+	}
+
+	results, err := c.ss.Split(ctx.Request().Context(), params)
+	if err != nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, results)
+}
+
+// GetVisualizer renders the chart page, with the chart if any words are given.
+func (c *DheeController) GetVisualizer(ctx echo.Context) error {
+	req, err := parseVisualizationRequest(ctx)
+	if err != nil {
+		return err
+	}
+	data := &visualizer.VisualizerData{Request: req, Scriptures: c.conf.Scriptures}
+	ctx.Set("pageTitle", "Visualizer")
+	if len(req.Words) > 0 {
+		data.Response, err = c.vs.Visualize(ctx.Request().Context(), req)
+		if err != nil {
+			return common.WrapErrorForResponse(err, "Failed to build chart")
+		}
+		ctx.Set("pageTitle", "Visualizer: "+strings.Join(req.Words, ", "))
+	}
+	return c.render(ctx, http.StatusOK, "visualizer", data)
+}
+
+// GetVisualizationData returns the chart data as JSON.
+func (c *DheeController) GetVisualizationData(ctx echo.Context) error {
+	req, err := parseVisualizationRequest(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := c.vs.Visualize(ctx.Request().Context(), req)
+	if err != nil {
+		return common.WrapErrorForResponse(err, "Failed to build chart")
+	}
+	return ctx.JSON(http.StatusOK, resp)
+}
+
+// parseVisualizationRequest reads a VisualizationRequest from the query parameters. Words and
+// sources are comma separated.
+func parseVisualizationRequest(ctx echo.Context) (visualizer.VisualizationRequest, error) {
+	req := visualizer.VisualizationRequest{
+		ChartType:        visualizer.ChartType(ctx.QueryParam("chart")),
+		Tl:               common.Transliteration(ctx.QueryParam("tl")),
+		IncludeWordForms: ctx.QueryParam("forms") == "true",
+		GroupBy:          ctx.QueryParam("groupBy"),
+	}
+	switch req.Tl {
+	case "":
+		req.Tl = common.TlSLP1
+	case common.TlIAST, common.TlHK, common.TlNagari, common.TlSLP1:
+	default:
+		return req, echo.NewHTTPError(http.StatusBadRequest, "invalid tl value")
+	}
+	for _, w := range strings.Split(ctx.QueryParam("words"), ",") {
+		if w = strings.TrimSpace(w); w != "" {
+			req.Words = append(req.Words, w)
+		}
+	}
+	for _, s := range strings.Split(ctx.QueryParam("sources"), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			req.Sources = append(req.Sources, s)
+		}
+	}
+	return req, nil
+}
+
+// parsePageParam returns the 1-based page number from the `page` query parameter, defaulting to 1.
+// Pages beyond common.MaxPage are rejected.
+func parsePageParam(ctx echo.Context) (int, error) {
+	pageStr := ctx.QueryParam("page")
+	if pageStr == "" {
+		return 1, nil
+	}
+	page, err := strconv.Atoi(pageStr)
+	if err != nil || page < 1 || page > common.MaxPage {
+		return 0, common.NewUserVisibleError(http.StatusBadRequest, fmt.Sprintf("page must be a number between 1 and %d", common.MaxPage))
+	}
+	return page, nil
+}
@@ -20,30 +20,7 @@ import (
 
 func StartServer(controller *DheeController, dheeConf *config.DheeConfig, serverConf config.ServerRuntimeConfig) {
 	e := echo.New()
-	e.HTTPErrorHandler = func(err error, c echo.Context) {
-		code := http.StatusInternalServerError
-		msg := http.StatusText(code)
-
-		if he, ok := err.(*echo.HTTPError); ok {
-			code = he.Code
-			if he.Message != nil {
-				msg = fmt.Sprintf("%v", he.Message)
-			}
-		}
-
-		if he, ok := err.(*common.UserVisibleError); ok {
-			code = he.HttpCode
-			msg = he.Error()
-		}
-
-		c.Logger().Error(err)
-
-		if !c.Response().Committed {
-			if renderErr := c.Render(code, "error", msg); renderErr != nil {
-				c.Logger().Error(renderErr)
-			}
-		}
-	}
+	e.HTTPErrorHandler = handleHTTPError
 	e.HideBanner = true
 	e.Pre(middleware.HTTPSRedirect())
 	e.Pre(middleware.RemoveTrailingSlash())
@@ -163,15 +140,7 @@ func StartServer(controller *DheeController, dheeConf *config.DheeConfig, server
 		return c.Blob(http.StatusOK, "image/x-icon", file)
 	})
 
-	e.GET("/", controller.GetHome)
-	e.GET("/scriptures/:scriptureName/excerpts/:path", controller.GetExcerpts).Name = "excerpts"
-	e.GET("/scriptures/:scriptureName/excerpts", controller.GetExcerpts)
-	e.GET("/scriptures/:scriptureName/hierarchy", controller.GetHierarchy)
-	e.GET("/scriptures/:scriptureName/hierarchy/:path", controller.GetHierarchy).Name = "hierarchy"
-	e.GET("/scripture-search", controller.SearchScripture)
-	e.GET("/dictionaries/:dictionaryName/words/:word", controller.GetDictionaryWord)
-	e.GET("/dictionaries/:dictionaryName/search", controller.SearchDictionary)
-	e.GET("/dictionaries/:dictionaryName/suggestions", controller.SuggestDictionary)
+	registerRoutes(e, controller)
 
 	host := serverConf.Addr
 	port := serverConf.Port
@@ -194,3 +163,86 @@ func StartServer(controller *DheeController, dheeConf *config.DheeConfig, server
 		e.Logger.Fatal(e.Start(addr))
 	}
 }
+
+// registerRoutes adds the HTML pages and the JSON API routes to e. API routes must be kept in
+// sync with openapi.json.
+func registerRoutes(e *echo.Echo, controller *DheeController) {
+	e.GET("/", controller.GetHome)
+	e.GET("/scriptures/:scriptureName/excerpts/:path", controller.GetExcerpts).Name = "excerpts"
+	e.GET("/scriptures/:scriptureName/excerpts", controller.GetExcerpts)
+	e.GET("/scriptures/:scriptureName/hierarchy", controller.GetHierarchy)
+	e.GET("/scriptures/:scriptureName/hierarchy/:path", controller.GetHierarchy).Name = "hierarchy"
+	e.GET("/scriptures/:scriptureName/formulas", controller.ListFormulas)
+	e.GET("/scriptures/:scriptureName/formulas/:id", controller.GetFormula)
+	e.GET("/scriptures/:scriptureName/meters", controller.ListMeters)
+	e.GET("/scriptures/:scriptureName/meters/:meter", controller.GetMeter)
+	e.GET("/scriptures/:scriptureName/accents", controller.GetAccents)
+	e.GET("/scriptures/:scriptureName/anukramani/:kind", controller.ListAttributions)
+	e.GET("/scriptures/:scriptureName/anukramani/:kind/:name", controller.GetAttribution)
+	e.GET("/scriptures/:scriptureName/export", controller.ExportExcerpts)
+	e.GET("/scripture-search", controller.SearchScripture)
+	e.GET("/visualizer", controller.GetVisualizer)
+	e.GET("/dictionaries/:dictionaryName/words/:word", controller.GetDictionaryWord)
+	e.GET("/dictionaries/:dictionaryName/search", controller.SearchDictionary)
+	e.GET("/dictionaries/:dictionaryName/suggestions", controller.SuggestDictionary)
+	e.GET("/dictionaries/:dictionaryName/analyze", controller.AnalyzeWord)
+	e.GET("/sandhi", controller.SplitSandhi)
+
+	api := e.Group(APIPrefix)
+	api.GET("/openapi.json", controller.GetOpenAPI)
+	api.GET("/scriptures/:scriptureName/excerpts/:path", controller.GetExcerpts).Name = "api.excerpts"
+	api.GET("/scriptures/:scriptureName/excerpts", controller.GetExcerpts)
+	api.GET("/scriptures/:scriptureName/hierarchy", controller.GetHierarchy)
+	api.GET("/scriptures/:scriptureName/hierarchy/:path", controller.GetHierarchy).Name = "api.hierarchy"
+	api.GET("/scriptures/:scriptureName/formulas", controller.ListFormulas)
+	api.GET("/scriptures/:scriptureName/formulas/:id", controller.GetFormula)
+	api.GET("/scriptures/:scriptureName/meters", controller.ListMeters)
+	api.GET("/scriptures/:scriptureName/meters/:meter", controller.GetMeter)
+	api.GET("/scriptures/:scriptureName/accents", controller.GetAccents)
+	api.GET("/scriptures/:scriptureName/anukramani/:kind", controller.ListAttributions)
+	api.GET("/scriptures/:scriptureName/anukramani/:kind/:name", controller.GetAttribution)
+	api.GET("/scriptures/:scriptureName/export", controller.ExportExcerpts)
+	api.GET("/scripture-search", controller.SearchScripture)
+	api.GET("/visualizer", controller.GetVisualizationData)
+	api.GET("/dictionaries/:dictionaryName/words/:word", controller.GetDictionaryWord)
+	api.GET("/dictionaries/:dictionaryName/search", controller.SearchDictionary)
+	api.GET("/dictionaries/:dictionaryName/suggestions", controller.SuggestDictionary)
+	api.GET("/dictionaries/:dictionaryName/analyze", controller.AnalyzeWord)
+	api.GET("/sandhi", controller.SplitSandhi)
+}
+
+// handleHTTPError renders errors as an error page, or as an APIError for API requests.
+func handleHTTPError(err error, c echo.Context) {
+	code := http.StatusInternalServerError
+	msg := http.StatusText(code)
+	apiMsg := msg
+
+	if he, ok := err.(*echo.HTTPError); ok {
+		code = he.Code
+		if he.Message != nil {
+			msg = fmt.Sprintf("%v", he.Message)
+			apiMsg = msg
+		}
+	}
+
+	if he, ok := err.(*common.UserVisibleError); ok {
+		code = he.HttpCode
+		msg = he.Error()
+		apiMsg = he.Message
+	}
+
+	c.Logger().Error(err)
+
+	if c.Response().Committed {
+		return
+	}
+	if isAPIRequest(c) {
+		if jsonErr := c.JSON(code, APIError{Code: code, Message: apiMsg}); jsonErr != nil {
+			c.Logger().Error(jsonErr)
+		}
+		return
+	}
+	if renderErr := c.Render(code, "error", msg); renderErr != nil {
+		c.Logger().Error(renderErr)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/mahesh-hegde/dhee/app/dictionary"
 	"github.com/mahesh-hegde/dhee/app/excerpts"
 	"github.com/mahesh-hegde/dhee/app/server/templ_template"
+	"github.com/mahesh-hegde/dhee/app/visualizer"
 )
 
 type TemplateRenderer struct {
@@ -54,10 +55,46 @@ func (t *TemplateRenderer) Render(w io.Writer, name string, data interface{}, c
 		if d, ok := data.(*excerpts.ExcerptSearchData); ok {
 			page = templ_template.ScriptureSearch(d)
 		}
+	case "scripture_kwic":
+		if d, ok := data.(*excerpts.KWICData); ok {
+			page = templ_template.ScriptureKWIC(d)
+		}
 	case "excerpts":
 		if d, ok := data.(*excerpts.ExcerptTemplateData); ok {
 			page = templ_template.Excerpts(d)
 		}
+	case "formula_index":
+		if d, ok := data.(*excerpts.FormulaIndexData); ok {
+			page = templ_template.FormulaIndex(d)
+		}
+	case "formula":
+		if d, ok := data.(*excerpts.FormulaData); ok {
+			page = templ_template.Formula(d)
+		}
+	case "meter_index":
+		if d, ok := data.(*excerpts.MeterIndexData); ok {
+			page = templ_template.MeterIndex(d)
+		}
+	case "meter":
+		if d, ok := data.(*excerpts.MeterData); ok {
+			page = templ_template.Meter(d)
+		}
+	case "accents":
+		if d, ok := data.(*excerpts.AccentData); ok {
+			page = templ_template.Accents(d)
+		}
+	case "attribution_index":
+		if d, ok := data.(*excerpts.AttributionIndexData); ok {
+			page = templ_template.AttributionIndex(d)
+		}
+	case "attribution":
+		if d, ok := data.(*excerpts.AttributionData); ok {
+			page = templ_template.Attribution(d)
+		}
+	case "visualizer":
+		if d, ok := data.(*visualizer.VisualizerData); ok {
+			page = templ_template.Visualizer(d)
+		}
 	case "dictionary_search":
 		if d, ok := data.(dictionary.SearchResults); ok {
 			page = templ_template.DictionarySearch(d, false)
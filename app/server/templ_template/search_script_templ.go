@@ -29,7 +29,7 @@ func SearchScript() templ.Component {
 			templ_7745c5c3_Var1 = templ.NopComponent
 		}
 		ctx = templ.ClearChildren(ctx)
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<style>\n\t\t/* Ensure the suggestion area matches the width of the input field */\n\t\t.transliteration-suggestion {\n\t\t\tdisplay: flex;\n\t\t\talign-items: center;\n\t\t\twidth: 100%;\n\t\t\tbox-sizing: border-box;\n\t\t\tpadding: 2px 4px;\n\t\t\tfont-size: 0.9rem;\n\t\t\tcolor: #555;\n\t\t\tposition: relative; /* for tooltip positioning */\n\t\t}\n\t\t.transliteration-suggestion .suggestion-wrapper {\n\t\t\tdisplay: flex;\n\t\t\talign-items: center;\n\t\t\twidth: 100%;\n\t\t}\n\t\t/* The icon is no longer used, but the class is kept for backward compatibility */\n\t\t.transliteration-suggestion .suggestion-icon {\n\t\t\tmargin-right: 4px;\n\t\t\tflex-shrink: 0;\n\t\t}\n\t\t.transliteration-suggestion .iast-text {\n\t\t\tflex-grow: 1;\n\t\t\tuser-select: text;\n\t\t\toverflow: hidden;\n\t\t\twhite-space: nowrap;\n\t\t\ttext-overflow: ellipsis;\n\t\t}\n\t\t.transliteration-suggestion .copy-btn {\n\t\t\tbackground: none;\n\t\t\tborder: none;\n\t\t\tcursor: pointer;\n\t\t\tpadding: 0 4px;\n\t\t\tmargin-left: 4px;\n\t\t\tflex-shrink: 0;\n\t\t\tfont-size: 1rem;\n\t\t\tline-height: 1;\n\t\t\tposition: relative; /* for tooltip positioning */\n\t\t}\n\t\t/* Tooltip styling */\n\t\t.transliteration-suggestion .copy-btn .tooltip {\n\t\t\tposition: absolute;\n\t\t\ttop: 1em;\n\t\t\tleft: 150%;\n\t\t\ttransform: translateX(-50%);\n\t\t\tbackground: #333;\n\t\t\tcolor: #fff;\n\t\t\tpadding: 2px 6px;\n\t\t\tborder-radius: 3px;\n\t\t\tfont-size: 0.75rem;\n\t\t\twhite-space: nowrap;\n\t\t\topacity: 0;\n\t\t\ttransition: opacity 0.2s ease-in-out;\n\t\t\tpointer-events: none;\n\t\t}\n\t\t.transliteration-suggestion .copy-btn .tooltip.show {\n\t\t\topacity: 1;\n\t\t}\n\t</style><script>\n\t\t// Utility function to copy IAST text to clipboard and show a tooltip\n\t\tfunction copyIAST(text, btn) {\n\t\t\tif (!navigator.clipboard) {\n\t\t\t\t// Fallback for older browsers\n\t\t\t\tconst textarea = document.createElement('textarea');\n\t\t\t\ttextarea.value = text;\n\t\t\t\ttextarea.style.position = 'fixed';  // Prevent scrolling to bottom of page in MS Edge.\n\t\t\t\tdocument.body.appendChild(textarea);\n\t\t\t\ttextarea.focus();\n\t\t\t\ttextarea.select();\n\t\t\t\ttry {\n\t\t\t\t\tdocument.execCommand('copy');\n\t\t\t\t} catch (err) {\n\t\t\t\t\tconsole.error('Fallback: Oops, unable to copy', err);\n\t\t\t\t}\n\t\t\t\tdocument.body.removeChild(textarea);\n\t\t\t\tshowCopyTooltip(btn);\n\t\t\t\treturn;\n\t\t\t}\n\t\t\tnavigator.clipboard.writeText(text).then(function() {\n\t\t\t\tshowCopyTooltip(btn);\n\t\t\t}, function(err) {\n\t\t\t\tconsole.error('Async: Could not copy text: ', err);\n\t\t\t});\n\t\t}\n\n\t\t// Show a temporary tooltip next to the copy button\n\t\tfunction showCopyTooltip(btn) {\n\t\t\t// Remove any existing tooltip\n\t\t\tconst existing = btn.querySelector('.tooltip');\n\t\t\tif (existing) {\n\t\t\t\texisting.remove();\n\t\t\t}\n\t\t\tconst tip = document.createElement('span');\n\t\t\ttip.className = 'tooltip';\n\t\t\ttip.textContent = 'Copied!';\n\t\t\tbtn.appendChild(tip);\n\t\t\t// Force reflow to enable transition\n\t\t\tvoid tip.offsetWidth;\n\t\t\ttip.classList.add('show');\n\t\t\t// Hide after 1.5 seconds\n\t\t\tsetTimeout(() => {\n\t\t\t\ttip.classList.remove('show');\n\t\t\t\t// Remove after transition\n\t\t\t\tsetTimeout(() => tip.remove(), 200);\n\t\t\t}, 1500);\n\t\t}\n\n\t\tconst initSearchScript = function() {\n\t\t\twindow.dhee = window.dhee || {};\n\t\t\twindow.dhee.transliterator = new Transliterator({});\n\n\t\t\tconst DHEE_TL_PREF_KEY = 'dhee-tl-pref';\n\n\t\t\tfunction updateSuggestion(form) {\n\t\t\t\tconst input = form.querySelector('.search-input');\n\t\t\t\tconst tlSelect = form.querySelector('.transliteration-select');\n\t\t\t\tconst modeSelect = form.querySelector('.search-mode-select');\n\t\t\t\tconst suggestionEl = form.nextElementSibling?.querySelector('.transliteration-suggestion');\n\n\t\t\t\tif (!input || !tlSelect || !suggestionEl) {\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\tif (modeSelect && modeSelect.value === 'translations') {\n\t\t\t\t\tsuggestionEl.innerHTML = ' ';\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\tconst query = input.value;\n\t\t\t\tconst sourceTl = tlSelect.value;\n\n\t\t\t\tif (query.trim() === '') {\n\t\t\t\t\tsuggestionEl.innerHTML = ' ';\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\tif (sourceTl === TlIAST) {\n\t\t\t\t\tsuggestionEl.innerHTML = ' ';\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\ttry {\n\t\t\t\t\tconst iast = window.dhee.transliterator.convertNormalized(query, sourceTl, TlIAST);\n\t\t\t\t\t// Clear any previous content\n\t\t\t\t\tsuggestionEl.innerHTML = '';\n\n\t\t\t\t\t// Wrapper to hold copy button and text (button first)\n\t\t\t\t\tconst wrapper = document.createElement('div');\n\t\t\t\t\twrapper.className = 'suggestion-wrapper';\n\t\t\t\t\tsuggestionEl.appendChild(wrapper);\n\n\t\t\t\t\t// Copy button using copy emoji, no borders\n\t\t\t\t\tconst btn = document.createElement('button');\n\t\t\t\t\tbtn.type = 'button';\n\t\t\t\t\tbtn.className = 'copy-btn';\n\t\t\t\t\tbtn.textContent = '📋';\n\t\t\t\t\tbtn.addEventListener('click', function() {\n\t\t\t\t\t\tcopyIAST(iast, this);\n\t\t\t\t\t});\n\t\t\t\t\twrapper.appendChild(btn);\n\n\t\t\t\t\t// IAST text span (selectable)\n\t\t\t\t\tconst span = document.createElement('span');\n\t\t\t\t\tspan.className = 'iast-text';\n\t\t\t\t\tspan.textContent = iast;\n\t\t\t\t\twrapper.appendChild(span);\n\t\t\t\t} catch (e) {\n\t\t\t\t\tconsole.error(\"Transliteration failed\", e);\n\t\t\t\t\tsuggestionEl.innerHTML = ' ';\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tfunction updateTlSelects(value) {\n\t\t\t\tdocument.querySelectorAll('.transliteration-select').forEach(function(select) {\n\t\t\t\t\tselect.value = value;\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tfunction onTlChange(event) {\n\t\t\t\tconst newValue = event.target.value;\n\t\t\t\tlocalStorage.setItem(DHEE_TL_PREF_KEY, newValue);\n\t\t\t\tupdateTlSelects(newValue);\n\n\t\t\t\t// When TL changes, all suggestion should be re-evaluated\n\t\t\t\tdocument.querySelectorAll('.dictionary-search-form, .scripture-search-form').forEach(form => {\n\t\t\t\t\tupdateSuggestion(form);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tfunction onModeChange(event) {\n\t\t\t\tconst form = event.target.closest('form');\n\t\t\t\tif (!form) return;\n\t\t\t\tconst tlSelect = form.querySelector('.transliteration-select');\n\t\t\t\tif (!tlSelect) return;\n\n\t\t\t\tif (event.target.value === 'translations') {\n\t\t\t\t\ttlSelect.disabled = true;\n\t\t\t\t} else {\n\t\t\t\t\ttlSelect.disabled = false;\n\t\t\t\t}\n\t\t\t\tupdateSuggestion(form);\n\t\t\t}\n\n\t\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\t\tlet queryPopulated = false;\n\t\t\t\tdocument.querySelectorAll('.search-input').forEach(function(input) {\n\t\t\t\t\tif (input.value.trim() !== '') {\n\t\t\t\t\t\tqueryPopulated = true;\n\t\t\t\t\t}\n\t\t\t\t});\n\n\t\t\t\tif (!queryPopulated) {\n\t\t\t\t\tconst pref = localStorage.getItem(DHEE_TL_PREF_KEY) || 'slp1';\n\t\t\t\t\tupdateTlSelects(pref);\n\t\t\t\t}\n\n\t\t\t\t// Initial suggestions\n\t\t\t\tdocument.querySelectorAll('.dictionary-search-form, .scripture-search-form').forEach(form => {\n\t\t\t\t\tupdateSuggestion(form);\n\t\t\t\t});\n\n\t\t\t\tdocument.querySelectorAll('.transliteration-select').forEach(function(select) {\n\t\t\t\t\tselect.addEventListener('change', onTlChange);\n\t\t\t\t});\n\n\t\t\t\tdocument.querySelectorAll('.search-input').forEach(input => {\n\t\t\t\t\tinput.addEventListener('input', (event) => {\n\t\t\t\t\t\tconst form = event.target.closest('form');\n\t\t\t\t\t\tif (form) {\n\t\t\t\t\t\t\tupdateSuggestion(form);\n\t\t\t\t\t\t}\n\t\t\t\t\t});\n\t\t\t\t});\n\n\t\t\t\tdocument.querySelectorAll('.search-mode-select').forEach(function(select) {\n\t\t\t\t\tselect.addEventListener('change', onModeChange);\n\t\t\t\t\t// Initial check\n\t\t\t\t\tonModeChange({ target: select });\n\t\t\t\t});\n\t\t\t});\n\t\t};\n\t\tif (typeof preInit === \"undefined\") {\n\t\t\tpreInit = [];\n\t\t}\n\t\tpreInit.push(initSearchScript);\n\t</script>")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 1, "<style>\n\t\t/* Ensure the suggestion area matches the width of the input field */\n\t\t.transliteration-suggestion {\n\t\t\tdisplay: flex;\n\t\t\talign-items: center;\n\t\t\twidth: 100%;\n\t\t\tbox-sizing: border-box;\n\t\t\tpadding: 2px 4px;\n\t\t\tfont-size: 0.9rem;\n\t\t\tcolor: #555;\n\t\t\tposition: relative; /* for tooltip positioning */\n\t\t}\n\t\t.transliteration-suggestion .suggestion-wrapper {\n\t\t\tdisplay: flex;\n\t\t\talign-items: center;\n\t\t\twidth: 100%;\n\t\t}\n\t\t/* The icon is no longer used, but the class is kept for backward compatibility */\n\t\t.transliteration-suggestion .suggestion-icon {\n\t\t\tmargin-right: 4px;\n\t\t\tflex-shrink: 0;\n\t\t}\n\t\t.transliteration-suggestion .iast-text {\n\t\t\tflex-grow: 1;\n\t\t\tuser-select: text;\n\t\t\toverflow: hidden;\n\t\t\twhite-space: nowrap;\n\t\t\ttext-overflow: ellipsis;\n\t\t}\n\t\t.transliteration-suggestion .copy-btn {\n\t\t\tbackground: none;\n\t\t\tborder: none;\n\t\t\tcursor: pointer;\n\t\t\tpadding: 0 4px;\n\t\t\tmargin-left: 4px;\n\t\t\tflex-shrink: 0;\n\t\t\tfont-size: 1rem;\n\t\t\tline-height: 1;\n\t\t\tposition: relative; /* for tooltip positioning */\n\t\t}\n\t\t/* Tooltip styling */\n\t\t.transliteration-suggestion .copy-btn .tooltip {\n\t\t\tposition: absolute;\n\t\t\ttop: 1em;\n\t\t\tleft: 150%;\n\t\t\ttransform: translateX(-50%);\n\t\t\tbackground: #333;\n\t\t\tcolor: #fff;\n\t\t\tpadding: 2px 6px;\n\t\t\tborder-radius: 3px;\n\t\t\tfont-size: 0.75rem;\n\t\t\twhite-space: nowrap;\n\t\t\topacity: 0;\n\t\t\ttransition: opacity 0.2s ease-in-out;\n\t\t\tpointer-events: none;\n\t\t}\n\t\t.transliteration-suggestion .copy-btn .tooltip.show {\n\t\t\topacity: 1;\n\t\t}\n\t</style><script>\n\t\t// Utility function to copy IAST text to clipboard and show a tooltip\n\t\tfunction copyIAST(text, btn) {\n\t\t\tif (!navigator.clipboard) {\n\t\t\t\t// Fallback for older browsers\n\t\t\t\tconst textarea = document.createElement('textarea');\n\t\t\t\ttextarea.value = text;\n\t\t\t\ttextarea.style.position = 'fixed';  // Prevent scrolling to bottom of page in MS Edge.\n\t\t\t\tdocument.body.appendChild(textarea);\n\t\t\t\ttextarea.focus();\n\t\t\t\ttextarea.select();\n\t\t\t\ttry {\n\t\t\t\t\tdocument.execCommand('copy');\n\t\t\t\t} catch (err) {\n\t\t\t\t\tconsole.error('Fallback: Oops, unable to copy', err);\n\t\t\t\t}\n\t\t\t\tdocument.body.removeChild(textarea);\n\t\t\t\tshowCopyTooltip(btn);\n\t\t\t\treturn;\n\t\t\t}\n\t\t\tnavigator.clipboard.writeText(text).then(function() {\n\t\t\t\tshowCopyTooltip(btn);\n\t\t\t}, function(err) {\n\t\t\t\tconsole.error('Async: Could not copy text: ', err);\n\t\t\t});\n\t\t}\n\n\t\t// Show a temporary tooltip next to the copy button\n\t\tfunction showCopyTooltip(btn) {\n\t\t\t// Remove any existing tooltip\n\t\t\tconst existing = btn.querySelector('.tooltip');\n\t\t\tif (existing) {\n\t\t\t\texisting.remove();\n\t\t\t}\n\t\t\tconst tip = document.createElement('span');\n\t\t\ttip.className = 'tooltip';\n\t\t\ttip.textContent = 'Copied!';\n\t\t\tbtn.appendChild(tip);\n\t\t\t// Force reflow to enable transition\n\t\t\tvoid tip.offsetWidth;\n\t\t\ttip.classList.add('show');\n\t\t\t// Hide after 1.5 seconds\n\t\t\tsetTimeout(() => {\n\t\t\t\ttip.classList.remove('show');\n\t\t\t\t// Remove after transition\n\t\t\t\tsetTimeout(() => tip.remove(), 200);\n\t\t\t}, 1500);\n\t\t}\n\n\t\tconst initSearchScript = function() {\n\t\t\twindow.dhee = window.dhee || {};\n\t\t\twindow.dhee.transliterator = new Transliterator({});\n\n\t\t\tconst DHEE_TL_PREF_KEY = 'dhee-tl-pref';\n\n\t\t\tfunction updateSuggestion(form) {\n\t\t\t\tconst input = form.querySelector('.search-input');\n\t\t\t\tconst tlSelect = form.querySelector('.transliteration-select');\n\t\t\t\tconst modeSelect = form.querySelector('.search-mode-select');\n\t\t\t\tconst suggestionEl = form.nextElementSibling?.querySelector('.transliteration-suggestion');\n\n\t\t\t\tif (!input || !tlSelect || !suggestionEl) {\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\t// Advanced and morphological queries mix field names and english terms, which should not be transliterated\n\t\t\t\tif (modeSelect && ['translations', 'english', 'query', 'morph', 'cql'].includes(modeSelect.value)) {\n\t\t\t\t\tsuggestionEl.innerHTML = ' ';\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\tconst query = input.value;\n\t\t\t\tconst sourceTl = tlSelect.value;\n\n\t\t\t\tif (query.trim() === '') {\n\t\t\t\t\tsuggestionEl.innerHTML = ' ';\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\tif (sourceTl === TlIAST) {\n\t\t\t\t\tsuggestionEl.innerHTML = ' ';\n\t\t\t\t\treturn;\n\t\t\t\t}\n\n\t\t\t\ttry {\n\t\t\t\t\tconst iast = window.dhee.transliterator.convertNormalized(query, sourceTl, TlIAST);\n\t\t\t\t\t// Clear any previous content\n\t\t\t\t\tsuggestionEl.innerHTML = '';\n\n\t\t\t\t\t// Wrapper to hold copy button and text (button first)\n\t\t\t\t\tconst wrapper = document.createElement('div');\n\t\t\t\t\twrapper.className = 'suggestion-wrapper';\n\t\t\t\t\tsuggestionEl.appendChild(wrapper);\n\n\t\t\t\t\t// Copy button using copy emoji, no borders\n\t\t\t\t\tconst btn = document.createElement('button');\n\t\t\t\t\tbtn.type = 'button';\n\t\t\t\t\tbtn.className = 'copy-btn';\n\t\t\t\t\tbtn.textContent = '📋';\n\t\t\t\t\tbtn.addEventListener('click', function() {\n\t\t\t\t\t\tcopyIAST(iast, this);\n\t\t\t\t\t});\n\t\t\t\t\twrapper.appendChild(btn);\n\n\t\t\t\t\t// IAST text span (selectable)\n\t\t\t\t\tconst span = document.createElement('span');\n\t\t\t\t\tspan.className = 'iast-text';\n\t\t\t\t\tspan.textContent = iast;\n\t\t\t\t\twrapper.appendChild(span);\n\t\t\t\t} catch (e) {\n\t\t\t\t\tconsole.error(\"Transliteration failed\", e);\n\t\t\t\t\tsuggestionEl.innerHTML = ' ';\n\t\t\t\t}\n\t\t\t}\n\n\t\t\tfunction updateTlSelects(value) {\n\t\t\t\tdocument.querySelectorAll('.transliteration-select').forEach(function(select) {\n\t\t\t\t\tselect.value = value;\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tfunction onTlChange(event) {\n\t\t\t\tconst newValue = event.target.value;\n\t\t\t\tlocalStorage.setItem(DHEE_TL_PREF_KEY, newValue);\n\t\t\t\tupdateTlSelects(newValue);\n\n\t\t\t\t// When TL changes, all suggestion should be re-evaluated\n\t\t\t\tdocument.querySelectorAll('.dictionary-search-form, .scripture-search-form').forEach(form => {\n\t\t\t\t\tupdateSuggestion(form);\n\t\t\t\t});\n\t\t\t}\n\n\t\t\tfunction onModeChange(event) {\n\t\t\t\tconst form = event.target.closest('form');\n\t\t\t\tif (!form) return;\n\t\t\t\tconst tlSelect = form.querySelector('.transliteration-select');\n\t\t\t\tif (!tlSelect) return;\n\n\t\t\t\tif (['translations', 'english'].includes(event.target.value)) {\n\t\t\t\t\ttlSelect.disabled = true;\n\t\t\t\t} else {\n\t\t\t\t\ttlSelect.disabled = false;\n\t\t\t\t}\n\t\t\t\tupdateSuggestion(form);\n\t\t\t}\n\n\t\t\tdocument.addEventListener('DOMContentLoaded', function() {\n\t\t\t\tlet queryPopulated = false;\n\t\t\t\tdocument.querySelectorAll('.search-input').forEach(function(input) {\n\t\t\t\t\tif (input.value.trim() !== '') {\n\t\t\t\t\t\tqueryPopulated = true;\n\t\t\t\t\t}\n\t\t\t\t});\n\n\t\t\t\tif (!queryPopulated) {\n\t\t\t\t\tconst pref = localStorage.getItem(DHEE_TL_PREF_KEY) || 'slp1';\n\t\t\t\t\tupdateTlSelects(pref);\n\t\t\t\t}\n\n\t\t\t\t// Initial suggestions\n\t\t\t\tdocument.querySelectorAll('.dictionary-search-form, .scripture-search-form').forEach(form => {\n\t\t\t\t\tupdateSuggestion(form);\n\t\t\t\t});\n\n\t\t\t\tdocument.querySelectorAll('.transliteration-select').forEach(function(select) {\n\t\t\t\t\tselect.addEventListener('change', onTlChange);\n\t\t\t\t});\n\n\t\t\t\tdocument.querySelectorAll('.search-input').forEach(input => {\n\t\t\t\t\tinput.addEventListener('input', (event) => {\n\t\t\t\t\t\tconst form = event.target.closest('form');\n\t\t\t\t\t\tif (form) {\n\t\t\t\t\t\t\tupdateSuggestion(form);\n\t\t\t\t\t\t}\n\t\t\t\t\t});\n\t\t\t\t});\n\n\t\t\t\tdocument.querySelectorAll('.search-mode-select').forEach(function(select) {\n\t\t\t\t\tselect.addEventListener('change', onModeChange);\n\t\t\t\t\t// Initial check\n\t\t\t\t\tonModeChange({ target: select });\n\t\t\t\t});\n\t\t\t});\n\t\t};\n\t\tif (typeof preInit === \"undefined\") {\n\t\t\tpreInit = [];\n\t\t}\n\t\tpreInit.push(initSearchScript);\n\t</script>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
@@ -1,5 +1,10 @@
 package visualizer
 
+import (
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/config"
+)
+
 type ChartType string
 
 const (
@@ -8,32 +13,57 @@ const (
 )
 
 type VisualizationRequest struct {
-	ChartType        ChartType
-	Words            []string
-	IncludeWordForms bool   // Include all forms of the root word
-	GroupBy          string // one of the strings common to hierarchy of all texts, author, text or meter
-	Sources          []string
+	ChartType        ChartType              `json:"chart_type"`
+	Words            []string               `json:"words"`
+	Tl               common.Transliteration `json:"tl"`                 // Transliteration scheme of Words
+	IncludeWordForms bool                   `json:"include_word_forms"` // Include all forms of the root word
+	// one of the strings common to hierarchy of all texts, author, addressee, text or meter,
+	// or a grammatical feature such as case or tense
+	GroupBy string   `json:"group_by"`
+	Sources []string `json:"sources"`
 }
 
 type IncludedWord struct {
-	Word  string
-	Forms []string
+	Word  string   `json:"word"`
+	Forms []string `json:"forms"`
+	Color string   `json:"color"`
 }
 
+// Point is a single occurrence of a word, for scatter charts.
 type Point struct {
-	X          string
-	Y          string
-	Color      string
-	SourceName string
-	Path       []string
+	X          string   `json:"x"`
+	Y          string   `json:"y"`
+	Color      string   `json:"color"`
+	SourceName string   `json:"source_name"`
+	Path       []string `json:"path"`
+}
+
+// Series holds the counts of a word for each group of AxisX, for bar charts.
+type Series struct {
+	Word   string `json:"word"`
+	Color  string `json:"color"`
+	Counts []int  `json:"counts"`
+	// Occurrences per thousand glossed words of the group, to compare groups of different sizes.
+	PerThousand []float64 `json:"per_thousand"`
 }
 
-// Temporary draft: to be refined based on what kind of data is supported by d3.js / uplot to draw
-// charts.
 type VisualizationResponse struct {
-	Words  []IncludedWord
-	AxisX  []string
-	AxisY  []string
-	Points []Point
-	Bars   map[string]string
+	ChartType ChartType      `json:"chart_type"`
+	GroupBy   string         `json:"group_by"`
+	Words     []IncludedWord `json:"words"`
+	// Groups in display order
+	AxisX []string `json:"axis_x"`
+	// Words, as the categories of scatter charts
+	AxisY  []string `json:"axis_y"`
+	Points []Point  `json:"points"`
+	Series []Series `json:"series"`
+	// Set when there were more occurrences than points returned
+	PointsTruncated bool `json:"points_truncated"`
+}
+
+// VisualizerData is the data of the visualizer page. Response is nil until words are given.
+type VisualizerData struct {
+	Request    VisualizationRequest   `json:"request"`
+	Response   *VisualizationResponse `json:"response"`
+	Scriptures []config.ScriptureDefn `json:"scriptures"`
 }
@@ -0,0 +1,188 @@
+package visualizer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mahesh-hegde/dhee/app/config"
+)
+
+// SQLiteVisualizerStore reads the glossings index maintained by the excerpts store.
+type SQLiteVisualizerStore struct {
+	db   *sql.DB
+	conf *config.DheeConfig
+}
+
+func NewSQLiteVisualizerStore(db *sql.DB, conf *config.DheeConfig) *SQLiteVisualizerStore {
+	return &SQLiteVisualizerStore{db: db, conf: conf}
+}
+
+var _ VisualizerStore = &SQLiteVisualizerStore{}
+
+// featureColumns maps grammatical features to dhee_glossings columns.
+var featureColumns = map[string]string{
+	"case":   "nominal_case",
+	"number": "number",
+	"gender": "gender",
+	"tense":  "tense",
+	"voice":  "voice",
+	"person": "person",
+	"mood":   "mood",
+}
+
+// tokenSQL is the FROM and WHERE clauses selecting the glossed tokens of some scriptures,
+// with the expression computing the group of each token.
+type tokenSQL struct {
+	groupExpr string
+	from      string
+	where     []string
+	args      []any
+}
+
+func newTokenSQL(scriptures []string, g Grouping) (*tokenSQL, error) {
+	q := &tokenSQL{
+		from: "dhee_glossings AS g JOIN dhee_excerpts AS ex ON ex.rowid = g.excerpt_rowid",
+	}
+	switch g.Kind {
+	case GroupHierarchy:
+		// sort_index components are 5 digits separated by '.'
+		q.groupExpr = fmt.Sprintf("substr(ex.sort_index, 1, %d)", 6*(g.Level+1)-1)
+	case GroupFacet:
+		q.from += " LEFT JOIN dhee_excerpt_facets AS f ON f.excerpt_rowid = ex.rowid AND f.facet = ?"
+		q.args = append(q.args, g.Name)
+		q.groupExpr = "COALESCE(f.value, '')"
+	case GroupText:
+		q.groupExpr = "ex.scripture"
+	case GroupFeature:
+		column, ok := featureColumns[g.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown grammatical feature %q", g.Name)
+		}
+		q.groupExpr = "COALESCE(g." + column + ", '')"
+	default:
+		return nil, fmt.Errorf("unknown grouping %d", g.Kind)
+	}
+	q.in("ex.scripture", scriptures)
+	return q, nil
+}
+
+func (q *tokenSQL) in(column string, values []string) {
+	placeholders := make([]string, len(values))
+	for i, v := range values {
+		placeholders[i] = "?"
+		q.args = append(q.args, v)
+	}
+	q.where = append(q.where, column+" IN ("+strings.Join(placeholders, ", ")+")")
+}
+
+func (q *tokenSQL) word(wq WordQuery) {
+	if wq.ByLemma {
+		q.in("g.lemma", wq.Values)
+	} else {
+		q.in("g.surface", wq.Values)
+	}
+}
+
+func (q *tokenSQL) query(selectExpr string, suffix string) string {
+	return "SELECT " + selectExpr + " FROM " + q.from + " WHERE " + strings.Join(q.where, " AND ") + " " + suffix
+}
+
+func (s *SQLiteVisualizerStore) Forms(ctx context.Context, scriptures []string, word string) ([]string, []string, error) {
+	q, err := newTokenSQL(scriptures, Grouping{Kind: GroupText})
+	if err != nil {
+		return nil, nil, err
+	}
+	q.where = append(q.where, "g.lemma != ''", "(g.lemma = ? OR g.surface = ?)")
+	q.args = append(q.args, word, word)
+	lemmas, err := s.queryStrings(ctx, q.query("DISTINCT g.lemma", "ORDER BY g.lemma"), q.args)
+	if err != nil || len(lemmas) == 0 {
+		return nil, nil, err
+	}
+
+	q, _ = newTokenSQL(scriptures, Grouping{Kind: GroupText})
+	q.in("g.lemma", lemmas)
+	q.where = append(q.where, "g.surface != ''")
+	forms, err := s.queryStrings(ctx, q.query("g.surface", "GROUP BY g.surface ORDER BY COUNT(*) DESC, g.surface"), q.args)
+	if err != nil {
+		return nil, nil, err
+	}
+	return lemmas, forms, nil
+}
+
+func (s *SQLiteVisualizerStore) queryStrings(ctx context.Context, query string, args []any) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite query failed: %w", err)
+	}
+	defer rows.Close()
+	var values []string
+	for rows.Next() {
+		var v string
+		if err := rows.Scan(&v); err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, rows.Err()
+}
+
+func (s *SQLiteVisualizerStore) Counts(ctx context.Context, scriptures []string, wq WordQuery, g Grouping) ([]GroupCount, error) {
+	q, err := newTokenSQL(scriptures, g)
+	if err != nil {
+		return nil, err
+	}
+	q.word(wq)
+	return s.queryCounts(ctx, q.query("ex.scripture, "+q.groupExpr+", COUNT(*)", "GROUP BY 1, 2"), q.args)
+}
+
+func (s *SQLiteVisualizerStore) GroupSizes(ctx context.Context, scriptures []string, g Grouping) ([]GroupCount, error) {
+	q, err := newTokenSQL(scriptures, g)
+	if err != nil {
+		return nil, err
+	}
+	return s.queryCounts(ctx, q.query("ex.scripture, "+q.groupExpr+", COUNT(*)", "GROUP BY 1, 2"), q.args)
+}
+
+func (s *SQLiteVisualizerStore) queryCounts(ctx context.Context, query string, args []any) ([]GroupCount, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite count query failed: %w", err)
+	}
+	defer rows.Close()
+	var counts []GroupCount
+	for rows.Next() {
+		var c GroupCount
+		if err := rows.Scan(&c.Scripture, &c.Group, &c.Count); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+	return counts, rows.Err()
+}
+
+func (s *SQLiteVisualizerStore) Occurrences(ctx context.Context, scriptures []string, wq WordQuery, g Grouping, limit int) ([]Occurrence, error) {
+	q, err := newTokenSQL(scriptures, g)
+	if err != nil {
+		return nil, err
+	}
+	q.word(wq)
+	q.args = append(q.args, limit)
+	query := q.query("ex.scripture, ex.view_index, COALESCE(g.surface, ''), "+q.groupExpr,
+		"ORDER BY ex.scripture, ex.sort_index, g.line, g.position LIMIT ?")
+	rows, err := s.db.QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("sqlite occurrences query failed: %w", err)
+	}
+	defer rows.Close()
+	var occurrences []Occurrence
+	for rows.Next() {
+		var o Occurrence
+		if err := rows.Scan(&o.Scripture, &o.Path, &o.Surface, &o.Group); err != nil {
+			return nil, err
+		}
+		occurrences = append(occurrences, o)
+	}
+	return occurrences, rows.Err()
+}
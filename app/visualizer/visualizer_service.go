@@ -0,0 +1,347 @@
+package visualizer
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mahesh-hegde/dhee/app/common"
+	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/mahesh-hegde/dhee/app/transliteration"
+	"github.com/patrickmn/go-cache"
+)
+
+const (
+	MaxWords = 10
+	// maxGroups bounds the groups of non-hierarchy groupings, the rest are summed up as OthersGroup.
+	maxGroups = 30
+	// maxFilledGroups bounds the number of hierarchy groups shown when they have no occurrences.
+	maxFilledGroups = 200
+	// maxPoints bounds the number of occurrences returned for scatter charts.
+	maxPoints = 5000
+
+	OthersGroup = "(others)"
+	NoneGroup   = "(none)"
+)
+
+// GroupByFacets and GroupByFeatures are the non-hierarchy values accepted for GroupBy.
+var (
+	GroupByFacets   = []string{"author", "addressee", "meter", "text"}
+	GroupByFeatures = []string{"case", "number", "gender", "tense", "voice", "person", "mood"}
+)
+
+// palette is the d3 category10 color scheme.
+var palette = []string{
+	"#1f77b4", "#ff7f0e", "#2ca02c", "#d62728", "#9467bd",
+	"#8c564b", "#e377c2", "#7f7f7f", "#bcbd22", "#17becf",
+}
+
+type VisualizerService struct {
+	store          VisualizerStore
+	conf           *config.DheeConfig
+	transliterator *transliteration.Transliterator
+	sizeCache      *cache.Cache
+}
+
+func NewVisualizerService(store VisualizerStore, conf *config.DheeConfig, transliterator *transliteration.Transliterator) *VisualizerService {
+	return &VisualizerService{
+		store:          store,
+		conf:           conf,
+		transliterator: transliterator,
+		sizeCache:      cache.New(2*time.Hour, 10*time.Minute),
+	}
+}
+
+func newRequestError(format string, args ...any) error {
+	return common.NewUserVisibleError(http.StatusBadRequest, fmt.Sprintf(format, args...))
+}
+
+// ParseGrouping resolves groupBy against the hierarchies of the sources. Hierarchy level names
+// are matched case-insensitively and must be at the same level in all sources. An empty groupBy
+// selects the top level of the hierarchy.
+func ParseGrouping(groupBy string, sources []*config.ScriptureDefn) (Grouping, error) {
+	name := strings.ToLower(strings.TrimSpace(groupBy))
+	if name == "" {
+		return Grouping{Kind: GroupHierarchy}, nil
+	}
+	switch {
+	case name == "text":
+		return Grouping{Kind: GroupText}, nil
+	case slices.Contains(GroupByFacets, name):
+		return Grouping{Kind: GroupFacet, Name: name}, nil
+	case slices.Contains(GroupByFeatures, name):
+		return Grouping{Kind: GroupFeature, Name: name}, nil
+	}
+	level := -1
+	for _, s := range sources {
+		idx := slices.IndexFunc(s.Hierarchy, func(h string) bool { return strings.ToLower(h) == name })
+		if idx < 0 || (level >= 0 && idx != level) {
+			level = -1
+			break
+		}
+		level = idx
+	}
+	if level < 0 {
+		return Grouping{}, newRequestError("cannot group by %q, expected a hierarchy level common to all sources, or one of %s",
+			groupBy, strings.Join(append(slices.Clone(GroupByFacets), GroupByFeatures...), ", "))
+	}
+	return Grouping{Kind: GroupHierarchy, Level: level}, nil
+}
+
+// groupKey identifies a group across scriptures, and sorts in display order for hierarchies.
+type groupKey struct {
+	scripture string
+	group     string
+}
+
+// groupLabel returns the display name of a group. Hierarchy groups are readable paths, prefixed
+// by the scripture name when there are several sources.
+func groupLabel(k groupKey, g Grouping, multipleSources bool) string {
+	switch g.Kind {
+	case GroupHierarchy:
+		parts := strings.Split(k.group, ".")
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err == nil {
+				parts[i] = strconv.Itoa(n)
+			}
+		}
+		path := strings.Join(parts, ".")
+		if multipleSources {
+			return k.scripture + " " + path
+		}
+		return path
+	case GroupText:
+		return k.scripture
+	}
+	if k.group == "" {
+		return NoneGroup
+	}
+	return k.group
+}
+
+// Visualize counts the occurrences of the requested words in each group, and for scatter
+// charts, returns the individual occurrences.
+func (s *VisualizerService) Visualize(ctx context.Context, req VisualizationRequest) (*VisualizationResponse, error) {
+	chartType := req.ChartType
+	if chartType == "" {
+		chartType = Bar
+	}
+	if chartType != Bar && chartType != Scatter {
+		return nil, newRequestError("invalid chart type %q, expected bar or scatter", req.ChartType)
+	}
+
+	var sources []*config.ScriptureDefn
+	for _, name := range req.Sources {
+		scripture := s.conf.GetScriptureByName(name)
+		if scripture == nil {
+			return nil, newRequestError("unknown source %q", name)
+		}
+		sources = append(sources, scripture)
+	}
+	if len(sources) == 0 {
+		for i := range s.conf.Scriptures {
+			sources = append(sources, &s.conf.Scriptures[i])
+		}
+	}
+	sourceNames := make([]string, len(sources))
+	for i, src := range sources {
+		sourceNames[i] = src.Name
+	}
+
+	grouping, err := ParseGrouping(req.GroupBy, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	tl := req.Tl
+	if tl == "" {
+		tl = common.TlIAST
+	}
+	var words []string
+	for _, w := range req.Words {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		iast, err := s.transliterator.Convert(w, tl, common.TlIAST)
+		if err != nil {
+			slog.Warn("transliteration failed for visualizer word", "word", w, "err", err)
+			iast = w
+		}
+		if req.IncludeWordForms {
+			iast = common.NormalizeLemma(iast)
+		} else {
+			iast = common.NormalizeSurface(iast)
+		}
+		if !slices.Contains(words, iast) {
+			words = append(words, iast)
+		}
+	}
+	if len(words) == 0 {
+		return nil, newRequestError("at least one word is required")
+	}
+	if len(words) > MaxWords {
+		return nil, newRequestError("at most %d words can be compared", MaxWords)
+	}
+
+	resp := &VisualizationResponse{ChartType: chartType, GroupBy: req.GroupBy}
+	if resp.GroupBy == "" {
+		resp.GroupBy = sources[0].Hierarchy[0]
+	}
+	queries := make([]WordQuery, len(words))
+	wordCounts := make([]map[groupKey]int, len(words))
+	totals := make(map[groupKey]int)
+	for i, w := range words {
+		included := IncludedWord{Word: w, Forms: []string{w}, Color: palette[i%len(palette)]}
+		queries[i] = WordQuery{Values: []string{w}}
+		if req.IncludeWordForms {
+			lemmas, forms, err := s.store.Forms(ctx, sourceNames, w)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find word forms: %w", err)
+			}
+			queries[i] = WordQuery{ByLemma: true, Values: []string{w}}
+			if len(lemmas) > 0 {
+				queries[i].Values = lemmas
+				included.Forms = forms
+			}
+		}
+		counts, err := s.store.Counts(ctx, sourceNames, queries[i], grouping)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count occurrences: %w", err)
+		}
+		wordCounts[i] = make(map[groupKey]int)
+		for _, c := range counts {
+			k := groupKey{c.Scripture, c.Group}
+			wordCounts[i][k] += c.Count
+			totals[k] += c.Count
+		}
+		resp.Words = append(resp.Words, included)
+		resp.AxisY = append(resp.AxisY, w)
+	}
+
+	sizes, err := s.groupSizes(ctx, sourceNames, grouping)
+	if err != nil {
+		return nil, err
+	}
+
+	// hierarchy groups without occurrences are shown too, unless there are too many of them
+	if (grouping.Kind == GroupHierarchy || grouping.Kind == GroupText) && len(sizes) <= maxFilledGroups {
+		for k := range sizes {
+			totals[k] += 0
+		}
+	}
+
+	// order the groups: hierarchy groups in text order, others by decreasing frequency
+	var keys []groupKey
+	for k := range totals {
+		keys = append(keys, k)
+	}
+	if grouping.Kind == GroupHierarchy || grouping.Kind == GroupText {
+		sort.Slice(keys, func(i, j int) bool {
+			si, sj := slices.Index(sourceNames, keys[i].scripture), slices.Index(sourceNames, keys[j].scripture)
+			if si != sj {
+				return si < sj
+			}
+			return keys[i].group < keys[j].group
+		})
+	} else {
+		sort.Slice(keys, func(i, j int) bool {
+			if totals[keys[i]] != totals[keys[j]] {
+				return totals[keys[i]] > totals[keys[j]]
+			}
+			return keys[i].group < keys[j].group
+		})
+	}
+
+	multipleSources := len(sources) > 1
+	labelIndex := make(map[groupKey]int)
+	groupSizes := []int{}
+	for _, k := range keys {
+		label := groupLabel(k, grouping, multipleSources)
+		if grouping.Kind != GroupHierarchy && len(resp.AxisX) >= maxGroups {
+			label = OthersGroup
+		}
+		idx := slices.Index(resp.AxisX, label)
+		if idx < 0 {
+			idx = len(resp.AxisX)
+			resp.AxisX = append(resp.AxisX, label)
+			groupSizes = append(groupSizes, 0)
+		}
+		labelIndex[k] = idx
+		groupSizes[idx] += sizes[k]
+	}
+
+	for i, w := range resp.Words {
+		series := Series{
+			Word:        w.Word,
+			Color:       w.Color,
+			Counts:      make([]int, len(resp.AxisX)),
+			PerThousand: make([]float64, len(resp.AxisX)),
+		}
+		for k, n := range wordCounts[i] {
+			series.Counts[labelIndex[k]] += n
+		}
+		for j, n := range series.Counts {
+			if groupSizes[j] > 0 {
+				series.PerThousand[j] = float64(n) * 1000 / float64(groupSizes[j])
+			}
+		}
+		resp.Series = append(resp.Series, series)
+	}
+
+	if chartType == Scatter {
+		for i, w := range resp.Words {
+			total := 0
+			for _, n := range wordCounts[i] {
+				total += n
+			}
+			limit := maxPoints - len(resp.Points)
+			if total > limit {
+				resp.PointsTruncated = true
+			}
+			if limit <= 0 || total == 0 {
+				continue
+			}
+			occurrences, err := s.store.Occurrences(ctx, sourceNames, queries[i], grouping, limit)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get occurrences: %w", err)
+			}
+			for _, o := range occurrences {
+				resp.Points = append(resp.Points, Point{
+					X:          resp.AxisX[labelIndex[groupKey{o.Scripture, o.Group}]],
+					Y:          w.Word,
+					Color:      w.Color,
+					SourceName: o.Scripture,
+					Path:       strings.Split(o.Path, "."),
+				})
+			}
+		}
+	}
+	return resp, nil
+}
+
+// groupSizes returns the number of glossed tokens in each group, which do not change
+// while the server is running.
+func (s *VisualizerService) groupSizes(ctx context.Context, sources []string, g Grouping) (map[groupKey]int, error) {
+	cacheKey := fmt.Sprintf("%s/%d/%d/%s", strings.Join(sources, ","), g.Kind, g.Level, g.Name)
+	if cached, ok := s.sizeCache.Get(cacheKey); ok {
+		return cached.(map[groupKey]int), nil
+	}
+	counts, err := s.store.GroupSizes(ctx, sources, g)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count group sizes: %w", err)
+	}
+	sizes := make(map[groupKey]int)
+	for _, c := range counts {
+		sizes[groupKey{c.Scripture, c.Group}] += c.Count
+	}
+	s.sizeCache.Set(cacheKey, sizes, cache.DefaultExpiration)
+	return sizes, nil
+}
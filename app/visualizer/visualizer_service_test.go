@@ -0,0 +1,40 @@
+package visualizer
+
+import (
+	"testing"
+
+	"github.com/mahesh-hegde/dhee/app/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGrouping(t *testing.T) {
+	rv := &config.ScriptureDefn{Name: "rigveda", Hierarchy: []string{"Mandala", "Sukta", "Verse"}}
+	av := &config.ScriptureDefn{Name: "atharvaveda", Hierarchy: []string{"Kanda", "Sukta", "Verse"}}
+
+	g, err := ParseGrouping("sukta", []*config.ScriptureDefn{rv, av})
+	assert.NoError(t, err)
+	assert.Equal(t, Grouping{Kind: GroupHierarchy, Level: 1}, g)
+
+	_, err = ParseGrouping("Mandala", []*config.ScriptureDefn{rv, av})
+	assert.Error(t, err)
+
+	g, err = ParseGrouping("", []*config.ScriptureDefn{av})
+	assert.NoError(t, err)
+	assert.Equal(t, Grouping{Kind: GroupHierarchy}, g)
+
+	g, err = ParseGrouping("Case", []*config.ScriptureDefn{rv})
+	assert.NoError(t, err)
+	assert.Equal(t, Grouping{Kind: GroupFeature, Name: "case"}, g)
+
+	g, err = ParseGrouping("author", []*config.ScriptureDefn{rv})
+	assert.NoError(t, err)
+	assert.Equal(t, Grouping{Kind: GroupFacet, Name: "author"}, g)
+}
+
+func TestGroupLabel(t *testing.T) {
+	hier := Grouping{Kind: GroupHierarchy, Level: 1}
+	assert.Equal(t, "1.32", groupLabel(groupKey{"rigveda", "00001.00032"}, hier, false))
+	assert.Equal(t, "rigveda 10.2", groupLabel(groupKey{"rigveda", "00010.00002"}, hier, true))
+	assert.Equal(t, NoneGroup, groupLabel(groupKey{"rigveda", ""}, Grouping{Kind: GroupFacet, Name: "author"}, false))
+	assert.Equal(t, "rigveda", groupLabel(groupKey{"rigveda", "rigveda"}, Grouping{Kind: GroupText}, true))
+}
@@ -0,0 +1,60 @@
+package visualizer
+
+import "context"
+
+// GroupKind is the kind of attribute occurrences are grouped by.
+type GroupKind int
+
+const (
+	// GroupHierarchy groups by a level of the scripture hierarchy, eg: the mandala.
+	GroupHierarchy GroupKind = iota
+	// GroupFacet groups by an excerpt facet, eg: author.
+	GroupFacet
+	// GroupText groups by the scripture.
+	GroupText
+	// GroupFeature groups by a grammatical feature of the token, eg: case.
+	GroupFeature
+)
+
+// Grouping is the parsed form of VisualizationRequest.GroupBy.
+type Grouping struct {
+	Kind GroupKind
+	// Level is the 0-based hierarchy level for GroupHierarchy.
+	Level int
+	// Name is the facet name for GroupFacet, or the feature name for GroupFeature.
+	Name string
+}
+
+// WordQuery selects the tokens of a word, by normalized surface forms or lemmas.
+type WordQuery struct {
+	ByLemma bool
+	Values  []string
+}
+
+// GroupCount is the number of tokens in a group. Group is the raw value of the grouping
+// attribute, for hierarchy levels it is the prefix of the sort index.
+type GroupCount struct {
+	Scripture string
+	Group     string
+	Count     int
+}
+
+// Occurrence is a single token matching a WordQuery.
+type Occurrence struct {
+	Scripture string
+	Path      string
+	Surface   string
+	Group     string
+}
+
+type VisualizerStore interface {
+	// Forms returns the lemmas of the tokens whose lemma or surface is word, and all the
+	// attested surfaces of those lemmas, most frequent first.
+	Forms(ctx context.Context, scriptures []string, word string) (lemmas []string, forms []string, err error)
+	// Counts counts the tokens matching the query in each group.
+	Counts(ctx context.Context, scriptures []string, q WordQuery, g Grouping) ([]GroupCount, error)
+	// Occurrences returns up to limit tokens matching the query, in text order.
+	Occurrences(ctx context.Context, scriptures []string, q WordQuery, g Grouping, limit int) ([]Occurrence, error)
+	// GroupSizes counts all glossed tokens in each group.
+	GroupSizes(ctx context.Context, scriptures []string, g Grouping) ([]GroupCount, error)
+}
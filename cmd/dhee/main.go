@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -9,14 +10,19 @@ import (
 	"path"
 	"runtime"
 	"runtime/pprof"
+	"strings"
 
 	"github.com/blevesearch/bleve/v2"
+	"github.com/mahesh-hegde/dhee/app/common"
 	"github.com/mahesh-hegde/dhee/app/config"
 	"github.com/mahesh-hegde/dhee/app/dictionary"
 	"github.com/mahesh-hegde/dhee/app/docstore"
 	"github.com/mahesh-hegde/dhee/app/excerpts"
+	"github.com/mahesh-hegde/dhee/app/inflection"
+	"github.com/mahesh-hegde/dhee/app/sandhi"
 	"github.com/mahesh-hegde/dhee/app/server"
 	"github.com/mahesh-hegde/dhee/app/transliteration"
+	"github.com/mahesh-hegde/dhee/app/visualizer"
 	"github.com/spf13/pflag"
 )
 
@@ -48,6 +54,10 @@ func main() {
 		runIndex()
 	case "stats":
 		runStats()
+	case "cql":
+		runCQL()
+	case "export":
+		runExport()
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", command)
 		printUsage()
@@ -63,6 +73,8 @@ func printUsage() {
 	fmt.Fprintln(os.Stderr, "  server        Start the dhee server")
 	fmt.Fprintln(os.Stderr, "  index         Build the search index in advance")
 	fmt.Fprintln(os.Stderr, "  stats         Show index statistics")
+	fmt.Fprintln(os.Stderr, "  cql           Search glossing token sequences with a CQL pattern")
+	fmt.Fprintln(os.Stderr, "  export        Export verses as plain text, CSV, Markdown or TEI")
 }
 
 func readConfig(dataDir string) *config.DheeConfig {
@@ -85,10 +97,14 @@ func readConfig(dataDir string) *config.DheeConfig {
 
 func runPreprocess() {
 	flags := pflag.NewFlagSet("preprocess", pflag.ExitOnError)
-	var input, output, embeddingsFile string
+	var input, output, embeddingsFile, anukramaniFile string
+	var dictionaries []string
 	flags.StringVarP(&input, "input", "i", "", "Input directory (required)")
 	flags.StringVarP(&output, "output", "o", "", "Output directory (required)")
 	flags.StringVar(&embeddingsFile, "embeddings-file", "", "Path to embeddings JSONL file (optional)")
+	flags.StringVar(&anukramaniFile, "anukramani-file", "", "Path to anukramani JSONL file of seers, deities and meters of hymns (optional)")
+	flags.StringSliceVar(&dictionaries, "dictionaries", []string{"mw"},
+		"Cologne dictionaries to convert from {code}.xml to {code}.jsonl: mw, gra, md or ap90")
 
 	flags.Parse(os.Args[2:])
 
@@ -97,15 +113,21 @@ func runPreprocess() {
 		os.Exit(1)
 	}
 
-	mwInput := path.Join(input, "mw.xml")
-	mwOutput := path.Join(output, "mw.jsonl")
-
-	if err := dictionary.ConvertMonierWilliamsDictionary(mwInput, mwOutput); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	for _, code := range dictionaries {
+		parser, ok := dictionary.CologneParsers[code]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: unknown dictionary %q\n", code)
+			os.Exit(1)
+		}
+		dictInput := path.Join(input, code+".xml")
+		dictOutput := path.Join(output, code+".jsonl")
+		if err := dictionary.ConvertCologneDictionary(parser, dictInput, dictOutput); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
-	if err := excerpts.PreprocessRvDataset(path.Join(input, "tei"), output, embeddingsFile); err != nil {
+	if err := excerpts.PreprocessRvDataset(path.Join(input, "tei"), output, embeddingsFile, anukramaniFile); err != nil {
 		slog.Error("error when preprocessing rigveda dataset", "error", err)
 		os.Exit(1)
 	}
@@ -190,6 +212,9 @@ func runServer() {
 	conf := readConfig(dataDir)
 	var dictStore dictionary.DictStore
 	var excerptStore excerpts.ExcerptStore
+	var visualizerStore visualizer.VisualizerStore
+	var lexiconStore sandhi.LexiconStore
+	var formStore inflection.FormStore
 	var err error
 
 	switch store {
@@ -199,8 +224,17 @@ func runServer() {
 			slog.Error("error while initializing SQLite DB", "err", err)
 			os.Exit(1)
 		}
-		dictStore = dictionary.NewSQLiteDictStore(db, conf)
+		sqliteDictStore := dictionary.NewSQLiteDictStore(db, conf)
+		go func() {
+			if err := sqliteDictStore.BuildFuzzyIndexes(context.Background()); err != nil {
+				slog.Error("error while building fuzzy dictionary indexes", "err", err)
+			}
+		}()
+		dictStore = sqliteDictStore
 		excerptStore = excerpts.NewSQLiteExcerptStore(db, conf)
+		visualizerStore = visualizer.NewSQLiteVisualizerStore(db, conf)
+		lexiconStore = sandhi.NewSQLiteLexiconStore(db, conf)
+		formStore = inflection.NewSQLiteFormStore(db, conf)
 	default:
 		slog.Error("unknown store type", "store", store)
 		os.Exit(1)
@@ -214,7 +248,7 @@ func runServer() {
 		os.Exit(1)
 	}
 
-	controller := server.NewDheeController(dictStore, excerptStore, conf, &serverConf, transliterator)
+	controller := server.NewDheeController(dictStore, excerptStore, visualizerStore, lexiconStore, formStore, conf, &serverConf, transliterator)
 	server.StartServer(controller, conf, serverConf)
 }
 
@@ -248,16 +282,24 @@ func runIndex() {
 
 func runStats() {
 	flags := pflag.NewFlagSet("stats", pflag.ExitOnError)
-	var dataDir, store string
+	var dataDir, store, scripture, scope string
 	flags.StringVarP(&dataDir, "data-dir", "d", "",
 		"data directory to read config.json and data JSONL files")
 	flags.StringVar(&store, "store", "sqlite", "storage backend to use (bleve or sqlite)")
+	flags.StringVarP(&scripture, "scripture", "s", "",
+		"print statistics of this scripture and its verse sets (sqlite only)")
+	flags.StringVar(&scope, "scope", "",
+		"restrict scripture statistics to paths, ranges or verse sets, eg: 2-7 or family-books")
 	flags.Parse(os.Args[2:])
 
 	if dataDir == "" {
 		slog.Error("--data-dir not provided, stopping")
 		os.Exit(1)
 	}
+	if scripture != "" {
+		runScriptureStats(dataDir, scripture, scope)
+		return
+	}
 
 	if store == "bleve" {
 		dbPath := path.Join(dataDir, "docstore.bleve")
@@ -315,3 +357,194 @@ func runStats() {
 		os.Exit(1)
 	}
 }
+
+// runScriptureStats prints the excerpt, word and lemma counts of a scripture, for
+// the given scope or else for the whole scripture and each of its verse sets.
+func runScriptureStats(dataDir, scriptureName, scope string) {
+	conf := readConfig(dataDir)
+	scri := conf.GetScriptureByName(scriptureName)
+	if scri == nil {
+		slog.Error("unknown scripture", "scripture", scriptureName)
+		os.Exit(1)
+	}
+
+	db, err := docstore.NewSQLiteDB(dataDir, true)
+	if err != nil {
+		slog.Error("error while initializing SQLite DB", "err", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+	store := excerpts.NewSQLiteExcerptStore(db, conf)
+
+	scopes := []string{scope}
+	if scope == "" {
+		for _, set := range scri.VerseSets {
+			scopes = append(scopes, set.Name)
+		}
+	}
+
+	fmt.Println("scope\texcerpts\twords\tlemmas")
+	for _, sc := range scopes {
+		ranges, err := excerpts.ParseScope(sc, []config.ScriptureDefn{*scri})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		stats, err := store.Stats(context.Background(), scri.Name, ranges)
+		if err != nil {
+			slog.Error("error getting statistics", "scope", sc, "err", err)
+			os.Exit(1)
+		}
+		if sc == "" {
+			sc = "*"
+		}
+		fmt.Printf("%s\t%d\t%d\t%d\n", sc, stats.Excerpts, stats.Words, stats.Lemmas)
+	}
+}
+
+func runCQL() {
+	flags := pflag.NewFlagSet("cql", pflag.ExitOnError)
+	var dataDir, tl string
+	var scriptures []string
+	flags.StringVarP(&dataDir, "data-dir", "d", "",
+		"data directory to read config.json and the SQLite DB")
+	flags.StringSliceVarP(&scriptures, "scriptures", "s", nil, "scriptures to search (default: all)")
+	flags.StringVar(&tl, "tl", "iast", "transliteration scheme of sanskrit values in the pattern")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dhee cql [options] <pattern>")
+		fmt.Fprintln(os.Stderr, `Example: dhee cql -d data '[lemma="indra"][case="GEN"]'`)
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[2:])
+
+	if dataDir == "" || flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(1)
+	}
+	conf := readConfig(dataDir)
+	if len(scriptures) == 0 {
+		for _, scri := range conf.Scriptures {
+			scriptures = append(scriptures, scri.Name)
+		}
+	}
+
+	db, err := docstore.NewSQLiteDB(dataDir, true)
+	if err != nil {
+		slog.Error("error while initializing SQLite DB", "err", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	transliterator, err := transliteration.NewTransliterator(transliteration.TlOptions{})
+	if err != nil {
+		slog.Error("error while initializing transliterator", "err", err)
+		os.Exit(1)
+	}
+	es := excerpts.NewExcerptService(
+		dictionary.NewSQLiteDictStore(db, conf),
+		excerpts.NewSQLiteExcerptStore(db, conf),
+		conf, transliterator,
+	)
+
+	// one line per excerpt: scripture, readable index and the matched surfaces
+	search := excerpts.SearchParams{
+		Scriptures: scriptures,
+		Mode:       common.SearchCQL,
+		Q:          flags.Arg(0),
+		Tl:         tl,
+	}
+	total := 0
+	for page := 1; page <= common.MaxPage; page++ {
+		search.Page = page
+		results, err := es.Search(context.Background(), search)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, e := range results.Excerpts {
+			surfaces := make([]string, 0, len(e.Tokens))
+			for _, t := range e.Tokens {
+				surfaces = append(surfaces, e.Excerpt.Glossings[t.Line][t.Position].Surface)
+			}
+			fmt.Printf("%s\t%s\t%s\n", e.Excerpt.Scripture, e.Excerpt.ReadableIndex, strings.Join(surfaces, " "))
+		}
+		total = results.Pagination.Total
+		if !results.Pagination.HasNext() {
+			break
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d matching excerpts\n", total)
+}
+
+func runExport() {
+	flags := pflag.NewFlagSet("export", pflag.ExitOnError)
+	var dataDir, scriptureName, path, format, fields, output string
+	flags.StringVarP(&dataDir, "data-dir", "d", "",
+		"data directory to read config.json and the SQLite DB")
+	flags.StringVarP(&scriptureName, "scripture", "s", "", "scripture to export")
+	flags.StringVarP(&path, "path", "p", "", "comma separated paths, ranges or verse sets, eg: 1.1-1.3")
+	flags.StringVarP(&format, "format", "f", "text", "output format: text, csv, markdown or tei")
+	flags.StringVar(&fields, "fields", "",
+		"comma separated fields: source, roman, padapatha, glossings or auxiliary names (default: source, roman and the translation)")
+	flags.StringVarP(&output, "output", "o", "", "output file (default: standard output)")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: dhee export [options]")
+		fmt.Fprintln(os.Stderr, "Example: dhee export -d data -s rigveda -p 1.1 -f markdown --fields roman,griffith")
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[2:])
+
+	if dataDir == "" || scriptureName == "" || path == "" {
+		flags.Usage()
+		os.Exit(1)
+	}
+	conf := readConfig(dataDir)
+	scri := conf.GetScriptureByName(scriptureName)
+	if scri == nil {
+		slog.Error("unknown scripture", "scripture", scriptureName)
+		os.Exit(1)
+	}
+	opts, err := excerpts.ParseExportOptions(format, fields, scri)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	db, err := docstore.NewSQLiteDB(dataDir, true)
+	if err != nil {
+		slog.Error("error while initializing SQLite DB", "err", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	transliterator, err := transliteration.NewTransliterator(transliteration.TlOptions{})
+	if err != nil {
+		slog.Error("error while initializing transliterator", "err", err)
+		os.Exit(1)
+	}
+	es := excerpts.NewExcerptService(
+		dictionary.NewSQLiteDictStore(db, conf),
+		excerpts.NewSQLiteExcerptStore(db, conf),
+		conf, transliterator,
+	)
+
+	_, results, err := es.Export(context.Background(), scriptureName, path, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	out := os.Stdout
+	if output != "" {
+		out, err = os.Create(output)
+		if err != nil {
+			slog.Error("error creating output file", "file", output, "err", err)
+			os.Exit(1)
+		}
+		defer out.Close()
+	}
+	if err := excerpts.WriteExport(out, scri, results, opts); err != nil {
+		slog.Error("error writing export", "err", err)
+		os.Exit(1)
+	}
+}